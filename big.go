@@ -47,7 +47,8 @@ type Big struct {
 	Context Context
 
 	// unscaled is only used if the decimal is too large to fit
-	// in compact.
+	// in compact. Arithmetic on values with 38 or fewer digits
+	// treats it as a two-word compact value; see compact128.go.
 	unscaled big.Int
 
 	// compact is use if the value fits into an uint64. The scale
@@ -73,7 +74,9 @@ var (
 	_ fmt.Formatter            = (*Big)(nil)
 	_ fmt.Scanner              = (*Big)(nil)
 	_ fmt.Stringer             = (*Big)(nil)
+	_ json.Marshaler           = (*Big)(nil)
 	_ json.Unmarshaler         = (*Big)(nil)
+	_ encoding.TextMarshaler   = (*Big)(nil)
 	_ encoding.TextUnmarshaler = (*Big)(nil)
 	_ decomposer               = (*Big)(nil)
 )
@@ -137,6 +140,7 @@ type Payload uint64
 
 //go:generate stringer -type Payload -linecomment
 
+// New payloads go at the end so that existing values never change.
 const (
 	absvalue       Payload = iota + 1 // absolute value of NaN
 	acos                              // acos with NaN as an operand
@@ -178,6 +182,73 @@ const (
 	sin                               // sin with NaN as an operand
 	subinfinf                         // subtraction of infinities with opposing signs
 	subtraction                       // subtraction with NaN as an operand
+	logb                              // logb with NaN as an operand
+	logical                           // logical operation with a non-logical operand
+	nexttoward                        // next-toward with NaN as an operand
+	rotation                          // rotation with NaN as an operand
+	rotinvalid                        // rotation by an invalid number of digits
+	roundtoint                        // round-to-integral with NaN as an operand
+	scaleb                            // scaleb with NaN as an operand
+	scalebinvalid                     // scaleb with an invalid scale
+	shifting                          // shift with NaN as an operand
+	shiftinvalid                      // shift by an invalid number of digits
+	dotlen                            // dot product of vectors with different lengths
+	dotproduct                        // dot product with NaN as an operand
+	mean                              // mean with NaN as an operand
+	meanempty                         // mean of an empty set
+	summation                         // summation with NaN as an operand
+	variance                          // variance with NaN as an operand
+	varinf                            // variance of an infinity
+	varsize                           // variance of too few values
+	acosh                             // acosh with NaN as an operand
+	acoshlt1                          // acosh of a value less than one
+	asinh                             // asinh with NaN as an operand
+	atanh                             // atanh with NaN as an operand
+	atanhgt1                          // atanh of a value with a magnitude greater than one
+	cosh                              // cosh with NaN as an operand
+	sinh                              // sinh with NaN as an operand
+	tanh                              // tanh with NaN as an operand
+	cbrt                              // cbrt with NaN as an operand
+	log2                              // log2 with NaN as an operand
+	logbase                           // logarithm with NaN as an operand
+	logbaseinvalid                    // logarithm with an invalid base
+	root                              // root with NaN as an operand
+	rootinvalid                       // root with a non-positive degree
+	rootneg                           // even root of a negative number
+	maxNaN                            // max with NaN as an operand
+	minNaN                            // min with NaN as an operand
+	canceled                          // operation was canceled by its context.Context
+	storage                           // result or intermediate value exceeds MaxDigits or MaxMemory
+	beta                              // beta with NaN as an operand
+	betainvalid                       // beta of a non-positive integer or negative infinity
+	binomial                          // binomial coefficient with NaN as an operand
+	binominvalid                      // binomial coefficient of a non-integer
+	erf                               // erf with NaN as an operand
+	erfc                              // erfc with NaN as an operand
+	factinvalid                       // factorial of a negative number or non-integer
+	factorial                         // factorial with NaN as an operand
+	gamma                             // gamma with NaN as an operand
+	gammainvalid                      // gamma of a negative integer or negative infinity
+	lgamma                            // log gamma with NaN as an operand
+	zeta                              // zeta with NaN as an operand
+	zetaneginf                        // zeta of negative infinity
+	dim                               // dim with NaN as an operand
+	expm1                             // expm1 with NaN as an operand
+	frexp                             // frexp with NaN as an operand
+	ldexp                             // ldexp with NaN as an operand
+	log1p                             // log1p with NaN as an operand
+	log1pinvalid                      // log1p of a value less than negative one
+	modf                              // modf with NaN as an operand
+	modfinf                           // fractional part of an infinity
+	cot                               // cot with NaN as an operand
+	csc                               // csc with NaN as an operand
+	sec                               // sec with NaN as an operand
+	tan                               // tan with NaN as an operand
+	triginf                           // trigonometric function of an infinity
+	interval                          // interval arithmetic with NaN as an operand
+	intervaldomain                    // interval entirely outside of a function's domain
+	intervalorder                     // interval with a lower bound greater than its upper bound
+	logneg                            // logarithm of a negative number
 )
 
 // An ErrNaN is used when a decimal operation would lead to a NaN under IEEE-754
@@ -313,6 +384,28 @@ func (z *Big) Add(x, y *Big) *Big {
 	return z.Context.Add(z, x, y)
 }
 
+// And sets z to the digit-wise logical AND of x and y and
+// returns z. See Context.And for more details.
+func (z *Big) And(x, y *Big) *Big {
+	return z.Context.And(z, x, y)
+}
+
+// AppendText implements encoding.TextAppender. It appends the
+// same text as MarshalText to b and returns the extended buffer.
+// It does not allocate if b has enough capacity and x's
+// coefficient has at most 76 digits.
+func (x *Big) AppendText(b []byte) ([]byte, error) {
+	if x == nil {
+		return append(b, "<nil>"...), nil
+	}
+	if debug {
+		x.validate()
+	}
+	f := formatter{b: b, prec: x.Precision(), width: noWidth}
+	f.format(x, normal, sciE[x.Context.OperatingMode])
+	return f.b, nil
+}
+
 // Class returns the "class" of x, which is one of the following:
 //
 //    sNaN
@@ -394,6 +487,12 @@ func (x *Big) CmpAbs(y *Big) int {
 	return cmp(x, y, true)
 }
 
+// CmpSignal sets z to the result of comparing x and y and
+// returns z. See Context.CmpSignal for more details.
+func (z *Big) CmpSignal(x, y *Big) *Big {
+	return z.Context.CmpSignal(z, x, y)
+}
+
 func cmpInt(x *Big, y int64) int {
 	switch {
 	case x.Signbit() && y >= 0:
@@ -434,6 +533,10 @@ func cmpAbsInt(x *Big, y int64) int {
 
 	// The length of the integral parts match. Rescale x, then
 	// compare straight across.
+	if !x.isCompact() {
+		var t Big
+		return cmp(x, t.SetUint64(u), true)
+	}
 	t, ok := scalex(x.compact, x.exp)
 	if !ok {
 		if x.exp > 0 {
@@ -580,18 +683,7 @@ func (x *Big) CmpTotal(y *Big) int {
 		x.validate()
 		y.validate()
 	}
-	xs := x.ordTotal(false)
-	ys := y.ordTotal(false)
-	if xs != ys {
-		if xs > ys {
-			return +1
-		}
-		return -1
-	}
-	if xs != 0 {
-		return 0
-	}
-	return x.Cmp(y)
+	return x.cmpTotal(y, false)
 }
 
 // CmpTotalAbs is like CmpTotal but instead compares the absolute
@@ -601,8 +693,13 @@ func (x *Big) CmpTotalAbs(y *Big) int {
 		x.validate()
 		y.validate()
 	}
-	xs := x.ordTotal(true)
-	ys := y.ordTotal(true)
+	return x.cmpTotal(y, true)
+}
+
+// cmpTotal implements CmpTotal and CmpTotalAbs.
+func (x *Big) cmpTotal(y *Big, abs bool) int {
+	xs := x.ordTotal(abs)
+	ys := y.ordTotal(abs)
 	if xs != ys {
 		if xs > ys {
 			return +1
@@ -610,9 +707,46 @@ func (x *Big) CmpTotalAbs(y *Big) int {
 		return -1
 	}
 	if xs != 0 {
+		// NaNs of the same kind are ordered by their payloads.
+		r := 0
+		if x.compact < y.compact {
+			r = -1
+		} else if x.compact > y.compact {
+			r = +1
+		}
+		if xs < 0 {
+			r = -r
+		}
+		return r
+	}
+
+	var r int
+	if abs {
+		r = x.CmpAbs(y)
+	} else {
+		r = x.Cmp(y)
+	}
+	if r != 0 {
+		return r
+	}
+	neg := false
+	if !abs {
+		if xn, yn := x.Signbit(), y.Signbit(); xn != yn {
+			if xn {
+				return -1
+			}
+			return +1
+		}
+		neg = x.Signbit()
+	}
+	// Equal finite values are ordered by their exponents.
+	if x.IsInf(0) || x.exp == y.exp {
 		return 0
 	}
-	return x.CmpAbs(y)
+	if (x.exp < y.exp) != neg {
+		return -1
+	}
+	return +1
 }
 
 func (x *Big) ordTotal(abs bool) (r int) {
@@ -791,8 +925,11 @@ func (x *Big) Float(z *big.Float) *big.Float {
 // 	%v: same as %s
 // 	%e: -d.dddd±edd
 // 	%E: -d.dddd±Edd
-// 	%f: -dddd.dd
+// 	%f: -dddd.dd, or same as %e if writing every digit would exceed
+// 	    the MaxDigits or MaxMemory of x's Context
 // 	%g: same as %f
+// 	%n: -ddd.dddd±edd or -dddd.dd, depending on x, where the exponent
+// 	    is a multiple of three
 //
 // While width is honored in the same manner as the fmt package (the minimum
 // width of the formatted number), precision is the number of significant digits
@@ -831,30 +968,13 @@ func (x *Big) Format(s fmt.State, c rune) {
 		e       = sciE[x.Context.OperatingMode]
 	)
 
-	// If we need to left pad then we need to first write our
-	// string into an
-	// empty buffer.
-	tmpbuf := lpZero || lpSpace
-	if tmpbuf {
-		b := new(strings.Builder)
-		b.Grow(x.Precision())
-		f.w = b
-	} else {
-		f.w = stateWrapper{s}
-	}
-
 	if plus {
 		f.sign = '+'
 	} else if space {
 		f.sign = ' '
 	}
 
-	// noE is a placeholder for formats that do not use scientific notation
-	// and don't require 'e' or 'E'
-	const noE = 0
 	switch c {
-	case 's', 'd':
-		f.format(x, normal, e)
 	case 'q':
 		// The fmt package's docs specify that the '+' flag
 		// "guarantee[s] ASCII-only output for %q (%+q)"
@@ -866,31 +986,9 @@ func (x *Big) Format(s fmt.State, c rune) {
 		if hash {
 			quote = '`'
 		}
-		f.WriteByte(quote)
+		f.b = append(f.b, quote)
 		f.format(x, normal, e)
-		f.WriteByte(quote)
-	case 'e', 'E':
-		f.format(x, sci, byte(c))
-	case 'f', 'F':
-		if !hasPrec {
-			prec = 0
-		} else {
-			// %f's precision means "number of digits after the radix"
-			if x.exp > 0 {
-				f.prec += (x.exp + x.Precision())
-			} else {
-				if adj := x.exp + x.Precision(); adj > -f.prec {
-					f.prec += adj
-				} else {
-					f.prec = -f.prec
-				}
-			}
-		}
-
-		f.format(x, plain, noE)
-	case 'g', 'G':
-		// %g's precision means "number of significant digits"
-		f.format(x, plain, noE)
+		f.b = append(f.b, quote)
 
 	// Make sure we return from the following two cases.
 	case 'v':
@@ -927,25 +1025,29 @@ func (x *Big) Format(s fmt.State, c rune) {
 		fmt.Fprintf(s, "%"+specs+"v", (*Big)(x))
 		return
 	default:
-		fmt.Fprintf(s, "%%!%c(*Big=%s)", c, x.String())
-		return
+		if c > 0x7f || !f.formatVerb(x, byte(c), hasPrec) {
+			fmt.Fprintf(s, "%%!%c(*Big=%s)", c, x.String())
+			return
+		}
 	}
 
 	// Need padding out to width.
-	if f.n < int64(width) {
-		switch pad := int64(width) - f.n; {
-		case dash:
-			io.CopyN(s, spaceReader{}, pad)
-		case lpZero:
-			io.CopyN(s, zeroReader{}, pad)
-		case lpSpace:
-			io.CopyN(s, spaceReader{}, pad)
-		}
+	var pad int64
+	if n := int64(len(f.b)); n < int64(width) {
+		pad = int64(width) - n
 	}
-
-	if tmpbuf {
-		// fmt's internal state type implements stringWriter I think.
-		io.WriteString(s, f.w.(*strings.Builder).String())
+	switch {
+	case dash:
+		s.Write(f.b)
+		io.CopyN(s, spaceReader{}, pad)
+	case lpZero:
+		io.CopyN(s, zeroReader{}, pad)
+		s.Write(f.b)
+	case lpSpace:
+		io.CopyN(s, spaceReader{}, pad)
+		s.Write(f.b)
+	default:
+		s.Write(f.b)
 	}
 }
 
@@ -957,7 +1059,10 @@ func (z *Big) FMA(x, y, u *Big) *Big {
 // Int sets z, which may be nil, to x, truncating the fractional
 // portion (if any) and returns z.
 //
-// If x is an infinity or a NaN value the result is undefined.
+// If x is an infinity or a NaN value the result is undefined. If
+// the integer part of x has more digits than the MaxDigits or
+// MaxMemory of x's Context allow, z is set to zero and
+// InsufficientStorage is raised on x's Context.
 func (x *Big) Int(z *big.Int) *big.Int {
 	if debug {
 		x.validate()
@@ -970,6 +1075,10 @@ func (x *Big) Int(z *big.Int) *big.Int {
 	if !x.IsFinite() {
 		return z
 	}
+	if x.exp > 0 && x.Context.tooLarge(int64(x.Precision())+int64(x.exp)) {
+		x.Context.Conditions |= InsufficientStorage
+		return z.SetUint64(0)
+	}
 
 	if x.isCompact() {
 		z.SetUint64(x.compact)
@@ -985,6 +1094,12 @@ func (x *Big) Int(z *big.Int) *big.Int {
 	return bigScalex(z, z, x.exp)
 }
 
+// Invert sets z to the digit-wise logical inversion of x and
+// returns z. See Context.Invert for more details.
+func (z *Big) Invert(x *Big) *Big {
+	return z.Context.Invert(z, x)
+}
+
 // Int64 returns x as an int64, truncating towards zero.
 //
 // The bool result indicates whether the conversion to an int64
@@ -1128,6 +1243,12 @@ func (x *Big) IsInt() bool {
 	return exp >= 0
 }
 
+// Logb sets z to the adjusted exponent of x and returns z. See
+// Context.Logb for more details.
+func (z *Big) Logb(x *Big) *Big {
+	return z.Context.Logb(z, x)
+}
+
 // Mantissa returns the mantissa of x and reports whether the
 // mantissa fits into a uint64 and x is finite.
 //
@@ -1137,6 +1258,61 @@ func (x *Big) Mantissa() (uint64, bool) {
 	return x.compact, x.IsFinite() && x.compact != c.Inflated
 }
 
+// MarshalJSON implements json.Marshaler.
+//
+// x is encoded as a JSON string. To encode finite values as JSON
+// numbers, use JSONNumber. A nil x is encoded as null.
+func (x *Big) MarshalJSON() ([]byte, error) {
+	return x.marshalJSON(false)
+}
+
+// marshalJSON encodes x as JSON. If numbers is true, finite values
+// are encoded as JSON numbers instead of JSON strings.
+func (x *Big) marshalJSON(numbers bool) ([]byte, error) {
+	if x == nil {
+		return []byte("null"), nil
+	}
+	text, err := x.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	if numbers && x.IsFinite() {
+		return text, nil
+	}
+	b := make([]byte, 0, len(text)+2)
+	b = append(b, '"')
+	b = append(b, text...)
+	return append(b, '"'), nil
+}
+
+// JSONNumber is a Big that is encoded as a JSON number instead of
+// a JSON string. For example, to encode x as a number:
+//
+// 	json.Marshal((*decimal.JSONNumber)(x))
+//
+// or, to encode a struct field as a number:
+//
+// 	type Item struct {
+// 		Price decimal.JSONNumber
+// 	}
+//
+// Infinities and NaN values are still encoded as JSON strings
+// since JSON numbers cannot represent them.
+type JSONNumber Big
+
+// MarshalJSON implements json.Marshaler. It has a value receiver
+// so that JSONNumber fields are encoded as numbers even if the
+// struct containing them is not addressable.
+func (x JSONNumber) MarshalJSON() ([]byte, error) {
+	return (*Big)(&x).marshalJSON(true)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the same
+// input as Big.UnmarshalJSON.
+func (z *JSONNumber) UnmarshalJSON(data []byte) error {
+	return (*Big)(z).UnmarshalJSON(data)
+}
+
 // MarshalText implements encoding.TextMarshaler.
 func (x *Big) MarshalText() ([]byte, error) {
 	if debug {
@@ -1145,14 +1321,7 @@ func (x *Big) MarshalText() ([]byte, error) {
 	if x == nil {
 		return []byte("<nil>"), nil
 	}
-	var (
-		b = new(bytes.Buffer)
-		f = formatter{w: b, prec: x.Precision(), width: noWidth}
-		e = sciE[x.Context.OperatingMode]
-	)
-	b.Grow(x.Precision())
-	f.format(x, normal, e)
-	return b.Bytes(), nil
+	return x.AppendText(make([]byte, 0, x.Precision()))
 }
 
 // Max returns the greater of the provided values.
@@ -1228,6 +1397,13 @@ func (z *Big) Neg(x *Big) *Big {
 	return z.Context.Neg(z, x)
 }
 
+// NextToward sets z to the representable number closest to x in
+// the direction of y and returns z. See Context.NextToward for
+// more details.
+func (z *Big) NextToward(x, y *Big) *Big {
+	return z.Context.NextToward(z, x, y)
+}
+
 // New creates a new Big decimal with the given value and scale.
 //
 // For example:
@@ -1242,6 +1418,12 @@ func New(value int64, scale int) *Big {
 	return new(Big).SetMantScale(value, scale)
 }
 
+// Or sets z to the digit-wise logical OR of x and y and returns
+// z. See Context.Or for more details.
+func (z *Big) Or(x, y *Big) *Big {
+	return z.Context.Or(z, x, y)
+}
+
 // Payload returns the payload of x, provided x is a NaN value.
 //
 // If x is not a NaN value, the result is undefined.
@@ -1367,6 +1549,13 @@ func (z *Big) Rem(x, y *Big) *Big {
 	return z.Context.Rem(z, x, y)
 }
 
+// RemNear sets z to the remainder x - y*n, where n is the integer
+// nearest to x / y, and returns z. See Context.RemNear for more
+// details.
+func (z *Big) RemNear(x, y *Big) *Big {
+	return z.Context.RemNear(z, x, y)
+}
+
 // Round rounds z down to n digits of precision and returns z.
 //
 // The result is undefined if z is not finite. No rounding will
@@ -1383,9 +1572,25 @@ func (z *Big) RoundToInt() *Big {
 	return z.Context.RoundToInt(z)
 }
 
+// RoundToIntegral is like RoundToInt, but it does not raise the
+// Inexact or Rounded conditions.
+func (z *Big) RoundToIntegral() *Big {
+	return z.Context.RoundToIntegral(z)
+}
+
+// Rotate sets z to x with the digits of its coefficient rotated
+// by y places and returns z. See Context.Rotate for more details.
+func (z *Big) Rotate(x, y *Big) *Big {
+	return z.Context.Rotate(z, x, y)
+}
+
 // SameQuantum reports whether x and y have the same exponent
-// (scale).
+// (scale). Any two NaNs or any two infinities have the same
+// quantum, but neither has the same quantum as a finite number.
 func (x *Big) SameQuantum(y *Big) bool {
+	if x.isSpecial() || y.isSpecial() {
+		return x.IsNaN(0) && y.IsNaN(0) || x.IsInf(0) && y.IsInf(0)
+	}
 	return x.Scale() == y.Scale()
 }
 
@@ -1404,6 +1609,12 @@ func (x *Big) Scale() int {
 	return -x.exp
 }
 
+// Scaleb sets z to x * 10**y and returns z. See Context.Scaleb
+// for more details.
+func (z *Big) Scaleb(x, y *Big) *Big {
+	return z.Context.Scaleb(z, x, y)
+}
+
 // Scan implements fmt.Scanner.
 func (z *Big) Scan(state fmt.ScanState, verb rune) error {
 	return z.scan(byteReader{state})
@@ -1609,6 +1820,16 @@ func (z *Big) setNaN(c Condition, f form, p Payload) *Big {
 	return z
 }
 
+// checkDone sets z to NaN and raises Canceled if c is done.
+// It reports whether z was set.
+func (z *Big) checkDone(c Context) bool {
+	if !c.done() {
+		return false
+	}
+	z.setNaN(Canceled, qnan, canceled)
+	return true
+}
+
 // SetNaN sets z to a signaling NaN if signal is true or quiet
 // NaN otherwise and returns z.
 //
@@ -1630,7 +1851,14 @@ func (z *Big) SetRat(x *big.Rat) *Big {
 }
 
 // SetScale sets z's scale to scale and returns z.
+//
+// If z's coefficient has more digits than z's Context allows (see
+// MaxDigits and MaxMemory), z is set to NaN and InsufficientStorage
+// is raised.
 func (z *Big) SetScale(scale int) *Big {
+	if z.IsFinite() && z.checkStorage(z.Context, int64(z.Precision())) {
+		return z
+	}
 	z.exp = -scale
 	return z
 }
@@ -1700,6 +1928,12 @@ func (z *Big) SetUint64(x uint64) *Big {
 	return z
 }
 
+// Shift sets z to x with the digits of its coefficient shifted
+// by y places and returns z. See Context.Shift for more details.
+func (z *Big) Shift(x, y *Big) *Big {
+	return z.Context.Shift(z, x, y)
+}
+
 // Sign returns:
 //
 //    -1 if x <  0
@@ -1738,17 +1972,37 @@ func (x *Big) Signbit() bool {
 //
 // Special cases depend on the OperatingMode.
 func (x *Big) String() string {
+	return x.toString(normal)
+}
+
+// EngString returns the engineering string representation of x.
+//
+// It's the same as String, except that exponential notation uses
+// an exponent that is a multiple of three, as described in
+// http://speleotrove.com/decimal/daconvs.html#reftoeng. For example,
+// 1.23E+7 is "12.3E+6" and 0E+1 is "0.00E+3". The result is exact,
+// but it may have up to two more trailing zeros than x.
+//
+// It's equivalent to the %n verb discussed in the Format method's
+// documentation.
+func (x *Big) EngString() string {
+	return x.toString(eng)
+}
+
+// toString returns x formatted with format.
+func (x *Big) toString(format format) string {
 	if x == nil {
 		return "<nil>"
 	}
+	// Most decimals fit into buf, in which case the only allocation
+	// is the string itself.
 	var (
-		b = new(strings.Builder)
-		f = formatter{w: b, prec: x.Precision(), width: noWidth}
-		e = sciE[x.Context.OperatingMode]
+		buf [64]byte
+		f   = formatter{b: buf[:0], prec: x.Precision(), width: noWidth}
+		e   = sciE[x.Context.OperatingMode]
 	)
-	b.Grow(x.Precision())
-	f.format(x, normal, e)
-	return b.String()
+	f.format(x, format, e)
+	return string(f.b)
 }
 
 // Sub sets z to x - y and returns z.
@@ -1757,7 +2011,13 @@ func (z *Big) Sub(x, y *Big) *Big {
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
+//
+// data may be a JSON number or a JSON string. As with other
+// json.Unmarshalers, null is a no-op.
 func (z *Big) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
 	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
 		data = data[1 : len(data)-1]
 	}
@@ -1769,6 +2029,12 @@ func (z *Big) UnmarshalText(data []byte) error {
 	return z.scan(bytes.NewReader(data))
 }
 
+// Xor sets z to the digit-wise logical exclusive OR of x and y
+// and returns z. See Context.Xor for more details.
+func (z *Big) Xor(x, y *Big) *Big {
+	return z.Context.Xor(z, x, y)
+}
+
 // validate ensures x's internal state is correct. There's no need for it to
 // have good performance since it's for debug == true only.
 func (x *Big) validate() {
@@ -1,9 +1,11 @@
 package decimal
 
 import (
+	"context"
 	"math"
 	"math/big"
 	"math/bits"
+	"strconv"
 
 	"github.com/ericlagergren/decimal/internal/arith"
 	cst "github.com/ericlagergren/decimal/internal/c"
@@ -76,8 +78,17 @@ func (c Context) add(z, x *Big, xform form, y *Big, yform form) form {
 		return sign
 	}
 
+	// Aligning the exponents shifts hi by hi.exp - lo.exp digits.
+	if z.checkStorage(c, int64(hi.Precision())+int64(hi.exp)-int64(lo.exp)) {
+		return z.form
+	}
+
 	var sign form
-	if hi.isCompact() {
+	if hi.isCompact() && lo.isCompact() && hi.Precision()+hi.exp-lo.exp < arith.MaxLength {
+		sign = c.addCompact(z, hi.compact, hisign, lo.compact, losign, uint64(hi.exp-lo.exp))
+	} else if s, ok := c.addWords(z, hi, hisign, lo, losign); ok {
+		sign = s
+	} else if hi.isCompact() {
 		if lo.isCompact() {
 			sign = c.addCompact(z, hi.compact, hisign, lo.compact, losign, uint64(hi.exp-lo.exp))
 		} else {
@@ -351,6 +362,76 @@ func (c Context) Acos(z, x *Big) *Big {
 	return c.finish(z)
 }
 
+// Acosh returns the inverse hyperbolic cosine of x.
+//
+// Range:
+//     Input: x >= 1
+//     Output: Acosh(x) >= 0
+//
+// Special cases:
+//     Acosh(NaN)  = NaN
+//     Acosh(+Inf) = +Inf
+//     Acosh(x)    = NaN if x < 1
+//     Acosh(1)    = 0
+func (c Context) Acosh(z, x *Big) *Big {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, x, acosh) {
+		return z
+	}
+
+	if x.Signbit() || x.Cmp(one.get()) < 0 {
+		return z.setNaN(InvalidOperation, qnan, acoshlt1)
+	}
+	if x.IsInf(+1) {
+		return z.SetInf(false)
+	}
+
+	if x.Cmp(two.get()) >= 0 {
+		return c.correctlyRound(z, 0, func(ctx Context, t *Big) *Big {
+			if 2*x.adjusted() > ctx.Precision {
+				// Acosh(x) = ln(2x) - 1/(4x**2) - ..., and the
+				// remaining terms are too small to matter.
+				return ctx.Log(t, ctx.Mul(t, x, two.get()))
+			}
+			// Acosh(x) = ln(x + sqrt(x**2 - 1))
+			ctx.Mul(t, x, x)
+			ctx.Sub(t, t, one.get())
+			ctx.Sqrt(t, t)
+			ctx.Add(t, t, x)
+			return ctx.Log(t, t)
+		})
+	}
+
+	// x - 1 is exact.
+	u := getDec(c)
+	defer putDec(u)
+	Context{Precision: UnlimitedPrecision}.Sub(u, x, one.get())
+	if u.isZero() {
+		// Acosh(1) = 0
+		return z.SetUint64(0)
+	}
+
+	// Near 1 the argument to ln is close to 1.
+	extra := 0
+	if adj := u.adjusted(); adj < 0 {
+		extra = (1 - adj) / 2
+	}
+	return c.correctlyRound(z, extra, func(ctx Context, t *Big) *Big {
+		// Acosh(1 + u) = ln(1 + u + sqrt(u*(u + 2)))
+		ctx.Add(t, u, two.get())
+		ctx.Mul(t, t, u)
+		ctx.Sqrt(t, t)
+		ctx.Add(t, t, u)
+		ctx.Add(t, t, one.get())
+		return ctx.Log(t, t)
+	})
+}
+
 // Asin returns the arcsine, in radians, of x.
 //
 // Range:
@@ -407,6 +488,60 @@ func (c Context) Asin(z, x *Big) *Big {
 	return c.finish(z)
 }
 
+// Asinh returns the inverse hyperbolic sine of x.
+//
+// Range:
+//     Input: all real numbers
+//     Output: all real numbers
+//
+// Special cases:
+//     Asinh(NaN)  = NaN
+//     Asinh(±Inf) = ±Inf
+//     Asinh(±0)   = ±0
+func (c Context) Asinh(z, x *Big) *Big {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, x, asinh) {
+		return z
+	}
+
+	if x.IsInf(0) {
+		return z.SetInf(x.Signbit())
+	}
+	if x.isZero() {
+		return z.setZero(x.form, 0)
+	}
+
+	// Asinh(x) = x - x**3/6 + ...
+	adj := x.adjusted()
+	if c.perturb(z, x, 3*(adj+1), x.Signbit()) {
+		return z
+	}
+
+	// For small x the argument to ln is close to 1.
+	return c.correctlyRound(z, max(-adj, 0), func(ctx Context, t *Big) *Big {
+		// Asinh(x) = sign(x) * ln(|x| + sqrt(x**2 + 1))
+		t.CopyAbs(x)
+		if 2*adj > ctx.Precision {
+			// Asinh(x) = ln(2|x|) + 1/(4x**2) - ..., and the
+			// remaining terms are too small to matter.
+			ctx.Mul(t, t, two.get())
+		} else {
+			u := getDec(ctx)
+			ctx.FMA(u, t, t, one.get())
+			ctx.Sqrt(u, u)
+			ctx.Add(t, t, u)
+			putDec(u)
+		}
+		ctx.Log(t, t)
+		return t.SetSignbit(x.Signbit())
+	})
+}
+
 // Atan returns the arctangent, in radians, of x.
 //
 // Range:
@@ -450,6 +585,9 @@ func (c Context) Atan(z, x *Big) *Big {
 		func(_ uint64) *Big { return one.get() },
 		ctx.getAtanQ(ySqPlus1),
 	)
+	if z.checkDone(c) {
+		return z
+	}
 
 	// undo the double angle part
 	tmp := ctx.Pow(ySq, two.get(), z.SetMantScale(int64(halved), 0)) // clobber ySq
@@ -471,6 +609,12 @@ func (c Context) Atan(z, x *Big) *Big {
 	return c.finish(z)
 }
 
+// AtanContext is like Atan, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) AtanContext(ctx context.Context, z, x *Big) *Big {
+	return c.withDone(ctx).Atan(z, x)
+}
+
 var sqrt3_3 = New(577350, 6) // sqrt(3) / 3
 
 func (c Context) prepAtan(z, x *Big) (y, ySq, ySqPlus1 *Big, segment, halved int) {
@@ -691,174 +835,234 @@ func (c Context) atan2Specials(z, x, y *Big) *Big {
 	panic("unreachable")
 }
 
-// Cos returns the cosine, in radians, of x.
+// Atanh returns the inverse hyperbolic tangent of x.
 //
 // Range:
-//     Input: all real numbers
-//     Output: -1 <= Cos(x) <= 1
+//     Input: -1 <= x <= 1
+//     Output: all real numbers
 //
 // Special cases:
-//		Cos(NaN)  = NaN
-//		Cos(±Inf) = NaN
-func (c Context) Cos(z, x *Big) *Big {
+//     Atanh(NaN) = NaN
+//     Atanh(x)   = NaN if x < -1 or x > 1
+//     Atanh(±1)  = ±Inf
+//     Atanh(±0)  = ±0
+//
+// Atanh(±1) also raises DivisionByZero.
+func (c Context) Atanh(z, x *Big) *Big {
 	if debug {
 		x.validate()
 	}
 	if z.invalidContext(c) {
 		return z
 	}
-	if z.checkNaNs(x, x, sin) {
+	if z.checkNaNs(x, x, atanh) {
 		return z
 	}
 
-	if x.IsInf(0) {
-		z.Context.Conditions |= InvalidOperation
-		return z.SetNaN(false)
+	cmp1 := x.CmpAbs(one.get())
+	if x.IsInf(0) || cmp1 > 0 {
+		return z.setNaN(InvalidOperation, qnan, atanhgt1)
 	}
-
-	ctx := c.dup()
-	ctx.Precision += defaultExtraPrecision
-
-	negXSq, halved, ok := ctx.prepCosine(z, x)
-	if !ok {
-		z.Context.Conditions |= InvalidOperation
-		return z.SetNaN(false)
+	if cmp1 == 0 {
+		z.Context.Conditions |= DivisionByZero
+		return z.SetInf(x.Signbit())
+	}
+	if x.isZero() {
+		return z.setZero(x.form, 0)
 	}
 
-	ctx.Precision += halved
-	z.Copy(BinarySplitDynamic(ctx,
-		func(_ uint64) *Big { return one.get() },
-		getCosineP(negXSq),
-		func(_ uint64) *Big { return one.get() },
-		ctx.getCosineQ(),
-	))
-
-	// now undo the half angle bit
-	for i := 0; i < halved; i++ {
-		ctx.Mul(z, z, z)
-		ctx.Mul(z, z, two.get())
-		ctx.Sub(z, z, one.get())
+	// Atanh(x) = x + x**3/3 + ...
+	adj := x.adjusted()
+	if c.perturb(z, x, 3*(adj+1), !x.Signbit()) {
+		return z
 	}
 
-	return c.finish(z)
+	// 1 - |x| is exact, which matters when |x| is close to 1.
+	u := getDec(c)
+	defer putDec(u)
+	Context{Precision: UnlimitedPrecision}.Sub(u, one.get(), u.CopyAbs(x))
+
+	// For small x the argument to ln is close to 1.
+	return c.correctlyRound(z, max(-adj, 0), func(ctx Context, t *Big) *Big {
+		// Atanh(x) = sign(x) * ln(1 + 2|x|/(1 - |x|)) / 2
+		t.CopyAbs(x)
+		ctx.Mul(t, t, two.get())
+		ctx.Quo(t, t, u)
+		ctx.Add(t, t, one.get())
+		ctx.Log(t, t)
+		ctx.Mul(t, t, ptFive.get())
+		return t.SetSignbit(x.Signbit())
+	})
 }
 
-func (c Context) prepCosine(z, x *Big) (*Big, int, bool) {
-	if z == x {
-		x = getDec(c).Copy(x)
-		defer putDec(x)
-	}
+// And sets z to the digit-wise logical AND of x and y and
+// returns z.
+//
+// Both x and y must be logical operands: finite, non-negative
+// integers with a scale of zero whose digits are all either 0 or
+// 1. Otherwise, z is set to NaN and InvalidOperation is raised.
+//
+// The operands are padded with leading zeros or truncated to the
+// Context's precision. If the precision is UnlimitedPrecision
+// the operands are padded to the length of the longer operand.
+func (c Context) And(z, x, y *Big) *Big {
+	return c.logical(z, x, y, func(a, b byte) byte { return a & b })
+}
 
-	var tmp Big
-	var twoPi Big
-
-	// For better results, we need to make sure the value we're
-	// working with a value is closer to zero.
-	c.Mul(&twoPi, c.pi(&twoPi), two.get()) // 2 * Pi
-	if x.CmpAbs(&twoPi) >= 0 {
-		// For cos to work correctly the input must be in (-2Pi,
-		// 2Pi).
-		c.Quo(&tmp, x, &twoPi)
-		v, ok := tmp.Int64()
-		if !ok {
-			return nil, 0, false
-		}
-		uv := arith.Abs(v)
+// logical sets z to the result of applying fn to each pair of
+// digits in the logical operands x and y, and returns z.
+//
+// The digits passed to fn are the ASCII characters '0' and '1'.
+func (c Context) logical(z, x, y *Big, fn func(a, b byte) byte) *Big {
+	if debug {
+		x.validate()
+		y.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
 
-		// Adjust so we have ceil(v/10) + ctx.Precision, but check for overflows.
-		// 1+((v-1)/10) will be wildly incorrect for v == 0, but x/y = 0 iff
-		// x = 0 and y != 0. In this case, -2pi <= x >= 2pi, so we're fine.
-		prec, carry := bits.Add64(1+((uv-1)/10), uint64(c.Precision), 0)
-		if carry != 0 || prec > arith.MaxInt {
-			return nil, 0, false
-		}
-		pctx := Context{Precision: int(prec)}
+	xd, ok := x.logicalDigits()
+	if !ok {
+		return z.setNaN(InvalidOperation, qnan, logical)
+	}
+	yd, ok := y.logicalDigits()
+	if !ok {
+		return z.setNaN(InvalidOperation, qnan, logical)
+	}
 
-		if uv <= math.MaxInt64/2 {
-			tmp.SetMantScale(v, 0)
-		}
+	n := c.precision()
+	if n == UnlimitedPrecision {
+		n = max(len(xd), len(yd))
+	}
+	xd = padDigits(xd, n)
+	yd = padDigits(yd, n)
+	for i := range xd {
+		xd[i] = fn(xd[i], yd[i])
+	}
 
-		pctx.Mul(&tmp, &twoPi, &tmp)
+	i := 0
+	for i < len(xd)-1 && xd[i] == '0' {
+		i++
+	}
+	z.unscaled.SetString(string(xd[i:]), 10)
+	z.exp = 0
+	z.form = finite
+	return z.norm()
+}
 
-		// so toRemove = 2*Pi*v so x - toRemove < 2*Pi
-		c.Sub(z, x, &tmp)
+// logicalDigits returns the digits of x's coefficient and reports
+// whether x is a logical operand.
+func (x *Big) logicalDigits() ([]byte, bool) {
+	if !x.IsFinite() || x.Signbit() || x.exp != 0 {
+		return nil, false
+	}
+	var digits []byte
+	if x.isCompact() {
+		digits = strconv.AppendUint(nil, x.compact, 10)
 	} else {
-		z.Copy(x)
+		digits = x.unscaled.Append(nil, 10)
+	}
+	for _, d := range digits {
+		if d != '0' && d != '1' {
+			return nil, false
+		}
 	}
+	return digits, true
+}
 
-	// add 1 to the precision for the up eventual squaring.
-	c.Precision++
+// padDigits left pads digits with zeros or truncates its most
+// significant digits so that it has exactly n digits.
+func padDigits(digits []byte, n int) []byte {
+	if len(digits) >= n {
+		return digits[len(digits)-n:]
+	}
+	p := make([]byte, n)
+	m := copy(p[n-len(digits):], digits)
+	for i := range p[:n-m] {
+		p[i] = '0'
+	}
+	return p
+}
 
-	// now preform the half angle.
-	// we'll repeat this up to log(precision)/log(2) and keep track
-	// since we'll be dividing x we need to give a bit more precision
-	// we'll be repeated applying the double angle formula
-	// we could use a higher angle formula but wouldn't buy us anything.
-	// Each time we half we'll have to increase the values precision by 1
-	// and since we'll dividing at most 11 time that means at most 11 digits
-	// but we'll figure out the minimum time we'll apply the double angle
-	// formula
+// Cos returns the cosine, in radians, of x.
+//
+// x is limited as described for Sin. Within that limit, the result
+// is correctly rounded.
+//
+// Range:
+//     Input: all real numbers
+//     Output: -1 <= Cos(x) <= 1
+//
+// Special cases:
+//		Cos(NaN)  = NaN
+//		Cos(±Inf) = NaN
+func (c Context) Cos(z, x *Big) *Big {
+	return c.trig(z, x, trigCos, radians, cos)
+}
 
-	// we'll we reduce until it's x <= p where p= 1/2^8 (approx 0.0039)
-	// we figure out the number of time to divide by solving for r
-	// in x/p = 2^r  so r = log(x/p)/log(2)
+// CosContext is like Cos, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) CosContext(ctx context.Context, z, x *Big) *Big {
+	return c.withDone(ctx).Cos(z, x)
+}
 
-	xf, _ := z.Float64()
-	// We only need to do the calculation if xf >= 0.0004. Anything below that
-	// and we're <= 0.
-	var halved int
-	if xf = math.Abs(xf); xf >= 0.0004 {
-		// Originally: ceil(log(xf/0.0048828125) / ln2)
-		halved = int(math.Ceil(1.4427*math.Log(xf) + 11))
-		// The general case is halved > 0, since we only get 0 if xf is very
-		// close to 0.0004.
-		if halved > 0 {
-			// Increase the precision based on the number of divides. Overflow is
-			// unlikely, but possible.
-			c.Precision += halved
-			if c.Precision <= halved {
-				return nil, 0, false
-			}
-			// The maximum value for halved will be 14, given
-			//     ceil(1.4427*log(2*pi)+11) = 14
-			c.Quo(z, z, tmp.SetUint64(1<<uint64(halved)))
-		}
+// Cosh returns the hyperbolic cosine of x.
+//
+// Range:
+//     Input: all real numbers
+//     Output: Cosh(x) >= 1
+//
+// Special cases:
+//     Cosh(NaN)  = NaN
+//     Cosh(±Inf) = +Inf
+//     Cosh(±0)   = 1
+//
+// If the result is too large for the Context, Cosh raises
+// Overflow.
+func (c Context) Cosh(z, x *Big) *Big {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, x, cosh) {
+		return z
 	}
 
-	c.Mul(z, z, z)
-	z.CopyNeg(z)
-	return z, halved, true
-}
+	if x.IsInf(0) {
+		return z.SetInf(false)
+	}
+	if x.isZero() {
+		return z.SetUint64(1)
+	}
 
-func getCosineP(negXSq *Big) func(n uint64) *Big {
-	return func(n uint64) *Big {
-		if n == 0 {
-			return one.get()
-		}
-		return negXSq
+	// Cosh(x) = 1 + x**2/2 + ...
+	if c.perturb(z, one.get(), 2*(x.adjusted()+1), true) {
+		return z
 	}
-}
 
-func (c Context) getCosineQ() func(n uint64) *Big {
-	var q, tmp Big
-	return func(n uint64) *Big {
-		// (0) = 1, q(n) = 2n(2n-1) for n > 0
-		if n == 0 {
-			return one.get()
+	return c.correctlyRound(z, 0, func(ctx Context, t *Big) *Big {
+		// Cosh(x) = (e**|x| + e**-|x|) / 2
+		ctx.Exp(t, t.CopyAbs(x))
+		if t.IsInf(0) {
+			return t
 		}
+		u := getDec(ctx)
+		ctx.Quo(u, one.get(), t)
+		ctx.Add(t, t, u)
+		putDec(u)
+		return ctx.Mul(t, t, ptFive.get())
+	})
+}
 
-		// most of the time n will be a small number so
-		// use the fastest method to calculate 2n(2n-1)
-		const cosine4NMaxN = 2147483648
-		if n < cosine4NMaxN {
-			// ((n*n) << 2) - (n << 1)
-			return q.SetUint64((2 * n) * (2*n - 1))
-		}
-		q.SetUint64(n)
-		c.Mul(&tmp, &q, two.get())
-		c.Mul(&q, &tmp, &tmp)
-		return c.Sub(&q, &q, &tmp)
-	}
+// Cbrt sets z to the cube root of x and returns z.
+//
+// Like Root, the result is exact if possible. Unlike Pow, the cube
+// root of a negative number is negative.
+func (c Context) Cbrt(z, x *Big) *Big {
+	return c.root(z, x, 3, cbrt)
 }
 
 // Ceil sets z to the least integer value greater than or equal
@@ -868,35 +1072,86 @@ func (c Context) Ceil(z, x *Big) *Big {
 	return c.Neg(z, c.Floor(z, z.CopyNeg(x)))
 }
 
+// CmpSignal sets z to the result of comparing x and y and
+// returns z. The result is
+//
+//   -1 if x <  y
+//    0 if x == y
+//   +1 if x >  y
+//
+// Unlike Cmp, if either x or y is a NaN value—quiet or
+// signaling—z is set to NaN and InvalidOperation is raised.
+func (c Context) CmpSignal(z, x, y *Big) *Big {
+	if debug {
+		x.validate()
+		y.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, y, comparison) {
+		// Quiet NaNs signal, too.
+		z.Context.Conditions |= InvalidOperation
+		return z
+	}
+	return z.SetMantScale(int64(cmp(x, y, false)), 0)
+}
+
+// Dim sets z to the maximum of x-y and 0 and returns z.
+//
+// Special cases:
+//     Dim(+Inf, +Inf) = NaN
+//     Dim(-Inf, -Inf) = NaN
+//     Dim(x, NaN) = Dim(NaN, x) = NaN
+func (c Context) Dim(z, x, y *Big) *Big {
+	if debug {
+		x.validate()
+		y.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, y, dim) {
+		return z
+	}
+	if x.IsInf(0) && y.IsInf(0) && x.Signbit() == y.Signbit() {
+		return z.setNaN(InvalidOperation, qnan, subinfinf)
+	}
+	if x.Cmp(y) <= 0 {
+		return z.SetUint64(0)
+	}
+	return c.Sub(z, x, y)
+}
+
 // E sets z to the mathematical constant e and returns z.
 func (c Context) E(z *Big) *Big {
 	if c.Precision <= constPrec {
 		return c.Set(z, _E.get())
 	}
 
-	var (
-		sum  = z.SetUint64(2)
-		fac  = new(Big).SetUint64(1)
-		term = new(Big)
-		prev = new(Big)
-	)
-
-	ctx := c
-	ctx.Precision += 5
-	for i := uint64(2); sum.Cmp(prev) != 0; i++ {
-		// Use term as our intermediate storage for our
-		// factorial. SetUint64 should be marginally faster than
-		// ctx.Add(incr, incr, one), but either the costly call
-		// to Quo makes it difficult to notice.
-		term.SetUint64(i)
-		ctx.Mul(fac, fac, term)
-		ctx.Quo(term, one.get(), fac)
-		prev.Copy(sum)
-		ctx.Add(sum, sum, term)
+	// e = sum_(n=0)^infinity 1/n!, so stop once n! has more
+	// digits than the working precision.
+	ctx := Context{
+		Precision: c.Precision + 16,
+		Options:   c.Options,
 	}
-	return ctx.Set(z, sum)
+	n := uint64(1)
+	for digits := 0.0; digits <= float64(ctx.Precision); n++ {
+		digits += math.Log10(float64(n))
+	}
+
+	unit := func(_ uint64) *Big { return one.get() }
+	BinarySplit(z, ctx, 0, n, unit, unit, unit, func(n uint64) *Big {
+		// q(0) = 1, q(n) = n
+		if n == 0 {
+			return one.get()
+		}
+		return new(Big).SetUint64(n)
+	})
+	return c.Set(z, z)
 }
 
+
 // Exp sets z to e**x and returns z.
 func (c Context) Exp(z, x *Big) *Big {
 	if debug {
@@ -924,6 +1179,9 @@ func (c Context) Exp(z, x *Big) *Big {
 	}
 
 	z = c.exp(z, x)
+	if z.checkDone(c) {
+		return z
+	}
 	if z.IsFinite() && z.Sign() != 0 && z.Precision() < c.Precision {
 		s := c.Precision - z.Precision()
 		c.shiftl(z, uint64(s))
@@ -932,6 +1190,12 @@ func (c Context) Exp(z, x *Big) *Big {
 	return c.finish(z)
 }
 
+// ExpContext is like Exp, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) ExpContext(ctx context.Context, z, x *Big) *Big {
+	return c.withDone(ctx).Exp(z, x)
+}
+
 func (c Context) exp(z, x *Big) *Big {
 	if getmsd(x, 1) == 0 {
 		return z.SetMantScale(1, 0)
@@ -1164,6 +1428,62 @@ func (e *expg) Term() Term {
 	return e.t
 }
 
+// Expm1 sets z to e**x - 1 and returns z.
+//
+// Unlike computing Exp(x) - 1, the result is correctly rounded
+// even if x is close to zero.
+//
+// Special cases:
+//     Expm1(NaN)  = NaN
+//     Expm1(+Inf) = +Inf
+//     Expm1(-Inf) = -1
+//     Expm1(±0)   = ±0
+func (c Context) Expm1(z, x *Big) *Big {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, x, expm1) {
+		return z
+	}
+
+	if x.IsInf(0) {
+		if x.IsInf(+1) {
+			return z.SetInf(false)
+		}
+		return z.SetMantScale(-1, 0)
+	}
+	if x.isZero() {
+		return z.Copy(x)
+	}
+
+	// Expm1(x) = x + x**2/2 + ... and e**x >= 1 + x.
+	if c.perturb(z, x, 2*(x.adjusted()+1), true) {
+		return z
+	}
+
+	// For a large, negative x, Expm1(x) = -1 + e**x where
+	// 0 < e**x < 10**(x*log10(e) + 1).
+	if x.Signbit() && x.adjusted() >= 1 {
+		xf, _ := x.Float64()
+		xf = math.Max(xf, -1e15)
+		if c.perturb(z, negOne.get(), int(xf*math.Log10E)+1, true) {
+			return z
+		}
+	}
+
+	// e**x - 1 loses about -adjusted(x) digits to cancellation.
+	return c.correctlyRound(z, max(-x.adjusted(), 0), func(ctx Context, t *Big) *Big {
+		ctx.Exp(t, x)
+		if t.IsInf(0) {
+			return t
+		}
+		return ctx.Sub(t, t, one.get())
+	})
+}
+
 // Floor sets z to the greatest integer value less than or equal
 // to x and returns z.
 func (c Context) Floor(z, x *Big) *Big {
@@ -1193,8 +1513,37 @@ func (c Context) FMA(z, x, y, u *Big) *Big {
 	return z.setShared(z0)
 }
 
-// Hypot sets z to Sqrt(p*p + q*q) and returns z.
-func (c Context) Hypot(z, p, q *Big) *Big {
+// Frexp breaks x into a fraction and an integral power of ten,
+// sets z to the fraction, and returns z and the exponent. The
+// fraction satisfies x == z * 10**exp and 0.1 <= |z| < 1.
+//
+// x is rounded to the Context's precision before it is broken
+// apart.
+//
+// Special cases:
+//     Frexp(±0)   = ±0, 0
+//     Frexp(±Inf) = ±Inf, 0
+//     Frexp(NaN)  = NaN, 0
+func (c Context) Frexp(z, x *Big) (*Big, int) {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z, 0
+	}
+	if z.checkNaNs(x, x, frexp) {
+		return z, 0
+	}
+	if c.Set(z, x); !z.IsFinite() || z.isZero() {
+		return z, 0
+	}
+	exp := z.adjusted() + 1
+	z.exp -= exp
+	return z, exp
+}
+
+// Hypot sets z to Sqrt(p*p + q*q) and returns z.
+func (c Context) Hypot(z, p, q *Big) *Big {
 	if z.CheckNaNs(p, q) {
 		return z
 	}
@@ -1218,6 +1567,45 @@ func (c Context) Hypot(z, p, q *Big) *Big {
 	return c.finish(z)
 }
 
+// Invert sets z to the digit-wise logical inversion of x and
+// returns z.
+//
+// See And for the requirements of logical operands.
+func (c Context) Invert(z, x *Big) *Big {
+	return c.logical(z, x, x, func(a, _ byte) byte { return a ^ 1 })
+}
+
+// Ldexp is the inverse of Frexp. It sets z to frac * 10**exp and
+// returns z.
+//
+// Unlike Scaleb, an exp too large or too small for the Context
+// results in an overflow or underflow, not NaN.
+//
+// Special cases:
+//     Ldexp(±0, exp)   = ±0
+//     Ldexp(±Inf, exp) = ±Inf
+//     Ldexp(NaN, exp)  = NaN
+func (c Context) Ldexp(z, frac *Big, exp int) *Big {
+	if debug {
+		frac.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(frac, frac, ldexp) {
+		return z
+	}
+	if !frac.IsFinite() {
+		return z.Copy(frac)
+	}
+	// Past this limit the result overflows or underflows no
+	// matter the value of frac.
+	n := 2 * (c.emax() + c.precision())
+	z.Copy(frac)
+	z.exp += min(max(exp, -n), n)
+	return c.finish(z)
+}
+
 // Log sets z to the natural logarithm of x and returns z.
 func (c Context) Log(z, x *Big) *Big {
 	if debug {
@@ -1232,7 +1620,16 @@ func (c Context) Log(z, x *Big) *Big {
 	if logSpecials(z, x) {
 		return z
 	}
-	return c.finish(c.log(z, x, false))
+	if c.log(z, x, false).checkDone(c) {
+		return z
+	}
+	return c.finish(z)
+}
+
+// LogContext is like Log, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) LogContext(ctx context.Context, z, x *Big) *Big {
+	return c.withDone(ctx).Log(z, x)
 }
 
 // Log10 sets z to the common logarithm of x and returns z.
@@ -1262,7 +1659,224 @@ func (c Context) Log10(z, x *Big) *Big {
 		z.SetMantScale(int64(x.adjusted()), 0)
 		return c.finish(z)
 	}
-	return c.finish(c.log(z, x, true))
+	if c.log(z, x, true).checkDone(c) {
+		return z
+	}
+	return c.finish(z)
+}
+
+// Log10Context is like Log10, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) Log10Context(ctx context.Context, z, x *Big) *Big {
+	return c.withDone(ctx).Log10(z, x)
+}
+
+// Log1p sets z to the natural logarithm of 1 + x and returns z.
+//
+// Unlike computing Log(1 + x), the result is correctly rounded
+// even if x is close to zero.
+//
+// Special cases:
+//     Log1p(NaN)  = NaN
+//     Log1p(+Inf) = +Inf
+//     Log1p(±0)   = ±0
+//     Log1p(-1)   = -Inf
+//     Log1p(x)    = NaN if x < -1
+func (c Context) Log1p(z, x *Big) *Big {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, x, log1p) {
+		return z
+	}
+
+	if x.IsInf(+1) {
+		return z.SetInf(false)
+	}
+	if x.isZero() {
+		return z.Copy(x)
+	}
+	switch x.Cmp(negOne.get()) {
+	case -1:
+		return z.setNaN(InvalidOperation, qnan, log1pinvalid)
+	case 0:
+		// log 0 = -Inf
+		return z.SetInf(true)
+	}
+
+	// Log1p(x) = x - x**2/2 + ... and log(1 + x) <= x.
+	if c.perturb(z, x, 2*(x.adjusted()+1), false) {
+		return z
+	}
+
+	// Rounding 1 + x to the working precision plus -adjusted(x)
+	// digits keeps the relative error of the result small.
+	return c.correctlyRound(z, max(-x.adjusted(), 0), func(ctx Context, t *Big) *Big {
+		ctx.Add(t, x, one.get())
+		return ctx.Log(t, t)
+	})
+}
+
+// Log2 sets z to the binary logarithm of x and returns z.
+//
+// If x is an integral power of two, like 8 or 0.125, the result is
+// exact. Otherwise, the result is correctly rounded.
+func (c Context) Log2(z, x *Big) *Big {
+	return c.logBase(z, x, two.get(), log2)
+}
+
+// LogBase sets z to the base b logarithm of x and returns z.
+//
+// If x is an integral power of b, the result is exact. Otherwise,
+// the result is correctly rounded. If b is not positive, is
+// infinite, or is 1, z is set to NaN and InvalidOperation is
+// raised.
+func (c Context) LogBase(z, x, b *Big) *Big {
+	return c.logBase(z, x, b, logbase)
+}
+
+// logBase sets z to the base b logarithm of x and returns z. op is
+// the payload for NaN operands.
+func (c Context) logBase(z, x, b *Big, op Payload) *Big {
+	if debug {
+		x.validate()
+		b.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, b, op) {
+		return z
+	}
+
+	if b.Sign() <= 0 || b.IsInf(0) || b.Cmp(one.get()) == 0 {
+		return z.setNaN(InvalidOperation, qnan, logbaseinvalid)
+	}
+	if x.Sign() < 0 {
+		return z.setNaN(InvalidOperation, qnan, logneg)
+	}
+	if logSpecials(z, x) {
+		if z.IsInf(0) && b.CmpAbs(one.get()) < 0 {
+			// log_b 0 = +Inf and log_b +Inf = -Inf if b < 1.
+			z.SetInf(!z.Signbit())
+		}
+		return z
+	}
+	if c.logExact(z, x, b) {
+		return z
+	}
+
+	return c.correctlyRound(z, 0, func(ctx Context, t *Big) *Big {
+		// log_b x = ln x / ln b
+		u := getDec(ctx)
+		ctx.Log(u, b)
+		ctx.Log(t, x)
+		ctx.Quo(t, t, u)
+		putDec(u)
+		return t
+	})
+}
+
+// logExact sets z to log_b x and reports whether it did so, which
+// is the case if x = b**(k/n) for integers k and n. Both x and b
+// must be positive and finite, and b must not be 1.
+//
+// The result is exact unless k/n does not have a terminating
+// decimal expansion, in which case it is correctly rounded.
+func (c Context) logExact(z, x, b *Big) bool {
+	// Find the largest n such that b = g**n for some decimal g. Then
+	// x must be an integral power of g.
+	var g Big
+	c.simpleReduce(g.Copy(b))
+	n := 1
+	if g.isCompact() && g.compact == 1 {
+		// b = 10**e = (10**±1)**|e|
+		n = int(arith.Abs(int64(g.exp)))
+		g.exp /= n
+	} else {
+		// Only prime q need be tried since g**(p*q) = (g**p)**q, and
+		// m < 2**q means m is not a q-th power.
+		m := g.coeff(g.Precision())
+		for q := 2; q < m.BitLen(); q++ {
+			if !big.NewInt(int64(q)).ProbablyPrime(0) {
+				continue
+			}
+			for g.exp%q == 0 {
+				r := iroot(m, q)
+				var p big.Int
+				if p.Exp(r, big.NewInt(int64(q)), nil).Cmp(m) != 0 {
+					break
+				}
+				m = r
+				g.exp /= q
+				n *= q
+			}
+		}
+		g.setCoeff(m, 0, g.exp)
+	}
+
+	k, ok := c.logInt(x, &g)
+	if !ok {
+		return false
+	}
+	if n == 1 {
+		c.finish(z.SetMantScale(k, 0))
+		return true
+	}
+	var d Big
+	c.Quo(z, d.SetMantScale(k, 0), new(Big).SetMantScale(int64(n), 0))
+	return true
+}
+
+// logInt returns k and true if x = g**k for some integer k, where g
+// is reduced. Both x and g must be positive and finite, and g must
+// not be 1.
+func (c Context) logInt(x, g *Big) (int64, bool) {
+	var xr Big
+	c.simpleReduce(xr.Copy(x))
+	cx := xr.coeff(xr.Precision())
+	cg := g.coeff(g.Precision())
+
+	if cg.Cmp(cst.OneInt) == 0 {
+		// g = 10**e, so x must be 10**(e*k).
+		if cx.Cmp(cst.OneInt) != 0 || xr.exp%g.exp != 0 {
+			return 0, false
+		}
+		return int64(xr.exp / g.exp), true
+	}
+
+	// cg has no trailing zeros, so neither does cg**|k|. Either
+	//
+	//    cx = cg**k, or
+	//    cx * cg**-k = 10**j
+	//
+	// In both cases cx >= 2**|k|, so |k| < 4 * len(cx).
+	lim := 4 * (xr.Precision() + 1)
+
+	// Estimate k with enough precision to find the nearest integer.
+	ctx := Context{Precision: 20 + arith.Length(uint64(lim))}
+	var r, t Big
+	ctx.Quo(&r, ctx.Log(&r, x), ctx.Log(&t, g))
+	ctx.RoundToIntegral(&r)
+	k, ok := r.Int64()
+	if !ok || k > int64(lim) || k < -int64(lim) {
+		return 0, false
+	}
+
+	var p big.Int
+	switch {
+	case k == 0:
+		ok = xr.exp == 0 && cx.Cmp(cst.OneInt) == 0
+	case k > 0:
+		ok = xr.exp == g.exp*int(k) && p.Exp(cg, big.NewInt(k), nil).Cmp(cx) == 0
+	default:
+		j := -(xr.exp - g.exp*int(k))
+		ok = j >= 0 && p.Mul(p.Exp(cg, big.NewInt(-k), nil), cx).Cmp(arith.BigPow10(uint64(j))) == 0
+	}
+	return k, ok
 }
 
 // logSpecials checks for special values (Inf, NaN, 0) for
@@ -1456,7 +2070,7 @@ func (c Context) logSmall(z, x *Big) int64 {
 		}
 	}
 
-	wctx := Context{Precision: prec + 1}
+	wctx := Context{Precision: prec + 1, Options: c.Options}
 	v = wctx.ln10Taylor(v)
 	tmp.SetMantScale(int64(k), 0)
 	maxCtx.Mul(v, v, tmp)
@@ -1646,7 +2260,7 @@ func (c Context) ln10(z *Big) *Big {
 		return z.Copy(_Ln10.get())
 	}
 
-	ctx := Context{Precision: prec + 10}
+	ctx := Context{Precision: prec + 10, Options: c.Options}
 	g := lgen{
 		ctx: ctx,
 		pow: eightyOne.get(), // 9 * 9
@@ -1668,7 +2282,7 @@ func (c Context) ln10Taylor(z *Big) *Big {
 	}
 	var (
 		tmp  Big
-		wctx Context
+		wctx = Context{Options: c.Options}
 		uctx = ContextUnlimited
 	)
 	// N[ln(10), 25] = N[ln(10), 5] + (exp(-N[ln(10), 5]) * 10 - 1)
@@ -1686,6 +2300,176 @@ func (c Context) ln10Taylor(z *Big) *Big {
 	return z
 }
 
+// Logb sets z to the adjusted exponent of x and returns z. The
+// adjusted exponent is the exponent of x when expressed in
+// scientific notation with one digit before the radix.
+//
+// Special cases:
+//		Logb(±Inf) = +Inf
+//		Logb(0)    = -Inf (DivisionByZero)
+func (c Context) Logb(z, x *Big) *Big {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, x, logb) {
+		return z
+	}
+	if x.IsInf(0) {
+		return z.SetInf(false)
+	}
+	if x.isZero() {
+		z.Context.Conditions |= DivisionByZero
+		return z.SetInf(true)
+	}
+	return c.finish(z.SetMantScale(int64(x.adjusted()), 0))
+}
+
+// Max sets z to the larger of x and y and returns z.
+//
+// Unlike the Max function, Max follows the General Decimal
+// Arithmetic specification. A quiet NaN is ignored if the other
+// operand is not a NaN, equal values are ordered like CmpTotal,
+// and the result is rounded using c.
+func (c Context) Max(z, x, y *Big) *Big {
+	return c.minmax(z, x, y, false, +1, maxNaN)
+}
+
+// MaxAbs is like Max, but compares the absolute values of x and
+// y. If they are equal, it is the same as Max.
+func (c Context) MaxAbs(z, x, y *Big) *Big {
+	return c.minmax(z, x, y, true, +1, maxNaN)
+}
+
+// Min sets z to the smaller of x and y and returns z.
+//
+// Unlike the Min function, Min follows the General Decimal
+// Arithmetic specification. See Max for more information.
+func (c Context) Min(z, x, y *Big) *Big {
+	return c.minmax(z, x, y, false, -1, minNaN)
+}
+
+// MinAbs is like Min, but compares the absolute values of x and
+// y. If they are equal, it is the same as Min.
+func (c Context) MinAbs(z, x, y *Big) *Big {
+	return c.minmax(z, x, y, true, -1, minNaN)
+}
+
+// minmax implements Max, MaxAbs, Min, and MinAbs. It chooses the
+// larger operand if sign > 0 and the smaller operand otherwise.
+func (c Context) minmax(z, x, y *Big, abs bool, sign int, op Payload) *Big {
+	if debug {
+		x.validate()
+		y.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+
+	if x.IsNaN(0) || y.IsNaN(0) {
+		if x.IsNaN(-1) || y.IsNaN(-1) || (x.IsNaN(0) && y.IsNaN(0)) {
+			z.checkNaNs(x, y, op)
+			return z
+		}
+		if x.IsNaN(0) {
+			x = y
+		}
+		return c.finish(z.Copy(x))
+	}
+
+	r := 0
+	if abs {
+		r = x.CmpAbs(y)
+	}
+	if r == 0 {
+		r = x.CmpTotal(y)
+	}
+	if r*sign < 0 {
+		x = y
+	}
+	return c.finish(z.Copy(x))
+}
+
+// Mod sets z to the remainder x - y*n, where n is the integer
+// part of x / y, and returns z.
+//
+// Mod is like Rem, but the quotient may have any number of digits.
+// The magnitude of the result is less than |y| and its sign
+// matches the sign of x.
+//
+// Special cases:
+//     Mod(±Inf, y)  = NaN
+//     Mod(NaN, y)   = NaN
+//     Mod(x, 0)     = NaN
+//     Mod(x, ±Inf)  = x
+//     Mod(x, NaN)   = NaN
+func (c Context) Mod(z, x, y *Big) *Big {
+	if z.invalidContext(c) {
+		return z
+	}
+	if x.IsFinite() && y.IsFinite() && !y.isZero() && x.exp > y.exp {
+		// x = m × 10**e, so x mod y = m × (10**(e - y.exp) mod n)
+		// mod n, where n is y's coefficient. This avoids computing
+		// all the digits of x, like reduceExact.
+		m := x.coeff(x.Precision())
+		n := y.coeff(y.Precision())
+		e := new(big.Int).Sub(big.NewInt(int64(x.exp)), big.NewInt(int64(y.exp)))
+		e.Exp(cst.TenInt, e, n)
+		m.Mul(m, e).Mod(m, n)
+		return c.finish(z.setCoeff(m, x.form&signbit, y.exp))
+	}
+	ctx := c
+	ctx.Precision = UnlimitedPrecision
+	if !ctx.Rem(z, x, y).IsFinite() {
+		return z
+	}
+	return c.finish(z)
+}
+
+// Modf sets z to the integer part and f to the fractional part of
+// x and returns z and f. Both results have the same sign as x.
+//
+// x is rounded to the Context's precision before it is split, so
+// z + f is always equal to the rounded x.
+//
+// Special cases:
+//     Modf(±Inf) = ±Inf, NaN
+//     Modf(NaN)  = NaN, NaN
+func (c Context) Modf(z, x, f *Big) (*Big, *Big) {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) || f.invalidContext(c) {
+		return z, f
+	}
+	if x.IsNaN(0) {
+		z.checkNaNs(x, x, modf)
+		f.checkNaNs(x, x, modf)
+		return z, f
+	}
+	if x.IsInf(0) {
+		z.Copy(x)
+		return z, f.setNaN(InvalidOperation, qnan, modfinf)
+	}
+
+	var t, n Big
+	if c.Set(&t, x); t.IsInf(0) {
+		z.Copy(&t)
+		return z, f.setNaN(InvalidOperation, qnan, modfinf)
+	}
+	exact := Context{Precision: UnlimitedPrecision, RoundingMode: ToZero}
+	exact.RoundToIntegral(n.Copy(&t))
+	exact.Sub(f, &t, &n)
+	if f.isZero() {
+		f.SetSignbit(t.Signbit())
+	}
+	z.Context.Conditions |= t.Context.Conditions
+	f.Context.Conditions |= t.Context.Conditions
+	return z.Copy(&n), f
+}
+
 // Mul sets z to x * y and returns z.
 func (c Context) Mul(z, x, y *Big) *Big {
 	if z.invalidContext(c) {
@@ -1704,6 +2488,9 @@ func (c Context) mul(z, x, y *Big) *Big {
 	sign := x.form&signbit ^ y.form&signbit
 
 	if x.IsFinite() && y.IsFinite() {
+		if z.checkStorage(c, int64(x.Precision())+int64(y.Precision())) {
+			return z
+		}
 		z.form = finite | sign
 		z.exp = x.exp + y.exp
 
@@ -1725,8 +2512,8 @@ func (c Context) mul(z, x, y *Big) *Big {
 			}
 		} else if y.isCompact() { // x.isInflated
 			arith.Mul(&z.unscaled, &x.unscaled, y.compact)
-		} else {
-			z.unscaled.Mul(&x.unscaled, &y.unscaled)
+		} else if !z.mulWords(x, y) {
+			arith.MulBig(&z.unscaled, &x.unscaled, &y.unscaled)
 		}
 		return z.norm()
 	}
@@ -1794,9 +2581,14 @@ func (c Context) NextMinus(z, x *Big) *Big {
 		return z.SetBigMantScale(m, -c.etop())
 	}
 
+	if z == x {
+		x = new(Big).Copy(x)
+	}
 	c.RoundingMode = ToNegativeInf
-	c.Set(z, x)
-	c.Sub(z, x, new(Big).SetMantScale(1, -c.etiny()+1))
+	// If x can't be represented exactly, rounding it is enough.
+	if c.Set(z, x).Cmp(x) == 0 {
+		c.Sub(z, x, new(Big).SetMantScale(1, -c.etiny()+1))
+	}
 	z.Context.Conditions &= c.Conditions
 	return z
 }
@@ -1824,13 +2616,65 @@ func (c Context) NextPlus(z, x *Big) *Big {
 		return z.SetBigMantScale(m, -c.etop())
 	}
 
+	if z == x {
+		x = new(Big).Copy(x)
+	}
 	c.RoundingMode = ToPositiveInf
-	c.Set(z, x)
-	c.Add(z, x, new(Big).SetMantScale(1, -c.etiny()+1))
+	// If x can't be represented exactly, rounding it is enough.
+	if c.Set(z, x).Cmp(x) == 0 {
+		c.Add(z, x, new(Big).SetMantScale(1, -c.etiny()+1))
+	}
 	z.Context.Conditions &= c.Conditions
 	return z
 }
 
+// NextToward sets z to the representable number closest to x in
+// the direction of y and returns z.
+//
+// If x == y, z is set to x with the sign of y. Otherwise, the
+// result is the same as NextPlus or NextMinus, except that
+// Overflow is raised if the result is infinite and Underflow is
+// raised if the result is subnormal or zero.
+func (c Context) NextToward(z, x, y *Big) *Big {
+	if debug {
+		x.validate()
+		y.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, y, nexttoward) {
+		return z
+	}
+
+	switch cmp(x, y, false) {
+	case -1:
+		c.NextPlus(z, x)
+	case +1:
+		c.NextMinus(z, x)
+	default:
+		return z.CopySign(x, y)
+	}
+
+	if z.IsInf(0) {
+		z.Context.Conditions |= Overflow | Inexact | Rounded
+	} else if z.adjusted() < c.emin() {
+		z.Context.Conditions |= Underflow | Subnormal | Inexact | Rounded
+		if z.isZero() {
+			z.Context.Conditions |= Clamped
+		}
+	}
+	return z
+}
+
+// Or sets z to the digit-wise logical OR of x and y and returns
+// z.
+//
+// See And for the requirements of logical operands.
+func (c Context) Or(z, x, y *Big) *Big {
+	return c.logical(z, x, y, func(a, b byte) byte { return a | b })
+}
+
 // pi2 sets z to the mathematical constant pi / 2 and returns z.
 func (c Context) pi2(z *Big) *Big {
 	if c.Precision <= constPrec {
@@ -1852,6 +2696,14 @@ func (c Context) Pi(z *Big) *Big {
 	return c.pi(z)
 }
 
+// PiContext is like Pi, but it stops early if ctx is done before
+// pi is computed. In that case, it sets z to NaN and raises
+// Canceled. This bounds the time spent computing pi to a large
+// precision.
+func (c Context) PiContext(ctx context.Context, z *Big) *Big {
+	return c.withDone(ctx).Pi(z)
+}
+
 func (c Context) getPiA() func(n uint64) *Big {
 	return func(n uint64) *Big {
 		// returns A + Bn
@@ -1956,14 +2808,23 @@ func (c Context) piChudnovskyBrothers(z *Big) *Big {
 
 	calcPrec := c.Precision + 16
 	niters := uint64(math.Ceil(float64(calcPrec)/14)) + 1
-	ctx2 := Context{Precision: calcPrec}
+	ctx2 := Context{
+		Precision: calcPrec,
+		Options:   c.Options,
+	}
 
 	var value Big
 	BinarySplit(&value, ctx2, 0, niters,
 		ctx2.getPiA(), ctx2.getPiP(), getPiB(), ctx2.getPiQ())
+	if z.checkDone(c) {
+		return z
+	}
 
 	var tmp Big
 	ctx2.Sqrt(&tmp, _10005.get())
+	if z.checkDone(c) {
+		return z
+	}
 	ctx2.Mul(&tmp, _426880.get(), &tmp)
 	ctx2.Quo(z, &tmp, &value)
 
@@ -2035,7 +2896,11 @@ func (c Context) Pow(z, x, y *Big) *Big {
 // powInt sets z = x**y for the integer y.
 func (c Context) powInt(z, x, y *Big) *Big {
 	ctx := Context{
-		Precision: c.precision() + y.Precision() - y.Scale() + 2,
+		Precision: c.precision(),
+		Options:   c.Options,
+	}
+	if ctx.Precision != UnlimitedPrecision {
+		ctx.Precision += y.Precision() - y.Scale() + 2
 	}
 
 	if yy, ok := y.Uint64(); ok {
@@ -2044,7 +2909,9 @@ func (c Context) powInt(z, x, y *Big) *Big {
 
 	x0 := getDec(ctx)
 	if y.Signbit() {
-		ctx.Precision++
+		if ctx.Precision != UnlimitedPrecision {
+			ctx.Precision++
+		}
 		ctx.Quo(x0, one.get(), x)
 	} else {
 		x0.Copy(x)
@@ -2058,6 +2925,10 @@ func (c Context) powInt(z, x, y *Big) *Big {
 	for y0.Sign() != 0 {
 		if y0.Bit(0) != 0 {
 			ctx.Mul(z, z, x0)
+			if z.IsNaN(0) {
+				sign = false
+				break
+			}
 			if !z.IsFinite() || z.Sign() == 0 ||
 				z.Context.Conditions&Clamped != 0 {
 				z.Context.Conditions |= Underflow | Subnormal
@@ -2066,6 +2937,10 @@ func (c Context) powInt(z, x, y *Big) *Big {
 			}
 		}
 		y0.Rsh(y0, 1)
+		if y0.Sign() == 0 {
+			// Avoid squaring x0 needlessly.
+			break
+		}
 		ctx.Mul(x0, x0, x0)
 		if x0.IsNaN(0) {
 			putDec(x0)
@@ -2084,8 +2959,16 @@ func (c Context) powUint64(z, x *Big, y uint64) *Big {
 	z.SetUint64(1)
 	sign := x.Signbit() && y&1 == 1
 	for y != 0 {
+		if z.checkDone(c) {
+			putDec(x0)
+			return z
+		}
 		if y&1 != 0 {
 			c.Mul(z, z, x0)
+			if z.IsNaN(0) {
+				sign = false
+				break
+			}
 			if !z.IsFinite() || z.Sign() == 0 ||
 				z.Context.Conditions&Clamped != 0 {
 				z.Context.Conditions |= Underflow | Subnormal
@@ -2094,6 +2977,10 @@ func (c Context) powUint64(z, x *Big, y uint64) *Big {
 			}
 		}
 		y >>= 1
+		if y == 0 {
+			// Avoid squaring x0 needlessly.
+			break
+		}
 		c.Mul(x0, x0, x0)
 		if x0.IsNaN(0) {
 			putDec(x0)
@@ -2139,9 +3026,22 @@ func (c Context) Quantize(z *Big, n int) *Big {
 		return z
 	}
 
-	n = -n
-	if z.isSpecial() {
-		if z.form&inf != 0 {
+	c.quantize(z, -n)
+	if z.IsFinite() && !z.isZero() {
+		if adj := z.adjusted(); adj > c.emax() {
+			return z.setNaN(InvalidOperation, qnan, quantminmax)
+		} else if adj < c.emin() {
+			z.Context.Conditions |= Subnormal
+		}
+	}
+	return c.foldDown(z)
+}
+
+// quantize implements Quantize, except that n is the exponent rather
+// than the scale.
+func (c Context) quantize(z *Big, n int) *Big {
+	if z.isSpecial() {
+		if z.form&inf != 0 {
 			return z.setNaN(InvalidOperation, qnan, quantinf)
 		}
 		z.checkNaNs(z, z, quantization)
@@ -2161,6 +3061,9 @@ func (c Context) Quantize(z *Big, n int) *Big {
 	if z.Precision()+shift > c.precision() {
 		return z.setNaN(InvalidOperation, qnan, quantprec)
 	}
+	if shift > 0 && z.checkStorage(c, int64(z.Precision())+int64(shift)) {
+		return z
+	}
 
 	z.exp = n
 	if shift == 0 {
@@ -2171,7 +3074,15 @@ func (c Context) Quantize(z *Big, n int) *Big {
 		z.Context.Conditions |= Rounded
 	}
 
-	m := c.RoundingMode
+	if -shift > z.Precision()+1 {
+		// Every digit is discarded. The result depends only on
+		// whether the remainder is zero, which it is not, so use
+		// a small one instead of dividing by a huge power of ten.
+		z.compact = 1
+		z.precision = 1
+		shift = -19
+	}
+
 	neg := z.form & signbit
 	if z.isCompact() {
 		if shift > 0 {
@@ -2180,8 +3091,8 @@ func (c Context) Quantize(z *Big, n int) *Big {
 			}
 			// shift < 0
 		} else if yc, ok := arith.Pow10(uint64(-shift)); ok {
-			z.quo(m, z.compact, neg, yc, 0)
-			return z
+			z.quo(c, z.compact, neg, yc, 0)
+			return c.quantizeCarry(z, n)
 		}
 		z.unscaled.SetUint64(z.compact)
 		z.compact = cst.Inflated
@@ -2190,9 +3101,23 @@ func (c Context) Quantize(z *Big, n int) *Big {
 	if shift > 0 {
 		arith.MulBigPow10(&z.unscaled, &z.unscaled, uint64(shift))
 		z.precision = arith.BigLength(&z.unscaled)
-	} else {
-		var r big.Int
-		z.quoBig(m, &z.unscaled, neg, arith.BigPow10(uint64(-shift)), 0, &r)
+		return z
+	}
+	z.quoPow10(c, uint64(-shift))
+	return c.quantizeCarry(z, n)
+}
+
+// quantizeCarry restores z's exponent to n if rounding carried
+// into a new digit and incremented it. For example, quantizing
+// 9.5 to a scale of zero results in 10, not 1E+1.
+func (c Context) quantizeCarry(z *Big, n int) *Big {
+	if !z.IsFinite() || z.exp == n {
+		return z
+	}
+	c.shiftl(z, uint64(z.exp-n))
+	z.exp = n
+	if z.Precision() > c.precision() {
+		return z.setNaN(InvalidOperation, qnan, quantprec)
 	}
 	return z
 }
@@ -2242,15 +3167,19 @@ func (c Context) Quo(z, x, y *Big) *Big {
 		// 0 / y
 		return c.fix(z.setZero(sign, x.exp-y.exp))
 	}
+	return c.fix(c.quoFinite(z, x, y))
+}
 
+// quoFinite sets z to x / y, where x and y are finite and y is
+// non-zero, and returns z.
+func (c Context) quoFinite(z, x, y *Big) *Big {
 	var (
 		ideal = x.exp - y.exp // preferred exponent.
-		m     = c.RoundingMode
 		yp    = y.Precision() // stored since we might decrement it.
 		zp    = c.precision() // stored because of overhead.
 	)
 	if zp == UnlimitedPrecision {
-		m = unnecessary
+		c.RoundingMode = unnecessary
 		zp = x.Precision() + int(math.Ceil(10*float64(yp)/3))
 	}
 
@@ -2264,42 +3193,52 @@ func (c Context) Quo(z, x, y *Big) *Big {
 		expadj := ideal - z.exp
 		if shift > 0 {
 			if sx, ok := arith.MulPow10(x.compact, uint64(shift)); ok {
-				if z.quo(m, sx, x.form, y.compact, y.form) && expadj > 0 {
-					c.simpleReduce(z)
+				if z.quo(c, sx, x.form, y.compact, y.form) && expadj > 0 {
+					c.reduceIdeal(z, ideal)
 				}
 				return z
 			}
+			if c.quoFiniteWords(z, x, y, zp, ideal) {
+				return z
+			}
 			xb := z.unscaled.SetUint64(x.compact)
 			xb = arith.MulBigPow10(xb, xb, uint64(shift))
 			yb := new(big.Int).SetUint64(y.compact)
 			rb := new(big.Int)
-			if z.quoBig(m, xb, x.form, yb, y.form, rb) && expadj > 0 {
-				c.simpleReduce(z)
+			if z.quoBig(c, xb, x.form, yb, y.form, rb) && expadj > 0 {
+				c.reduceIdeal(z, ideal)
 			}
 			return z
 		}
 		if shift < 0 {
 			if sy, ok := arith.MulPow10(y.compact, uint64(-shift)); ok {
-				if z.quo(m, x.compact, x.form, sy, y.form) && expadj > 0 {
-					c.simpleReduce(z)
+				if z.quo(c, x.compact, x.form, sy, y.form) && expadj > 0 {
+					c.reduceIdeal(z, ideal)
 				}
 				return z
 			}
+			if c.quoFiniteWords(z, x, y, zp, ideal) {
+				return z
+			}
 			yb := z.unscaled.SetUint64(y.compact)
 			yb = arith.MulBigPow10(yb, yb, uint64(-shift))
 			xb := new(big.Int).SetUint64(x.compact)
 			rb := new(big.Int)
-			if z.quoBig(m, xb, x.form, yb, y.form, rb) && expadj > 0 {
-				c.simpleReduce(z)
+			if z.quoBig(c, xb, x.form, yb, y.form, rb) && expadj > 0 {
+				c.reduceIdeal(z, ideal)
 			}
 			return z
 		}
-		if z.quo(m, x.compact, x.form, y.compact, y.form) && expadj > 0 {
-			c.simpleReduce(z)
+		if z.quo(c, x.compact, x.form, y.compact, y.form) && expadj > 0 {
+			c.reduceIdeal(z, ideal)
 		}
 		return z
 	}
 
+	if c.quoFiniteWords(z, x, y, zp, ideal) {
+		return z
+	}
+
 	xb, yb := &x.unscaled, &y.unscaled
 	if x.isCompact() {
 		xb = new(big.Int).SetUint64(x.compact)
@@ -2326,13 +3265,14 @@ func (c Context) Quo(z, x, y *Big) *Big {
 	}
 
 	expadj := ideal - z.exp
-	if z.quoBig(m, xb, x.form, yb, y.form, alias(tmp, &z.unscaled)) && expadj > 0 {
-		c.simpleReduce(z)
+	if z.quoBig(c, xb, x.form, yb, y.form, alias(tmp, &z.unscaled)) && expadj > 0 {
+		c.reduceIdeal(z, ideal)
 	}
 	return z
 }
 
-func (z *Big) quo(m RoundingMode, x uint64, xneg form, y uint64, yneg form) bool {
+func (z *Big) quo(c Context, x uint64, xneg form, y uint64, yneg form) bool {
+	m := c.RoundingMode
 	z.form = xneg ^ yneg
 	z.compact = x / y
 	z.precision = arith.Length(z.compact)
@@ -2348,7 +3288,9 @@ func (z *Big) quo(m RoundingMode, x uint64, xneg form, y uint64, yneg form) bool
 	}
 
 	rc := 1
-	if hi, lo := bits.Mul64(r, 2); hi == 0 {
+	if m == Stochastic {
+		rc = arith.Cmp(r, c.threshold(y))
+	} else if hi, lo := bits.Mul64(r, 2); hi == 0 {
 		rc = arith.Cmp(lo, y)
 	}
 
@@ -2357,7 +3299,7 @@ func (z *Big) quo(m RoundingMode, x uint64, xneg form, y uint64, yneg form) bool
 		return false
 	}
 
-	if m.needsInc(z.compact&1 != 0, rc, xneg == yneg) {
+	if m.needsInc(z.compact%10, rc, xneg == yneg) {
 		z.Context.Conditions |= Rounded
 		z.compact++
 
@@ -2382,15 +3324,16 @@ func (z *Big) quo(m RoundingMode, x uint64, xneg form, y uint64, yneg form) bool
 }
 
 func (z *Big) quoBig(
-	m RoundingMode,
+	c Context,
 	x *big.Int, xneg form,
 	y *big.Int, yneg form,
 	r *big.Int,
 ) bool {
+	m := c.RoundingMode
 	z.compact = cst.Inflated
 	z.form = xneg ^ yneg
 
-	q, r := z.unscaled.QuoRem(x, y, r)
+	q, r := arith.QuoRemBig(&z.unscaled, x, y, r)
 	if r.Sign() == 0 {
 		z.norm()
 		return true
@@ -2404,10 +3347,13 @@ func (z *Big) quoBig(
 
 	var rc int
 	rv := r.Uint64()
-	// Drop into integers if possible.
-	if r.IsUint64() && y.IsUint64() && rv <= math.MaxUint64/2 {
+	switch {
+	case m == Stochastic:
+		rc = r.CmpAbs(c.thresholdBig(y))
+	case r.IsUint64() && y.IsUint64() && rv <= math.MaxUint64/2:
+		// Drop into integers if possible.
 		rc = arith.Cmp(rv*2, y.Uint64())
-	} else {
+	default:
 		rc = r.Mul(r, cst.TwoInt).CmpAbs(y)
 	}
 
@@ -2416,7 +3362,7 @@ func (z *Big) quoBig(
 		return false
 	}
 
-	if m.needsInc(q.Bit(0) != 0, rc, xneg == yneg) {
+	if m.needsInc(lastDigit(q), rc, xneg == yneg) {
 		z.Context.Conditions |= Rounded
 		z.precision = arith.BigLength(q)
 		arith.Add(q, q, 1)
@@ -2456,6 +3402,9 @@ func (c Context) QuoInt(z, x, y *Big) *Big {
 			return c.fix(z.setZero(sign, 0))
 		}
 		z, _ = c.quorem(z, nil, x, y)
+		if z.IsNaN(0) {
+			return z
+		}
 		z.exp = 0
 		if z.Precision() > c.precision() {
 			return z.setNaN(DivisionImpossible, qnan, quointprec)
@@ -2558,6 +3507,32 @@ func (c Context) quorem(z0, z1, x, y *Big) (*Big, *Big) {
 		z = z1
 	}
 
+	if x.exp < y.exp && x.adjusted() < y.adjusted() {
+		// |x| < |y|, so the quotient is zero and the remainder is
+		// x. Return early so that y isn't rescaled by a possibly
+		// huge power of ten.
+		if z1 != nil {
+			z1.Copy(x)
+			z1.exp = 0
+		}
+		if z0 != nil {
+			z0.setZero((x.form^y.form)&signbit, 0)
+		}
+		return z0, z1
+	}
+
+	// Rescaling x or y to the smaller exponent adds |shift| digits.
+	if shift := x.exp - y.exp; shift > 0 && c.tooLarge(int64(x.Precision())+int64(shift)) ||
+		shift < 0 && c.tooLarge(int64(y.Precision())-int64(shift)) {
+		if z0 != nil {
+			z0.setNaN(InsufficientStorage, qnan, storage)
+		}
+		if z1 != nil {
+			z1.setNaN(InsufficientStorage, qnan, storage)
+		}
+		return z0, z1
+	}
+
 	if x.isCompact() && y.isCompact() {
 		shift := x.exp - y.exp
 		if shift > 0 {
@@ -2626,11 +3601,11 @@ func (m RoundingMode) quoremBig(
 	}
 
 	if z1 != nil {
-		z0.unscaled.QuoRem(x, y, &z1.unscaled)
+		arith.QuoRemBig(&z0.unscaled, x, y, &z1.unscaled)
 		z1.form = xneg
 		z1.norm()
 	} else {
-		z0.unscaled.QuoRem(x, y, new(big.Int))
+		arith.QuoRemBig(&z0.unscaled, x, y, new(big.Int))
 	}
 	z0.form = xneg ^ yneg
 	return z0.norm(), z1
@@ -2641,8 +3616,30 @@ func (c Context) Reduce(z *Big) *Big {
 	if debug {
 		z.validate()
 	}
+	if z.IsFinite() && z.checkStorage(c, int64(z.Precision())) {
+		return z
+	}
 	c.Round(z)
-	return c.simpleReduce(z)
+	c.simpleReduce(z)
+
+	// If the Context clamps exponents, keep as many trailing
+	// zeros as it needs. Only the zeros removed above are
+	// restored, so this doesn't count as clamping.
+	clamped := z.Context.Conditions & Clamped
+	c.foldDown(z)
+	z.Context.Conditions = z.Context.Conditions&^Clamped | clamped
+	return z
+}
+
+// reduceIdeal is like simpleReduce, but it keeps enough trailing
+// zeros for z's exponent to be ideal.
+func (c Context) reduceIdeal(z *Big, ideal int) *Big {
+	c.simpleReduce(z)
+	if n := z.exp - ideal; n > 0 {
+		c.shiftl(z, uint64(n))
+		z.exp = ideal
+	}
+	return z
 }
 
 // simpleReduce is the same as Reduce, but it does not round
@@ -2664,36 +3661,27 @@ func (c Context) simpleReduce(z *Big) *Big {
 		if z.unscaled.Bit(0) != 0 {
 			return z
 		}
-
-		var r big.Int
-		for z.precision >= 20 {
-			z.unscaled.QuoRem(&z.unscaled, cst.OneMillionInt, &r)
-			if r.Sign() != 0 {
-				// TODO(eric): which is less expensive? Copying
-				// z.unscaled into a temporary or reconstructing
-				// if we can't divide by N?
-				z.unscaled.Mul(&z.unscaled, cst.OneMillionInt)
-				z.unscaled.Add(&z.unscaled, &r)
-				break
-			}
-			z.exp += 6
-			z.precision -= 6
-
-			// Try to avoid reconstruction for odd numbers.
-			if z.unscaled.Bit(0) != 0 {
-				break
-			}
+		if z.simpleReduceWords() {
+			return z
 		}
 
-		for z.precision >= 20 {
-			z.unscaled.QuoRem(&z.unscaled, cst.TenInt, &r)
+		// The number of zeros to divide out doubles while they
+		// divide z and halves once they don't, so k trailing zeros
+		// take O(log k) divisions instead of O(k).
+		var q, r big.Int
+		for n := 6; n > 0 && z.precision >= 20; {
+			n = min(n, z.precision-19)
+			q.QuoRem(&z.unscaled, arith.BigPow10(uint64(n)), &r)
 			if r.Sign() != 0 {
-				z.unscaled.Mul(&z.unscaled, cst.TenInt)
-				z.unscaled.Add(&z.unscaled, &r)
-				break
+				n /= 2
+				continue
 			}
-			z.exp++
-			z.precision--
+			z.unscaled.Set(&q)
+			z.exp += n
+			z.precision -= n
+			n *= 2
+
+			// Try to avoid dividing odd numbers.
 			if z.unscaled.Bit(0) != 0 {
 				break
 			}
@@ -2745,6 +3733,9 @@ func (c Context) Rem(z, x, y *Big) *Big {
 		// #72.
 		var tmp Big
 		_, z = c.quorem(&tmp, z, x, y)
+		if z.IsNaN(0) {
+			return z
+		}
 		z.exp = min(x.exp, y.exp)
 		tmp.exp = 0
 		if tmp.Precision() > c.precision() {
@@ -2772,6 +3763,87 @@ func (c Context) Rem(z, x, y *Big) *Big {
 	return c.Set(z, x)
 }
 
+// RemNear sets z to the remainder x - y*n, where n is the integer
+// nearest to x / y, and returns z. If two integers are equally
+// near, n is the even one.
+//
+// Unlike Rem, the magnitude of the result is at most |y| / 2 and
+// its sign might differ from the sign of x.
+func (c Context) RemNear(z, x, y *Big) *Big {
+	if debug {
+		x.validate()
+		y.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+
+	if x.IsFinite() && y.IsFinite() {
+		if y.isZero() {
+			if x.isZero() {
+				// 0 / 0
+				return z.setNaN(InvalidOperation|DivisionUndefined, qnan, quo00)
+			}
+			// x / 0
+			return z.setNaN(InvalidOperation, qnan, remx0)
+		}
+		if x.isZero() {
+			// 0 / y
+			return c.finish(z.setZero(x.form&signbit, min(x.exp, y.exp)))
+		}
+		return c.remNear(z, x, y)
+	}
+
+	// NaN / NaN
+	// NaN / y
+	// x / NaN
+	if z.checkNaNs(x, y, division) {
+		return z
+	}
+
+	if x.form&inf != 0 {
+		// ±Inf / y
+		return z.setNaN(InvalidOperation, qnan, reminfy)
+	}
+	// x / ±Inf
+	return c.Set(z, x)
+}
+
+// remNear is the implementation of RemNear for finite, non-zero
+// x and y.
+func (c Context) remNear(z, x, y *Big) *Big {
+	if z == y {
+		y = new(Big).Copy(y)
+	}
+	exp := min(x.exp, y.exp)
+
+	var q Big
+	c.quorem(&q, z, x, y)
+	if q.IsNaN(0) {
+		return z
+	}
+	z.exp = exp
+
+	// The magnitude of the truncated remainder is in [0, |y|).
+	// If it's larger than |y| / 2 (or equal and the quotient is
+	// odd) round the quotient away from zero and adjust the
+	// remainder.
+	var r2 Big
+	ContextUnlimited.Add(&r2, z, z)
+	odd := q.isCompact() && q.compact&1 != 0 ||
+		q.isInflated() && q.unscaled.Bit(0) != 0
+	if r := r2.CmpAbs(y); r > 0 || r == 0 && odd {
+		var t Big
+		ContextUnlimited.Sub(z, z, t.CopySign(y, z))
+		ContextUnlimited.Add(&q, q.copyAbs(&q), one.get())
+	}
+
+	if q.Precision() > c.precision() {
+		return z.setNaN(DivisionImpossible, qnan, quorem_)
+	}
+	return c.finish(z)
+}
+
 // Round rounds z down to the Context's precision and returns z.
 //
 // For a finite z, result of Round will always be within the
@@ -2784,15 +3856,27 @@ func (c Context) Round(z *Big) *Big {
 	if z.invalidContext(c) {
 		return z
 	}
-	return c.round(c.fix(z))
+	c.round(c.fix(z))
+	if z.IsFinite() && z.adjusted() > c.emax() {
+		// Rounding carried into a new digit and overflowed.
+		c.fix(z)
+	}
+	return z
 }
 
 func (c Context) finish(z *Big) *Big {
 	c.fix(z)
-	if c.OperatingMode != GDA {
-		return z
+	if c.OperatingMode == GDA {
+		c.round(z)
+		if z.IsFinite() && z.adjusted() > c.emax() {
+			// Rounding carried into a new digit and overflowed.
+			c.fix(z)
+		}
 	}
-	return c.round(z)
+	if z.IsFinite() {
+		z.checkStorage(c, int64(z.Precision()))
+	}
+	return z
 }
 
 // round rounds z to the Context's precision, if necessary.
@@ -2817,13 +3901,202 @@ func (c Context) round(z *Big) *Big {
 
 // RoundToInt rounds z down to an integral value.
 func (c Context) RoundToInt(z *Big) *Big {
-	if z.isSpecial() || z.exp >= 0 {
+	if z.isSpecial() {
+		z.checkNaNs(z, z, roundtoint)
+		return z
+	}
+	if z.exp >= 0 {
 		return z
 	}
 	c.Precision = z.Precision()
 	return c.Quantize(z, 0)
 }
 
+// RoundToIntegral is like RoundToInt, but it does not raise the
+// Inexact or Rounded conditions.
+func (c Context) RoundToIntegral(z *Big) *Big {
+	conds := z.Context.Conditions
+	c.RoundToInt(z)
+	z.Context.Conditions &^= (Inexact | Rounded) &^ conds
+	return z
+}
+
+// Root sets z to the nth root of x and returns z.
+//
+// If n is not positive, or if n is even and x is negative, z is
+// set to NaN and InvalidOperation is raised. For odd n, the root of
+// a negative number is negative; for example, Root(-27, 3) = -3.
+//
+// If the root can be represented exactly in the Context's
+// precision, the result is exact and, like Sqrt, its exponent is
+// as close as possible to floor(e/n), where e is the exponent of x.
+// Otherwise, the result is correctly rounded.
+func (c Context) Root(z, x *Big, n int) *Big {
+	return c.root(z, x, n, root)
+}
+
+// root sets z to the nth root of x and returns z. op is the payload
+// for NaN operands.
+func (c Context) root(z, x *Big, n int, op Payload) *Big {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, x, op) {
+		return z
+	}
+
+	if n <= 0 {
+		return z.setNaN(InvalidOperation, qnan, rootinvalid)
+	}
+	neg := x.Signbit()
+	if neg && n%2 == 0 && x.Sign() != 0 {
+		return z.setNaN(InvalidOperation, qnan, rootneg)
+	}
+	switch n {
+	case 1:
+		return c.Set(z, x)
+	case 2:
+		return c.Sqrt(z, x)
+	}
+	if x.IsInf(0) {
+		return z.SetInf(neg)
+	}
+
+	// floor(e/n)
+	ideal := x.exp / n
+	if x.exp%n != 0 && x.exp < 0 {
+		ideal--
+	}
+	if x.isZero() {
+		return c.finish(z.setZero(x.form, ideal))
+	}
+	if c.exactRoot(z, x, n, ideal) {
+		return z
+	}
+
+	// ln |x| has about as many integral digits as the exponent of x.
+	adj := x.adjusted()
+	if adj < 0 {
+		adj = -adj
+	}
+	return c.correctlyRound(z, arith.Length(uint64(adj)), func(ctx Context, t *Big) *Big {
+		// Root(x, n) = sign(x) * e**(ln(|x|) / n)
+		var d Big
+		d.SetMantScale(int64(n), 0)
+		ctx.Log(t, t.CopyAbs(x))
+		ctx.Quo(t, t, &d)
+		ctx.Exp(t, t)
+		return t.SetSignbit(neg)
+	})
+}
+
+// exactRoot sets z to the nth root of x, where x is finite and
+// non-zero, if it is exact and reports whether it did so.
+//
+// An exact root with more digits than the Context's precision is
+// rounded. Otherwise, its exponent is as close as possible to ideal.
+func (c Context) exactRoot(z, x *Big, n, ideal int) bool {
+	var r Big
+	c.simpleReduce(r.CopyAbs(x))
+
+	// If r = y**n then y = m * 10**e, where m has no trailing zeros,
+	// and r = m**n * 10**(e*n).
+	if r.exp%n != 0 {
+		return false
+	}
+	m := r.coeff(r.Precision())
+	y := iroot(m, n)
+	var p big.Int
+	if p.Exp(y, big.NewInt(int64(n)), nil).Cmp(m) != 0 {
+		return false
+	}
+
+	z.setCoeff(y, x.form&signbit, r.exp/n)
+	if s := min(z.exp-ideal, c.precision()-z.Precision()); s > 0 {
+		c.shiftl(z, uint64(s))
+		z.exp -= s
+	}
+	c.finish(z)
+	return true
+}
+
+// iroot returns floor(x**(1/n)) for x > 0 and n > 0.
+func iroot(x *big.Int, n int) *big.Int {
+	// Newton's method, starting from an overestimate, decreases
+	// monotonically to the root.
+	//
+	//    y' = ((n - 1)*y + x/y**(n - 1)) / n
+	if x.BitLen() <= n {
+		// 1 <= x < 2**n
+		return big.NewInt(1)
+	}
+	N := big.NewInt(int64(n))
+	N1 := big.NewInt(int64(n - 1))
+	y := new(big.Int).Lsh(cst.OneInt, uint((x.BitLen()+n-1)/n))
+	var t, u big.Int
+	for {
+		t.Exp(y, N1, nil)
+		t.Quo(x, &t)
+		u.Mul(y, N1)
+		t.Add(&t, &u)
+		t.Quo(&t, N)
+		if t.Cmp(y) >= 0 {
+			return y
+		}
+		y.Set(&t)
+	}
+}
+
+// Rotate sets z to x with the digits of its coefficient rotated
+// by y places and returns z. If y is positive the rotation is to
+// the left, otherwise it is to the right.
+//
+// Before rotating, the coefficient is padded with leading zeros
+// or truncated to the Context's precision. If the precision is
+// UnlimitedPrecision the coefficient is neither padded nor
+// truncated. The sign and scale of x are unchanged.
+//
+// y must be an integer with a scale of zero in the range
+// [-precision, precision]. Otherwise, z is set to NaN and
+// InvalidOperation is raised.
+func (c Context) Rotate(z, x, y *Big) *Big {
+	if debug {
+		x.validate()
+		y.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, y, rotation) {
+		return z
+	}
+
+	p := c.digits(x)
+	n, ok := intOperand(y, p)
+	if !ok {
+		return z.setNaN(InvalidOperation, qnan, rotinvalid)
+	}
+	if x.IsInf(0) {
+		return z.Copy(x)
+	}
+
+	m := x.coeff(p)
+	if n < 0 {
+		n += p
+	}
+	if n != 0 && n != p {
+		// m = (m % 10**(p-n))*10**n + m / 10**(p-n)
+		var hi big.Int
+		hi.QuoRem(m, arith.BigPow10(uint64(p-n)), m)
+		arith.MulBigPow10(m, m, uint64(n))
+		m.Add(m, &hi)
+	}
+	return z.setCoeff(m, x.form&signbit, x.exp)
+}
+
 func (c Context) shiftl(z *Big, n uint64) {
 	if z.isZero() {
 		return
@@ -2851,16 +4124,14 @@ func (c Context) shiftr(z *Big, n uint64) bool {
 		return false
 	}
 
-	m := c.RoundingMode
 	if z.isCompact() {
 		if y, ok := arith.Pow10(n); ok {
-			return z.quo(m, z.compact, z.form, y, 0)
+			return z.quo(c, z.compact, z.form, y, 0)
 		}
 		z.unscaled.SetUint64(z.compact)
 		z.compact = cst.Inflated
 	}
-	var r big.Int
-	return z.quoBig(m, &z.unscaled, z.form, arith.BigPow10(n), 0, &r)
+	return z.quoPow10(c, n)
 }
 
 // Sqrt sets z to the square root of x and returns z.
@@ -2872,7 +4143,7 @@ func (c Context) Sqrt(z, x *Big) *Big {
 	ideal := -((-x.Scale() - (-x.Scale() & 1)) / 2)
 	if xs := x.Sign(); xs <= 0 {
 		if xs == 0 {
-			return z.SetMantScale(0, ideal).CopySign(z, x)
+			return c.finish(z.SetMantScale(0, ideal).CopySign(z, x))
 		}
 		z.Context.Conditions |= InvalidOperation
 		return z.SetNaN(false)
@@ -2883,6 +4154,12 @@ func (c Context) Sqrt(z, x *Big) *Big {
 		return z.SetInf(false)
 	}
 
+	// x is needed to check the root.
+	if z == x {
+		x = getDec(c).Copy(x)
+		defer putDec(x)
+	}
+
 	var (
 		prec = c.precision()
 		ctx  = Context{Precision: prec}
@@ -2925,12 +4202,19 @@ func (c Context) Sqrt(z, x *Big) *Big {
 		// p := min(2*p - 2, maxp)
 		ctx.Precision = min(2*ctx.Precision-2, maxp)
 
+		if z.checkDone(c) {
+			return z
+		}
+
 		// approx := .5*(approx + f/approx)
 		ctx.Mul(z, pt5, ctx.Add(&tmp, z, ctx.Quo(&tmp, f, z)))
 		if ctx.Precision == maxp {
 			break
 		}
 	}
+	if z.checkDone(c) {
+		return z
+	}
 
 	// The paper also specifies an additional code block for
 	// adjusting approx.  This code never went into the branches
@@ -2940,18 +4224,74 @@ func (c Context) Sqrt(z, x *Big) *Big {
 	// anyway.
 
 	z.exp += e / 2
-	ctx.Reduce(z)
-	if z.Precision() <= prec {
-		if !rnd {
-			z.Context.Conditions &= ^Rounded
+
+	// z is within an ulp of the root at maxp digits. Truncate it
+	// to prec+1 digits and compare the square of the truncation,
+	// r, with x. Every rounding boundary is a multiple of half of
+	// r's ulp, so the root is either exact or rounds like r with a
+	// sticky digit appended.
+	ctx.Precision = prec + 1
+	ctx.RoundingMode = ToZero
+	r := ctx.Round(new(Big).Copy(z))
+
+	var (
+		exact  = Context{Precision: UnlimitedPrecision}
+		ulp    = new(Big).SetMantScale(1, r.Scale())
+		sticky = new(Big).SetMantScale(1, r.Scale()+1)
+		cmp    = exact.Mul(&tmp, r, r).Cmp(x)
+	)
+	switch cmp {
+	case +1:
+		// z rounded up past the root.
+		exact.Sub(r, r, sticky)
+	case -1:
+		var s Big
+		exact.Add(&s, r, ulp)
+		switch exact.Mul(&tmp, &s, &s).Cmp(x) {
+		case 0:
+			r, cmp = &s, 0
+		case -1:
+			exact.Add(r, &s, sticky)
+		case +1:
+			exact.Add(r, r, sticky)
 		}
-		if !ixt {
-			z.Context.Conditions &= ^Inexact
+	}
+
+	z.Copy(r)
+	z.Context.Conditions &^= Inexact | Rounded
+	if rnd {
+		z.Context.Conditions |= Rounded
+	}
+	if ixt {
+		z.Context.Conditions |= Inexact
+	}
+	if cmp != 0 {
+		return c.finish(z)
+	}
+
+	// The root is exact, so it takes the exponent closest to the
+	// ideal exponent, floor(x.exp/2).
+	ctx.Reduce(z)
+	if z.Precision() > prec {
+		return c.finish(z)
+	}
+	if n := z.exp + ideal; n > 0 {
+		if m := prec - z.Precision(); n > m {
+			z.Context.Conditions |= Rounded
+			n = m
 		}
+		c.shiftl(z, uint64(n))
+		z.exp -= n
 	}
 	return c.finish(z)
 }
 
+// SqrtContext is like Sqrt, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) SqrtContext(ctx context.Context, z, x *Big) *Big {
+	return c.withDone(ctx).Sqrt(z, x)
+}
+
 // sqrt3 sets z to sqrt(3) and returns z.
 func (c Context) sqrt3(z *Big) *Big {
 	if c.Precision <= constPrec {
@@ -2960,6 +4300,36 @@ func (c Context) sqrt3(z *Big) *Big {
 	return c.Set(z, c.Sqrt(z, three.get()))
 }
 
+// Scaleb sets z to x * 10**y and returns z.
+//
+// y must be an integer with a scale of zero in the range
+// [-2 * (MaxScale + precision), 2 * (MaxScale + precision)],
+// where MaxScale and precision are taken from the Context.
+// Otherwise, z is set to NaN and InvalidOperation is raised.
+func (c Context) Scaleb(z, x, y *Big) *Big {
+	if debug {
+		x.validate()
+		y.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, y, scaleb) {
+		return z
+	}
+
+	n, ok := intOperand(y, 2*(c.emax()+c.precision()))
+	if !ok {
+		return z.setNaN(InvalidOperation, qnan, scalebinvalid)
+	}
+	if x.IsInf(0) {
+		return z.Copy(x)
+	}
+	z.Copy(x)
+	z.exp += n
+	return c.finish(z)
+}
+
 // Set sets z to x and returns z.
 //
 // The result might be rounded, even if z == x.
@@ -2987,11 +4357,75 @@ func (c Context) SetString(z *Big, s string) (*Big, bool) {
 	if _, ok := z.SetString(s); !ok {
 		return nil, false
 	}
+	if z.IsNaN(0) && c.Precision != UnlimitedPrecision {
+		// The diagnostic information must fit in the coefficient.
+		n := c.precision()
+		if c.Clamp {
+			n--
+		}
+		if z.compact != 0 && arith.Length(z.compact) > n {
+			z.form = qnan
+			z.compact = 0
+			z.Context.Conditions |= ConversionSyntax
+		}
+		return z, true
+	}
 	return c.finish(z), true
 }
 
+// Shift sets z to x with the digits of its coefficient shifted
+// by y places and returns z. If y is positive the shift is to the
+// left, otherwise it is to the right. Digits shifted into the
+// coefficient are zeros.
+//
+// Before shifting, the coefficient is padded with leading zeros
+// or truncated to the Context's precision and the result is
+// truncated to the same number of digits. If the precision is
+// UnlimitedPrecision the coefficient is neither padded nor
+// truncated. The sign and scale of x are unchanged.
+//
+// y must be an integer with a scale of zero in the range
+// [-precision, precision]. Otherwise, z is set to NaN and
+// InvalidOperation is raised.
+func (c Context) Shift(z, x, y *Big) *Big {
+	if debug {
+		x.validate()
+		y.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, y, shifting) {
+		return z
+	}
+
+	p := c.digits(x)
+	n, ok := intOperand(y, p)
+	if !ok {
+		return z.setNaN(InvalidOperation, qnan, shiftinvalid)
+	}
+	if x.IsInf(0) {
+		return z.Copy(x)
+	}
+
+	m := x.coeff(p)
+	if n > 0 {
+		arith.MulBigPow10(m, m, uint64(n))
+		m.Mod(m, arith.BigPow10(uint64(p)))
+	} else if n < 0 {
+		m.Quo(m, arith.BigPow10(uint64(-n)))
+	}
+	return z.setCoeff(m, x.form&signbit, x.exp)
+}
+
 // Sin returns the sine, in radians, of x.
 //
+// Reducing x modulo pi/2 takes as many digits of 2/pi as x has
+// integral digits plus c's precision. If that exceeds the limit
+// set by ContextOptions.MaxDigits or MaxMemory, or 100,000 digits
+// if neither is set, the result is NaN and InsufficientStorage is
+// raised. Otherwise, the result is correctly rounded.
+//
 // Range:
 //     Input: all real numbers
 //     Output: -1 <= Sin(x) <= 1
@@ -3000,36 +4434,65 @@ func (c Context) SetString(z *Big, s string) (*Big, bool) {
 //     Sin(NaN) = NaN
 //     Sin(Inf) = NaN
 func (c Context) Sin(z, x *Big) *Big {
+	return c.trig(z, x, trigSin, radians, sin)
+}
+
+// SinContext is like Sin, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) SinContext(ctx context.Context, z, x *Big) *Big {
+	return c.withDone(ctx).Sin(z, x)
+}
+
+// Sinh returns the hyperbolic sine of x.
+//
+// Range:
+//     Input: all real numbers
+//     Output: all real numbers
+//
+// Special cases:
+//     Sinh(NaN)  = NaN
+//     Sinh(±Inf) = ±Inf
+//     Sinh(±0)   = ±0
+//
+// If the result is too large for the Context, Sinh raises
+// Overflow.
+func (c Context) Sinh(z, x *Big) *Big {
 	if debug {
 		x.validate()
 	}
 	if z.invalidContext(c) {
 		return z
 	}
-	if z.checkNaNs(x, x, sin) {
+	if z.checkNaNs(x, x, sinh) {
 		return z
 	}
 
-	if !x.IsFinite() {
-		// sin(inf) = NaN
-		// sin(NaN) = NaN
-		z.Context.Conditions |= InvalidOperation
-		return z.SetNaN(false)
+	if x.IsInf(0) {
+		return z.SetInf(x.Signbit())
 	}
-
 	if x.isZero() {
-		// sin(0) = 0
-		return z.setZero(0, 0)
+		return z.setZero(x.form, 0)
 	}
 
-	// Sin(x) = Cos(pi/2 - x)
-	ctx := c.dup()
-	ctx.Precision += defaultExtraPrecision
-	pi2 := ctx.pi2(getDec(ctx))
-	ctx.Cos(z, ctx.Sub(z, pi2, x))
-	putDec(pi2)
+	// Sinh(x) = x + x**3/6 + ...
+	adj := x.adjusted()
+	if c.perturb(z, x, 3*(adj+1), !x.Signbit()) {
+		return z
+	}
 
-	return c.finish(z)
+	// For small x, e**x and e**-x are close to each other.
+	return c.correctlyRound(z, max(-adj, 0), func(ctx Context, t *Big) *Big {
+		// Sinh(x) = sign(x) * (e**|x| - e**-|x|) / 2
+		ctx.Exp(t, t.CopyAbs(x))
+		if t.IsFinite() {
+			u := getDec(ctx)
+			ctx.Quo(u, one.get(), t)
+			ctx.Sub(t, t, u)
+			ctx.Mul(t, t, ptFive.get())
+			putDec(u)
+		}
+		return t.SetSignbit(x.Signbit())
+	})
 }
 
 // Sub sets z to x - y and returns z.
@@ -3071,123 +4534,112 @@ func (c Context) Sub(z, x, y *Big) *Big {
 
 // Tan returns the tangent, in radians, of x.
 //
+// x is limited as described for Sin. Within that limit, the result
+// is correctly rounded.
+//
 // Range:
-//     Input: -pi/2 <= x <= pi/2
+//     Input: all real numbers
 //     Output: all real numbers
 //
 // Special cases:
 //     Tan(NaN) = NaN
 //     Tan(±Inf) = NaN
 func (c Context) Tan(z, x *Big) *Big {
+	return c.trig(z, x, trigTan, radians, tan)
+}
+
+// TanContext is like Tan, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) TanContext(ctx context.Context, z, x *Big) *Big {
+	return c.withDone(ctx).Tan(z, x)
+}
+
+// Tanh returns the hyperbolic tangent of x.
+//
+// Range:
+//     Input: all real numbers
+//     Output: -1 <= Tanh(x) <= 1
+//
+// Special cases:
+//     Tanh(NaN)  = NaN
+//     Tanh(±Inf) = ±1
+//     Tanh(±0)   = ±0
+func (c Context) Tanh(z, x *Big) *Big {
 	if debug {
 		x.validate()
 	}
 	if z.invalidContext(c) {
 		return z
 	}
-	if z.checkNaNs(x, x, sin) {
+	if z.checkNaNs(x, x, tanh) {
 		return z
 	}
 
-	if !x.IsFinite() {
-		// tan(NaN) = NaN
-		// tan(inf) = NaN
-		z.Context.Conditions |= InvalidOperation
-		return z.SetNaN(false)
+	neg := x.Signbit()
+	if x.IsInf(0) {
+		return z.SetMantScale(1, 0).SetSignbit(neg)
 	}
-
-	ctx := c.dup()
-	ctx.Precision += defaultExtraPrecision
-
-	x0, ok := ctx.prepTan(z, x)
-	if !ok {
-		z.Context.Conditions |= InvalidOperation
-		return z.SetNaN(false)
+	if x.isZero() {
+		return z.setZero(x.form, 0)
 	}
 
-	// tan(x) = sign(x)*sqrt(1/cos(x)^2-1)
-
-	// tangent has an asymptote at pi/2 and we'll need more
-	// precision as we get closer the reason we need it is as we
-	// approach pi/2 is due to the squaring portion, it will
-	// cause the small value of Cosine to be come extremely
-	// small. we COULD fix it by simply doubling the precision,
-	// however, when the precision gets larger it will be
-	// a significant impact to performance, instead we'll only
-	// add the extra precision when we need it by using the
-	// difference to see how much extra precision we need we'll
-	// speed things up by only using a quick compare to see if we
-	// need to do a deeper inspection.
-	tctx := ctx.dup()
-	tmp := getDec(tctx)
-	defer putDec(tmp)
-
-	if x.CmpAbs(onePtFour.get()) >= 0 {
-		if x.Signbit() {
-			ctx.Add(tmp, x, ctx.pi2(tmp))
-		} else {
-			ctx.Sub(tmp, x, ctx.pi2(tmp))
-		}
-		tctx.Precision += tmp.Scale() - tmp.Precision()
+	// Tanh(x) = x - x**3/3 + ...
+	adj := x.adjusted()
+	if c.perturb(z, x, 3*(adj+1), neg) {
+		return z
 	}
 
-	tctx.Cos(tmp, x0)
-	ctx.Mul(tmp, tmp, tmp)
-	ctx.Quo(tmp, one.get(), tmp)
-	ctx.Sub(tmp, tmp, one.get())
-	tctx.Sqrt(tmp, tmp)
-	if x0.Signbit() {
-		tmp.CopyNeg(tmp)
+	// Tanh(x) = sign(x) * (1 - 2e**(-2|x|) + ...), and for
+	//
+	//    |x| >= 1.2 * (prec + 3)
+	//
+	// 2e**(-2|x|) < 10**-(prec + 2).
+	prec := c.precision()
+	var t Big
+	if adj > 19 || adj >= 0 && x.CmpAbs(t.SetUint64(uint64(12*(prec+3)/10))) >= 0 {
+		t.SetMantScale(1, 0).SetSignbit(neg)
+		c.perturb(z, &t, -(prec + 2), neg)
+		return z
 	}
-	ctx.Precision -= defaultExtraPrecision
-	return ctx.Set(z, tmp)
+
+	// For small x, e**2|x| is close to 1.
+	return c.correctlyRound(z, max(-adj, 0), func(ctx Context, t *Big) *Big {
+		// Tanh(x) = sign(x) * (e**2|x| - 1) / (e**2|x| + 1)
+		t.CopyAbs(x)
+		ctx.Mul(t, t, two.get())
+		ctx.Exp(t, t)
+		u := getDec(ctx)
+		ctx.Add(u, t, one.get())
+		ctx.Sub(t, t, one.get())
+		ctx.Quo(t, t, u)
+		putDec(u)
+		return t.SetSignbit(neg)
+	})
 }
 
-func (c Context) prepTan(z, x *Big) (*Big, bool) {
-	if z == x {
-		x = getDec(c)
-		defer putDec(x)
+// Trunc sets z to the integer part of x and returns z.
+//
+// Special cases:
+//     Trunc(±0)   = ±0
+//     Trunc(±Inf) = ±Inf
+//     Trunc(NaN)  = NaN
+//
+// Like RoundToIntegral, Trunc does not raise Inexact or Rounded.
+func (c Context) Trunc(z, x *Big) *Big {
+	if z.invalidContext(c) {
+		return z
 	}
-
-	if pi2 := c.pi2(z); x.CmpAbs(pi2) >= 0 {
-		// For tan to work correctly the input must be in (-2pi,
-		// 2pi)
-		if x.Signbit() {
-			c.Add(z, x, pi2)
-		} else {
-			c.Sub(z, x, pi2)
-		}
-
-		var tmp Big
-		c.QuoInt(z, z, c.pi(&tmp))
-		if x.Signbit() {
-			c.Sub(z, z, one.get())
-		} else {
-			c.Add(z, z, one.get())
-		}
-
-		v, ok := z.Int64()
-		if !ok {
-			return nil, false
-		}
-		uv := arith.Abs(v)
-
-		// Adjust so we have ceil(v/10) + ctx.Precision, but check for overflows.
-		// 1+((v-1)/10) will be widly incorrect for v == 0, but x/y = 0 iff
-		// x = 0 and y != 0. In this case, -2pi <= x >= 2pi, so we're fine.
-		prec, carry := bits.Add64(1+((uv-1)/10), uint64(c.Precision), 0)
-		if carry != 0 || prec > arith.MaxInt {
-			return nil, false
-		}
-		pctx := Context{Precision: int(prec)}
-
-		pctx.Mul(z, pctx.pi(&tmp), z)
-
-		c.Precision = c.precision() + 1
-		// so toRemove = m*Pi so |x-toRemove| < Pi/2
-		c.Sub(z, x, z)
-	} else {
-		z.Copy(x)
+	if z.CheckNaNs(x, nil) {
+		return z
 	}
-	return z, true
+	c.RoundingMode = ToZero
+	return c.RoundToIntegral(z.Copy(x))
+}
+
+// Xor sets z to the digit-wise logical exclusive OR of x and y
+// and returns z.
+//
+// See And for the requirements of logical operands.
+func (c Context) Xor(z, x, y *Big) *Big {
+	return c.logical(z, x, y, func(a, b byte) byte { return (a ^ b) | '0' })
 }
@@ -6,11 +6,14 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
+
+	"github.com/ericlagergren/decimal/internal/arith"
 )
 
 func TestMain(m *testing.M) {
@@ -254,6 +257,14 @@ func equal(x, y *Big) bool {
 	return cmp && scl && prec
 }
 
+// str is like x.String, but omits NaN payloads.
+func str(x *Big) string {
+	if x.IsNaN(0) {
+		return strings.TrimRight(x.String(), "0123456789")
+	}
+	return x.String()
+}
+
 var rnd = rand.New(rand.NewSource(0))
 
 func rndn(min, max int) int {
@@ -585,6 +596,37 @@ func TestCmpTotal(t *testing.T) {
 	}
 }
 
+func TestCmpSignal(t *testing.T) {
+	ctx := Context{
+		Precision:     9,
+		OperatingMode: GDA,
+		MaxScale:      999,
+		MinScale:      -999,
+	}
+	for i, test := range []struct {
+		x, y, r string
+	}{
+		{"1", "2", "-1"},
+		{"2.0", "2", "0"},
+		{"-3", "-7", "1"},
+		{"-0", "0", "0"},
+		{"NaN", "1", "NaN"},
+		{"1", "sNaN", "NaN"},
+	} {
+		x, _ := new(Big).SetString(test.x)
+		y, _ := new(Big).SetString(test.y)
+		z := ctx.CmpSignal(new(Big), x, y)
+		if s := str(z); s != test.r {
+			t.Fatalf("#%d: CmpSignal(%s, %s): got %s, wanted %s",
+				i, test.x, test.y, s, test.r)
+		}
+		if z.IsNaN(0) != (z.Context.Conditions&InvalidOperation != 0) {
+			t.Fatalf("#%d: CmpSignal(%s, %s): invalid conditions: %s",
+				i, test.x, test.y, z.Context.Conditions)
+		}
+	}
+}
+
 func TestCos(t *testing.T) {
 	const N = 100
 	diff := WithPrecision(N)
@@ -664,6 +706,90 @@ func TestExp(t *testing.T) {
 	}
 }
 
+func TestExpm1Log1p(t *testing.T) {
+	fns := map[string]func(Context, *Big, *Big) *Big{
+		"Expm1": Context.Expm1,
+		"Log1p": Context.Log1p,
+	}
+	for i, tc := range []struct {
+		fn   string
+		prec int
+		mode RoundingMode
+		x, r string
+		cond Condition
+	}{
+		{"Expm1", 34, ToNearestEven, "1E-10", "1.000000000050000000001666666666708E-10", Inexact | Rounded},
+		{"Expm1", 34, ToZero, "-0.001", "-0.0009995001666250083319446428323440252", Inexact | Rounded},
+		{"Expm1", 16, ToPositiveInf, "1E-20", "1.000000000000001E-20", Inexact | Rounded},
+		{"Expm1", 16, ToZero, "-50", "-0.9999999999999999", Inexact | Rounded},
+		{"Expm1", 50, ToNegativeInf, "0.5", "0.64872127070012814684865078781416357165377610071014", Inexact | Rounded},
+		{"Expm1", 16, ToNearestEven, "-1", "-0.6321205588285577", Inexact | Rounded},
+		{"Expm1", 16, ToNearestEven, "-0", "-0", 0},
+		{"Expm1", 16, ToNearestEven, "-Inf", "-1", 0},
+		{"Log1p", 34, ToNearestEven, "1E-10", "9.999999999500000000033333333330833E-11", Inexact | Rounded},
+		{"Log1p", 16, ToZero, "1E-20", "9.999999999999999E-21", Inexact | Rounded},
+		{"Log1p", 34, ToPositiveInf, "-0.999", "-6.907755278982137052053974364053092", Inexact | Rounded},
+		{"Log1p", 50, ToNegativeInf, "0.5", "0.40546510810816438197801311546434913657199042346249", Inexact | Rounded},
+		{"Log1p", 16, ToNearestEven, "1E+100", "230.2585092994046", Inexact | Rounded},
+		{"Log1p", 16, ToNearestEven, "-1E-7", "-1.000000050000003E-7", Inexact | Rounded},
+		{"Log1p", 16, ToNearestEven, "-0", "-0", 0},
+		{"Log1p", 16, ToNearestEven, "-1", "-Infinity", 0},
+		{"Log1p", 16, ToNearestEven, "-1.5", "NaN", InvalidOperation},
+	} {
+		x, _ := new(Big).SetString(tc.x)
+		ctx := Context{Precision: tc.prec, RoundingMode: tc.mode}
+		z := WithContext(ctx)
+		fns[tc.fn](ctx, z, x)
+		if !strEq(z, tc.r) || z.Context.Conditions != tc.cond {
+			t.Fatalf("#%d: %s(%s): wanted (%s, %s), got (%s, %s)",
+				i, tc.fn, tc.x, tc.r, tc.cond, z, z.Context.Conditions)
+		}
+	}
+}
+
+func TestFrexpLdexp(t *testing.T) {
+	ctx := Context{Precision: 16, MaxScale: 384, MinScale: -383}
+	for i, tc := range []struct {
+		x, frac string
+		exp     int
+	}{
+		{"123.456", "0.123456", 3},
+		{"-0.00123", "-0.123", -2},
+		{"1E+384", "0.1", 385},
+		{"9.99999999999999999", "0.1000000000000000", 2},
+		{"-0", "-0", 0},
+		{"Inf", "Infinity", 0},
+	} {
+		x, _ := new(Big).SetString(tc.x)
+		frac, exp := ctx.Frexp(WithContext(ctx), x)
+		if frac.String() != tc.frac || exp != tc.exp {
+			t.Fatalf("#%d: Frexp(%s): wanted (%s, %d), got (%s, %d)",
+				i, tc.x, tc.frac, tc.exp, frac, exp)
+		}
+		if z := ctx.Ldexp(WithContext(ctx), frac, exp); z.Cmp(ctx.Set(new(Big), x)) != 0 {
+			t.Fatalf("#%d: Ldexp(%s, %d): wanted %s, got %s", i, frac, exp, x, z)
+		}
+	}
+
+	for i, tc := range []struct {
+		exp  int
+		r    string
+		cond Condition
+	}{
+		{-5, "0.000015", 0},
+		{400, "Infinity", Inexact | Overflow | Rounded},
+		{-400, "0E-398", Clamped | Inexact | Rounded | Subnormal | Underflow},
+		{math.MaxInt32, "Infinity", Inexact | Overflow | Rounded},
+		{math.MinInt32, "0E-398", Clamped | Inexact | Rounded | Subnormal | Underflow},
+	} {
+		z := ctx.Ldexp(WithContext(ctx), New(15, 1), tc.exp)
+		if z.String() != tc.r || z.Context.Conditions != tc.cond {
+			t.Fatalf("#%d: Ldexp(1.5, %d): wanted (%s, %s), got (%s, %s)",
+				i, tc.exp, tc.r, tc.cond, z, z.Context.Conditions)
+		}
+	}
+}
+
 func TestFloor(t *testing.T) {
 	for i, s := range []struct {
 		x, r string
@@ -690,6 +816,89 @@ got   : %s
 	}
 }
 
+func TestHyperbolic(t *testing.T) {
+	fns := map[string]func(Context, *Big, *Big) *Big{
+		"Acosh": Context.Acosh,
+		"Asinh": Context.Asinh,
+		"Atanh": Context.Atanh,
+		"Cosh":  Context.Cosh,
+		"Sinh":  Context.Sinh,
+		"Tanh":  Context.Tanh,
+	}
+	for i, tc := range []struct {
+		fn   string
+		prec int
+		mode RoundingMode
+		x, r string
+		cond Condition
+	}{
+		{"Sinh", 16, ToZero, "3", "10.01787492740990", Inexact | Rounded},
+		{"Sinh", 16, ToZero, "0.001", "0.001000000166666675", Inexact | Rounded},
+		{"Sinh", 5, ToNegativeInf, "2.5", "6.0502", Inexact | Rounded},
+		{"Sinh", 34, ToZero, "0.5", "0.5210953054937473616224256264114915", Inexact | Rounded},
+		{"Sinh", 34, ToNegativeInf, "-1E-7", "-1.000000000000001666666666666667501E-7", Inexact | Rounded},
+		{"Sinh", 50, ToPositiveInf, "25", "36002449668.692936262073731761130596947314436024825", Inexact | Rounded},
+		{"Sinh", 16, ToNearestEven, "1.2345E-12", "1.234500000000000E-12", Inexact | Rounded},
+		{"Sinh", 16, ToPositiveInf, "1.2345E-12", "1.234500000000001E-12", Inexact | Rounded},
+		{"Sinh", 16, ToNearestEven, "-0", "-0", 0},
+		{"Sinh", 16, ToNearestEven, "-Inf", "-Infinity", 0},
+		{"Cosh", 34, ToNegativeInf, "100", "1.344058570908067724206312775790006E+43", Inexact | Rounded},
+		{"Cosh", 50, ToPositiveInf, "2.5", "6.1322894796636861166198523128175629955102859555393", Inexact | Rounded},
+		{"Cosh", 5, ToZero, "-60", "5.7100E+25", Inexact | Rounded},
+		{"Cosh", 34, ToZero, "1.2345E-12", "1.000000000000000000000000761995125", Inexact | Rounded},
+		{"Cosh", 16, ToNearestEven, "1E-20", "1.000000000000000", Inexact | Rounded},
+		{"Cosh", 16, ToPositiveInf, "1E-20", "1.000000000000001", Inexact | Rounded},
+		{"Cosh", 16, ToNearestEven, "0", "1", 0},
+		{"Cosh", 16, ToNearestEven, "-Inf", "Infinity", 0},
+		{"Tanh", 16, ToNegativeInf, "0.5", "0.4621171572600097", Inexact | Rounded},
+		{"Tanh", 16, ToPositiveInf, "0.001", "0.0009999996666668000", Inexact | Rounded},
+		{"Tanh", 34, ToNegativeInf, "0.3", "0.2913126124515909058182212728237659", Inexact | Rounded},
+		{"Tanh", 50, ToPositiveInf, "10", "0.99999999587769276361959283713827574105081461849502", Inexact | Rounded},
+		{"Tanh", 16, ToNearestEven, "-100", "-1.000000000000000", Inexact | Rounded},
+		{"Tanh", 16, ToZero, "100", "0.9999999999999999", Inexact | Rounded},
+		{"Tanh", 16, ToZero, "22.000000000000000000001", "0.9999999999999999", Inexact | Rounded},
+		{"Tanh", 16, ToNearestEven, "Inf", "1", 0},
+		{"Asinh", 5, ToNegativeInf, "1.0001", "0.88144", Inexact | Rounded},
+		{"Asinh", 16, ToZero, "0.999", "0.8806663034322094", Inexact | Rounded},
+		{"Asinh", 50, ToNegativeInf, "-60", "-4.7875611799938102655284400278264752123719405389401", Inexact | Rounded},
+		{"Asinh", 34, ToZero, "40", "4.382182848065498306761166253375664", Inexact | Rounded},
+		{"Asinh", 16, ToNearestEven, "1E+100", "230.9516564799645", Inexact | Rounded},
+		{"Acosh", 5, ToZero, "1.0001", "0.014142", Inexact | Rounded},
+		{"Acosh", 16, ToNegativeInf, "1.5", "0.9624236501192068", Inexact | Rounded},
+		{"Acosh", 50, ToNegativeInf, "2.5", "1.5667992369724110786640568625804834938620823510926", Inexact | Rounded},
+		{"Acosh", 16, ToNearestEven, "1", "0", 0},
+		{"Acosh", 16, ToNearestEven, "1.00000000000000000001", "1.414213562373095E-10", Inexact | Rounded},
+		{"Acosh", 16, ToNearestEven, "0.999", "NaN", InvalidOperation},
+		{"Acosh", 16, ToNearestEven, "-Inf", "NaN", InvalidOperation},
+		{"Atanh", 34, ToPositiveInf, "-0.5", "-0.5493061443340548456976226184612628", Inexact | Rounded},
+		{"Atanh", 34, ToNearestEven, "0.001", "0.001000000333333533333476190587301678", Inexact | Rounded},
+		{"Atanh", 50, ToNegativeInf, "-1E-7", "-1.0000000000000033333333333333533333333333334761905E-7", Inexact | Rounded},
+		{"Atanh", 16, ToNearestEven, "0.9999999999999999999", "22.22113197372341", Inexact | Rounded},
+		{"Atanh", 16, ToNearestEven, "-1", "-Infinity", DivisionByZero},
+		{"Atanh", 16, ToNearestEven, "1.0001", "NaN", InvalidOperation},
+	} {
+		x, _ := new(Big).SetString(tc.x)
+		ctx := Context{Precision: tc.prec, RoundingMode: tc.mode}
+		z := WithContext(ctx)
+		fns[tc.fn](ctx, z, x)
+		if !strEq(z, tc.r) || z.Context.Conditions != tc.cond {
+			t.Fatalf("#%d: %s(%s): wanted (%s, %s), got (%s, %s)",
+				i, tc.fn, tc.x, tc.r, tc.cond, z, z.Context.Conditions)
+		}
+		if tc.cond&InvalidOperation != 0 && z.Payload() == 0 {
+			t.Fatalf("#%d: %s(%s): missing NaN payload", i, tc.fn, tc.x)
+		}
+	}
+
+	ctx := Context{Precision: 16, MaxScale: 384, MinScale: -383}
+	for i, fn := range []func(Context, *Big, *Big) *Big{Context.Sinh, Context.Cosh} {
+		z := WithContext(ctx)
+		if fn(ctx, z, New(1000, 0)); !z.IsInf(+1) || z.Context.Conditions&Overflow == 0 {
+			t.Fatalf("#%d: expected overflow, got (%s, %s)", i, z, z.Context.Conditions)
+		}
+	}
+}
+
 func TestBrokenJobs_Exp(t *testing.T) {
 	for i, s := range []struct {
 		x, r string
@@ -904,6 +1113,220 @@ func TestLog10(t *testing.T) {
 	}
 }
 
+func TestLogBase(t *testing.T) {
+	for i, tc := range []struct {
+		x, b string
+		r    string
+		prec int
+		mode RoundingMode
+		cond Condition
+	}{
+		{"8", "2", "3", 16, ToNearestEven, 0},
+		{"0.125", "2", "-3", 16, ToNearestEven, 0},
+		{"4096", "8", "4", 16, ToNearestEven, 0},
+		{"64", "0.5", "-6", 16, ToNearestEven, 0},
+		{"1", "7", "0", 16, ToNearestEven, 0},
+		{"1E+9", "100", "4.5", 16, ToNearestEven, 0},
+		{"10", "100", "0.5", 34, ToPositiveInf, 0},
+		{"2", "4", "0.5", 16, ToNearestEven, 0},
+		{"2", "1024", "0.1", 16, ToNearestEven, 0},
+		{"4", "8", "0.6666666666666667", 16, ToNearestEven, Inexact | Rounded},
+		{"1E-7", "0.1", "7", 16, ToNearestEven, 0},
+		{"3.375", "1.5", "3", 16, ToNearestEven, 0},
+		{"27", "2", "4.754887502163469", 16, ToNearestEven, Inexact | Rounded},
+		{"1E+9", "2", "29.89735285398626", 16, ToZero, Inexact | Rounded},
+		{"123.456", "10", "2.091512201627772", 16, ToNearestEven, Inexact | Rounded},
+		{"7", "3", "1.7712", 5, ToNegativeInf, Inexact | Rounded},
+		{"0", "2", "-Infinity", 16, ToNearestEven, 0},
+		{"0", "0.5", "Infinity", 16, ToNearestEven, 0},
+		{"Inf", "0.5", "-Infinity", 16, ToNearestEven, 0},
+		{"-1", "2", "NaN", 16, ToNearestEven, InvalidOperation},
+		{"2", "1", "NaN", 16, ToNearestEven, InvalidOperation},
+		{"2", "-2", "NaN", 16, ToNearestEven, InvalidOperation},
+		{"2", "Inf", "NaN", 16, ToNearestEven, InvalidOperation},
+	} {
+		x, _ := new(Big).SetString(tc.x)
+		b, _ := new(Big).SetString(tc.b)
+		ctx := Context{Precision: tc.prec, RoundingMode: tc.mode}
+		z := WithContext(ctx)
+		ctx.LogBase(z, x, b)
+		if !strEq(z, tc.r) || z.Context.Conditions != tc.cond {
+			t.Fatalf("#%d: LogBase(%s, %s): wanted (%s, %s), got (%s, %s)",
+				i, tc.x, tc.b, tc.r, tc.cond, z, z.Context.Conditions)
+		}
+		if tc.b == "2" {
+			z := WithContext(ctx)
+			if ctx.Log2(z, x); !strEq(z, tc.r) {
+				t.Fatalf("#%d: Log2(%s): wanted %s, got %s", i, tc.x, tc.r, z)
+			}
+		}
+	}
+
+	ctx := Context{Precision: 16}
+	if z := ctx.Log2(new(Big), New(-1, 0)); z.Payload() != logneg {
+		t.Fatalf("Log2(-1): wanted payload %q, got %q", logneg, z.Payload())
+	}
+}
+
+func TestLogb(t *testing.T) {
+	ctx := Context{
+		Precision:     9,
+		OperatingMode: GDA,
+		MaxScale:      999,
+		MinScale:      -999,
+	}
+	for i, test := range []struct {
+		x, r string
+	}{
+		{"250", "2"},
+		{"2.50", "0"},
+		{"0.03", "-2"},
+		{"-1E+500", "500"},
+		{"-Inf", "Infinity"},
+		{"0", "-Infinity"},
+	} {
+		x, _ := new(Big).SetString(test.x)
+		z := ctx.Logb(new(Big), x)
+		if s := str(z); s != test.r {
+			t.Fatalf("#%d: Logb(%s): got %s, wanted %s", i, test.x, s, test.r)
+		}
+	}
+}
+
+func TestLogical(t *testing.T) {
+	ctx := Context{
+		Precision:     9,
+		OperatingMode: GDA,
+		MaxScale:      999,
+		MinScale:      -999,
+	}
+	for i, test := range []struct {
+		x, y         string
+		and, or, xor string
+		invert       string
+	}{
+		{"1100", "1010", "1000", "1110", "110", "111110011"},
+		{"1", "0", "0", "1", "1", "111111110"},
+		{"111111111", "10", "10", "111111111", "111111101", "0"},
+		// Operands are truncated to the precision.
+		{"1101", "1111111111", "1101", "111111111", "111110010", "111110010"},
+		{"2", "1", "NaN", "NaN", "NaN", "NaN"},
+		{"1E+1", "1", "NaN", "NaN", "NaN", "NaN"},
+		{"-1", "1", "NaN", "NaN", "NaN", "NaN"},
+	} {
+		x, _ := new(Big).SetString(test.x)
+		y, _ := new(Big).SetString(test.y)
+		for _, op := range []struct {
+			name string
+			z    *Big
+			r    string
+		}{
+			{"And", ctx.And(new(Big), x, y), test.and},
+			{"Or", ctx.Or(new(Big), x, y), test.or},
+			{"Xor", ctx.Xor(new(Big), x, y), test.xor},
+			{"Invert", ctx.Invert(new(Big), x), test.invert},
+		} {
+			if s := str(op.z); s != op.r {
+				t.Fatalf("#%d: %s(%s, %s): got %s, wanted %s",
+					i, op.name, test.x, test.y, s, op.r)
+			}
+		}
+	}
+}
+
+func TestModDim(t *testing.T) {
+	fns := map[string]func(Context, *Big, *Big, *Big) *Big{
+		"Dim": Context.Dim,
+		"Mod": Context.Mod,
+	}
+	for i, tc := range []struct {
+		fn      string
+		x, y, r string
+		cond    Condition
+	}{
+		{"Mod", "1E+30", "7", "1", 0},
+		{"Mod", "1E+50", "0.3", "0.1", 0},
+		{"Mod", "1E+999999999", "7", "6", 0},
+		{"Mod", "-10", "3", "-1", 0},
+		{"Mod", "10", "-3", "1", 0},
+		{"Mod", "-6", "3", "-0", 0},
+		{"Mod", "5.5", "-Inf", "5.5", 0},
+		{"Mod", "Inf", "2", "NaN", InvalidOperation},
+		{"Mod", "1", "0", "NaN", InvalidOperation | DivisionByZero},
+		{"Dim", "10", "-3", "13", 0},
+		{"Dim", "-10", "3", "0", 0},
+		{"Dim", "1E+30", "7", "1.000000000000000E+30", Inexact | Rounded},
+		{"Dim", "Inf", "2", "Infinity", 0},
+		{"Dim", "5.5", "Inf", "0", 0},
+		{"Dim", "-Inf", "-Inf", "NaN", InvalidOperation},
+	} {
+		ctx := Context{Precision: 16}
+		z := WithContext(ctx)
+		xy := decs(tc.x, tc.y)
+		fns[tc.fn](ctx, z, xy[0], xy[1])
+		if !strEq(z, tc.r) || z.Context.Conditions != tc.cond {
+			t.Fatalf("#%d: %s(%s, %s): wanted (%s, %s), got (%s, %s)",
+				i, tc.fn, tc.x, tc.y, tc.r, tc.cond, z, z.Context.Conditions)
+		}
+	}
+}
+
+func TestModf(t *testing.T) {
+	for i, tc := range []struct {
+		x, n, f, trunc string
+	}{
+		{"123.456", "123", "0.456", "123"},
+		{"-0.00123", "-0", "-0.00123", "-0"},
+		{"-6", "-6", "-0", "-6"},
+		{"9.99999999999999999", "10", "0E-14", "9"},
+		{"-Inf", "-Infinity", "NaN", "-Infinity"},
+		{"NaN", "NaN", "NaN", "NaN"},
+	} {
+		ctx := Context{Precision: 16}
+		x, _ := new(Big).SetString(tc.x)
+		n, f := ctx.Modf(WithContext(ctx), x, WithContext(ctx))
+		if !strEq(n, tc.n) || !strEq(f, tc.f) {
+			t.Fatalf("#%d: Modf(%s): wanted (%s, %s), got (%s, %s)",
+				i, tc.x, tc.n, tc.f, n, f)
+		}
+		if r := ctx.Trunc(WithContext(ctx), x); !strEq(r, tc.trunc) || r.Context.Conditions != 0 {
+			t.Fatalf("#%d: Trunc(%s): wanted %s, got %s (%s)",
+				i, tc.x, tc.trunc, r, r.Context.Conditions)
+		}
+	}
+}
+
+func TestNextToward(t *testing.T) {
+	ctx := Context{
+		Precision:     9,
+		OperatingMode: GDA,
+		MaxScale:      999,
+		MinScale:      -999,
+	}
+	for i, test := range []struct {
+		x, y, r string
+		c       Condition
+	}{
+		{"1", "2", "1.00000001", 0},
+		{"1", "0", "0.999999999", 0},
+		{"-1", "-2", "-1.00000001", 0},
+		{"2", "2", "2", 0},
+		{"-0", "0", "0", 0},
+		{"0", "1", "1E-1007", Underflow | Subnormal | Inexact | Rounded},
+		{"9.99999999E+999", "Inf", "Infinity", Overflow | Inexact | Rounded},
+	} {
+		x, _ := new(Big).SetString(test.x)
+		y, _ := new(Big).SetString(test.y)
+		z := ctx.NextToward(new(Big), x, y)
+		if s := str(z); s != test.r || z.Context.Conditions != test.c {
+			t.Fatalf(`#%d: NextToward(%s, %s)
+wanted: %s (%s)
+got   : %s (%s)
+`, i, test.x, test.y, test.r, test.c, s, z.Context.Conditions)
+		}
+	}
+}
+
 func TestParallel(t *testing.T) {
 	x := New(4, 0)
 	y := New(3, 0)
@@ -1023,6 +1446,150 @@ got   : %q
 	}
 }
 
+func TestRemNear(t *testing.T) {
+	ctx := Context{
+		Precision:     9,
+		OperatingMode: GDA,
+		MaxScale:      999,
+		MinScale:      -999,
+	}
+	for i, test := range []struct {
+		x, y, r string
+	}{
+		{"10", "3", "1"},
+		{"10", "6", "-2"},
+		{"-10", "6", "2"},
+		{"10.2", "1", "0.2"},
+		{"10", "0.3", "0.1"},
+		{"3.6", "1.3", "-0.3"},
+		{"-4", "2", "-0"},
+		{"1", "0", "NaN"},
+		{"Inf", "1", "NaN"},
+		{"1", "Inf", "1"},
+		{"1E+10", "3", "NaN"},
+	} {
+		x, _ := new(Big).SetString(test.x)
+		y, _ := new(Big).SetString(test.y)
+		z := ctx.RemNear(new(Big), x, y)
+		if s := str(z); s != test.r {
+			t.Fatalf("#%d: RemNear(%s, %s): got %s, wanted %s",
+				i, test.x, test.y, s, test.r)
+		}
+	}
+}
+
+func TestRoundToIntegral(t *testing.T) {
+	for i, test := range []struct {
+		x, r string
+	}{
+		{"2.5", "2"},
+		{"3.5", "4"},
+		{"-2.5", "-2"},
+		{"9.5", "10"},
+		{"101.6", "102"},
+		{"7E+3", "7E+3"},
+		{"-0.4", "-0"},
+	} {
+		z, _ := new(Big).SetString(test.x)
+		z.Context.OperatingMode = GDA
+		z.RoundToIntegral()
+		if s := str(z); s != test.r {
+			t.Fatalf("#%d: RoundToIntegral(%s): got %s, wanted %s",
+				i, test.x, s, test.r)
+		}
+		if z.Context.Conditions != 0 {
+			t.Fatalf("#%d: RoundToIntegral(%s): unexpected conditions: %s",
+				i, test.x, z.Context.Conditions)
+		}
+	}
+}
+
+func TestRoot(t *testing.T) {
+	for i, tc := range []struct {
+		x    string
+		n    int
+		r    string
+		prec int
+		mode RoundingMode
+		cond Condition
+	}{
+		{"27", 3, "3", 16, ToNearestEven, 0},
+		{"-27", 3, "-3", 16, ToNearestEven, 0},
+		{"-8.000", 3, "-2.0", 16, ToNearestEven, 0},
+		{"0.001", 3, "0.1", 16, ToNearestEven, 0},
+		{"1E+9", 3, "1E+3", 16, ToNearestEven, 0},
+		{"4096", 4, "8", 16, ToNearestEven, 0},
+		{"1E+100", 4, "1E+25", 16, ToNearestEven, 0},
+		{"-0.00032", 5, "-0.2", 16, ToNearestEven, 0},
+		{"1.0000000000000000000000000000000000000003", 1, "1.000000000000000", 16, ToNearestEven, Inexact | Rounded},
+		// The root is exact but has more digits than the precision.
+		{"1.000000000000000045000000000000000675000000000000003375", 3, "1.000000000000000", 16, ToNearestEven, Inexact | Rounded},
+		{"1.000000000000000045000000000000000675000000000000003375", 3, "1.000000000000001", 16, ToPositiveInf, Inexact | Rounded},
+		{"2", 3, "1.259921049894873", 16, ToNearestEven, Inexact | Rounded},
+		{"-2", 3, "-1.259921049894873", 16, ToNearestEven, Inexact | Rounded},
+		{"10", 5, "1.584893192461113", 16, ToNearestEven, Inexact | Rounded},
+		{"1E-10", 3, "0.0004641588833612778", 16, ToZero, Inexact | Rounded},
+		{"7", 7, "1.32047", 6, ToPositiveInf, Inexact | Rounded},
+		{"-0", 3, "-0", 16, ToNearestEven, 0},
+		{"0E-7", 3, "0.000", 16, ToNearestEven, 0},
+		{"-Inf", 3, "-Infinity", 16, ToNearestEven, 0},
+		{"16", 2, "4", 16, ToNearestEven, 0},
+		{"-16", 4, "NaN", 16, ToNearestEven, InvalidOperation},
+		{"16", 0, "NaN", 16, ToNearestEven, InvalidOperation},
+		{"16", -2, "NaN", 16, ToNearestEven, InvalidOperation},
+	} {
+		x, _ := new(Big).SetString(tc.x)
+		ctx := Context{Precision: tc.prec, RoundingMode: tc.mode}
+		z := WithContext(ctx)
+		ctx.Root(z, x, tc.n)
+		if !strEq(z, tc.r) || z.Context.Conditions != tc.cond {
+			t.Fatalf("#%d: Root(%s, %d): wanted (%s, %s), got (%s, %s)",
+				i, tc.x, tc.n, tc.r, tc.cond, z, z.Context.Conditions)
+		}
+		if tc.n == 3 {
+			z := WithContext(ctx)
+			if ctx.Cbrt(z, x); !strEq(z, tc.r) {
+				t.Fatalf("#%d: Cbrt(%s): wanted %s, got %s", i, tc.x, tc.r, z)
+			}
+		}
+	}
+
+	// The result may alias x.
+	x := New(-125, 0)
+	if Context64.Cbrt(x, x); x.String() != "-5" {
+		t.Fatalf("wanted -5, got %s", x)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	ctx := Context{
+		Precision:     9,
+		OperatingMode: GDA,
+		MaxScale:      999,
+		MinScale:      -999,
+	}
+	for i, test := range []struct {
+		x, y, r string
+	}{
+		{"34", "8", "400000003"},
+		{"12", "9", "12"},
+		{"123456789", "-2", "891234567"},
+		{"123456789", "2", "345678912"},
+		{"-1234.5", "3", "-1234500.0"},
+		{"1", "10", "NaN"},
+		{"1", "1.5", "NaN"},
+		{"Inf", "1", "Infinity"},
+	} {
+		x, _ := new(Big).SetString(test.x)
+		y, _ := new(Big).SetString(test.y)
+		z := ctx.Rotate(new(Big), x, y)
+		if s := str(z); s != test.r {
+			t.Fatalf("#%d: Rotate(%s, %s): got %s, wanted %s",
+				i, test.x, test.y, s, test.r)
+		}
+	}
+}
+
 func TestBig_Scan(t *testing.T) {
 	// TODO(eric): this
 }
@@ -1052,6 +1619,61 @@ func isSpecial(f float64) bool {
 	return math.IsInf(f, 0) || math.IsNaN(f)
 }
 
+func TestScaleb(t *testing.T) {
+	ctx := Context{
+		Precision:     9,
+		OperatingMode: GDA,
+		MaxScale:      999,
+		MinScale:      -999,
+	}
+	for i, test := range []struct {
+		x, y, r string
+	}{
+		{"7.50", "10", "7.50E+10"},
+		{"7.50", "-2", "0.0750"},
+		{"-7.50", "3", "-7.50E+3"},
+		{"1E+998", "5", "Infinity"},
+		{"1", "0.5", "NaN"},
+		{"1", "Inf", "NaN"},
+	} {
+		x, _ := new(Big).SetString(test.x)
+		y, _ := new(Big).SetString(test.y)
+		z := ctx.Scaleb(new(Big), x, y)
+		if s := str(z); s != test.r {
+			t.Fatalf("#%d: Scaleb(%s, %s): got %s, wanted %s",
+				i, test.x, test.y, s, test.r)
+		}
+	}
+}
+
+func TestShift(t *testing.T) {
+	ctx := Context{
+		Precision:     9,
+		OperatingMode: GDA,
+		MaxScale:      999,
+		MinScale:      -999,
+	}
+	for i, test := range []struct {
+		x, y, r string
+	}{
+		{"34", "8", "400000000"},
+		{"12", "9", "0"},
+		{"123456789", "-2", "1234567"},
+		{"123456789", "2", "345678900"},
+		{"-1234.5", "3", "-1234500.0"},
+		{"1", "-10", "NaN"},
+		{"Inf", "1", "Infinity"},
+	} {
+		x, _ := new(Big).SetString(test.x)
+		y, _ := new(Big).SetString(test.y)
+		z := ctx.Shift(new(Big), x, y)
+		if s := str(z); s != test.r {
+			t.Fatalf("#%d: Shift(%s, %s): got %s, wanted %s",
+				i, test.x, test.y, s, test.r)
+		}
+	}
+}
+
 func TestSin(t *testing.T) {
 	const N = 100
 	diff := new(Big)
@@ -1110,6 +1732,61 @@ func TestBig_Format(t *testing.T) {
 	}
 }
 
+func TestBinarySplit_Parallel(t *testing.T) {
+	for i, test := range [...]struct {
+		name string
+		prec int
+		fn   func(c Context, z *Big) *Big
+	}{
+		{"Pi", 5000, func(c Context, z *Big) *Big { return c.Pi(z) }},
+		{"E", 5000, func(c Context, z *Big) *Big { return c.E(z) }},
+		{"Atan", 3000, func(c Context, z *Big) *Big { return c.Atan(z, New(7, 1)) }},
+		{"Cos", 3000, func(c Context, z *Big) *Big { return c.Cos(z, New(123, 2)) }},
+	} {
+		c := Context{Precision: test.prec}
+		want := test.fn(c, WithContext(c))
+		for _, n := range []int{2, 3, 8} {
+			c.Options = &ContextOptions{Parallelism: n}
+			z := test.fn(c, WithContext(c))
+			if z.Cmp(want) != 0 || z.Scale() != want.Scale() {
+				t.Fatalf("#%d: %s: Parallelism %d: result differs from serial result",
+					i, test.name, n)
+			}
+		}
+	}
+}
+
+func TestBigThresholds(t *testing.T) {
+	tests := [...]struct {
+		name string
+		fn   func(c Context, z *Big) *Big
+	}{
+		{"Pi", func(c Context, z *Big) *Big { return c.Pi(z) }},
+		{"Sqrt", func(c Context, z *Big) *Big { return c.Sqrt(z, New(2, 0)) }},
+		{"Exp", func(c Context, z *Big) *Big { return c.Exp(z, New(15, 1)) }},
+		{"Quo", func(c Context, z *Big) *Big {
+			x := c.Pi(new(Big))
+			return c.Quo(z, x, c.Sqrt(new(Big), x))
+		}},
+	}
+	c := Context{Precision: 3000}
+	want := make([]*Big, len(tests))
+	for i, test := range tests {
+		want[i] = test.fn(c, WithContext(c))
+	}
+
+	ntt, newton := arith.NTTThreshold, arith.NewtonThreshold
+	arith.NTTThreshold, arith.NewtonThreshold = 20, 20
+	defer func() { arith.NTTThreshold, arith.NewtonThreshold = ntt, newton }()
+
+	for i, test := range tests {
+		z := test.fn(c, WithContext(c))
+		if z.Cmp(want[i]) != 0 || z.Scale() != want[i].Scale() {
+			t.Fatalf("#%d: %s: result differs with NTT and Newton division", i, test.name)
+		}
+	}
+}
+
 func TestDecimal_Hypot(t *testing.T) {
 	ctx := Context{Precision: 100}
 	pi := ctx.Pi(new(Big))
@@ -1342,6 +2019,22 @@ func BenchmarkPi(b *testing.B) {
 	}
 }
 
+func BenchmarkPi_Parallel(b *testing.B) {
+	for _, prec := range benchPrecs {
+		b.Run(fmt.Sprintf("%d", prec), func(b *testing.B) {
+			var z Big
+			ctx := Context{
+				Precision: prec,
+				Options:   &ContextOptions{Parallelism: runtime.GOMAXPROCS(0)},
+			}
+			for j := 0; j < b.N; j++ {
+				ctx.Pi(&z)
+			}
+			gB = &z
+		})
+	}
+}
+
 func BenchmarkCos(b *testing.B) {
 	x := new(Big)
 	for i, n := range []string{
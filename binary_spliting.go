@@ -1,6 +1,9 @@
 package decimal
 
 import (
+	"context"
+	"sync"
+
 	"github.com/ericlagergren/decimal/internal/arith"
 )
 
@@ -69,8 +72,41 @@ func (s *apbqBinarySplitState) term(z *Big, ctx Context) *Big {
 // must not be modified by the caller and may only be valid until the next
 // invocation of said function. This allows the implementation to conserve
 // memory usage.
+//
+// If the Context's Options.Parallelism is greater than one, calls to the SplitFuncs
+// are serialized, so they do not need to be safe for concurrent use.
 type SplitFunc func(n uint64) *Big
 
+// parallelSplitTerms is the minimum number of terms in a range before its
+// halves are computed on separate goroutines. Smaller ranges are not worth
+// the overhead.
+const parallelSplitTerms = 128
+
+// splitSem returns a semaphore that limits calculate to ctx.Parallelism - 1
+// additional goroutines, or nil if ctx.Parallelism <= 1.
+func splitSem(ctx Context) chan struct{} {
+	o := ctx.Options
+	if o == nil || o.Parallelism <= 1 {
+		return nil
+	}
+	return make(chan struct{}, o.Parallelism-1)
+}
+
+// syncSplit wraps A, P, B, and Q so they can be called from multiple
+// goroutines. A SplitFunc may reuse the storage of its result, so each call
+// is serialized and its result is copied.
+func syncSplit(A, P, B, Q SplitFunc) (SplitFunc, SplitFunc, SplitFunc, SplitFunc) {
+	var mu sync.Mutex
+	wrap := func(f SplitFunc) SplitFunc {
+		return func(n uint64) *Big {
+			mu.Lock()
+			defer mu.Unlock()
+			return new(Big).Copy(f(n))
+		}
+	}
+	return wrap(A), wrap(P), wrap(B), wrap(Q)
+}
+
 // BinarySplit sets z to the result of the binary splitting formula and returns
 // z. The formula is defined as:
 //
@@ -89,11 +125,24 @@ func BinarySplit(z *Big, ctx Context, start, stop uint64, A, P, B, Q SplitFunc)
 		panic("math: the stop of BinarySplit must be larger than the start")
 	}
 
+	sem := splitSem(ctx)
+	if sem != nil {
+		A, P, B, Q = syncSplit(A, P, B, Q)
+	}
 	state := newState()
-	state.calculate(ctx, start, stop, A, P, B, Q)
+	state.calculate(ctx, start, stop, A, P, B, Q, sem)
+	if z.checkDone(ctx) {
+		return z
+	}
 	return state.term(z, ctx)
 }
 
+// BinarySplitContext is like BinarySplit, but it stops early if ctx
+// is done. See Context.PiContext.
+func BinarySplitContext(ctx context.Context, z *Big, c Context, start, stop uint64, A, P, B, Q SplitFunc) *Big {
+	return BinarySplit(z, c.withDone(ctx), start, stop, A, P, B, Q)
+}
+
 // BinarySplitDynamic sets z to the result of the binary splitting formula. It
 // should be used when the number of terms is not known ahead of time. For more
 // information, See BinarySplit.
@@ -116,9 +165,14 @@ func BinarySplitDynamic(ctx Context, A, P, B, Q SplitFunc) *Big {
 	//     Q = Q_l*Q_r
 	//     T = B_l*P_l*T_r + B_r*Q_r*T_l
 
+	sem := splitSem(ctx)
+	if sem != nil {
+		A, P, B, Q = syncSplit(A, P, B, Q)
+	}
+
 	currentLastTerm := uint64(16)
 	current := newState()
-	current.calculate(ctx, 0, currentLastTerm, A, P, B, Q)
+	current.calculate(ctx, 0, currentLastTerm, A, P, B, Q, sem)
 
 	// the marked value is what should be returned which is T/(BQ)
 	markValue1 := current.term(new(Big), ctx)
@@ -143,7 +197,10 @@ func BinarySplitDynamic(ctx Context, A, P, B, Q SplitFunc) *Big {
 
 	for {
 		for {
-			tmp.calculate(ctx, nextLastTerm, nextLastTerm+deltaTerm, A, P, B, Q)
+			tmp.calculate(ctx, nextLastTerm, nextLastTerm+deltaTerm, A, P, B, Q, sem)
+			if markValue2.checkDone(ctx) {
+				return markValue2
+			}
 			next.combine(ctx, next, tmp)
 			nextLastTerm += deltaTerm
 
@@ -222,7 +279,10 @@ func BinarySplitDynamic(ctx Context, A, P, B, Q SplitFunc) *Big {
 
 		// now we have what we expect to be way closer to the true n
 		if currentLastTerm != expectedLastTerm {
-			tmp.calculate(ctx, currentLastTerm, expectedLastTerm, A, P, B, Q)
+			tmp.calculate(ctx, currentLastTerm, expectedLastTerm, A, P, B, Q, sem)
+			if markValue1.checkDone(ctx) {
+				return markValue1
+			}
 			current.combine(ctx, current, tmp)
 		}
 
@@ -234,7 +294,18 @@ func BinarySplitDynamic(ctx Context, A, P, B, Q SplitFunc) *Big {
 	}
 }
 
-func (s *apbqBinarySplitState) calculate(ctx Context, start, end uint64, A, P, B, Q SplitFunc) {
+// BinarySplitDynamicContext is like BinarySplitDynamic, but it stops
+// early if ctx is done. See Context.PiContext.
+func BinarySplitDynamicContext(ctx context.Context, c Context, A, P, B, Q SplitFunc) *Big {
+	return BinarySplitDynamic(c.withDone(ctx), A, P, B, Q)
+}
+
+// calculate sets s to the state for the range [start, end).
+//
+// If sem is non-nil, the halves of large ranges are computed
+// concurrently, using up to cap(sem) additional goroutines. The
+// result is identical to the serial result.
+func (s *apbqBinarySplitState) calculate(ctx Context, start, end uint64, A, P, B, Q SplitFunc, sem chan struct{}) {
 	switch n1 := start; end - start {
 	case 1:
 		s.B.Copy(B(n1))
@@ -418,11 +489,33 @@ func (s *apbqBinarySplitState) calculate(ctx Context, start, end uint64, A, P, B
 		// then do the calculations and return the value
 		m := uint64((start + end) / 2)
 
-		// We can reuse s as one of the states.
-		s.calculate(ctx, start, m, A, P, B, Q)
+		// Give up early if the Context is done. The caller checks
+		// the Context again and discards the partial result.
+		if ctx.done() {
+			return
+		}
 
+		// We can reuse s as one of the states.
 		r := newState()
-		r.calculate(ctx, m, end, A, P, B, Q)
+		if end-start >= parallelSplitTerms && tryAcquire(sem) {
+			// Compute the right side on another goroutine. The two
+			// sides share no state, so the result is the same as if
+			// they were computed serially.
+			done := make(chan struct{})
+			go func() {
+				r.calculate(ctx, m, end, A, P, B, Q, sem)
+				<-sem
+				close(done)
+			}()
+			s.calculate(ctx, start, m, A, P, B, Q, sem)
+			<-done
+		} else {
+			s.calculate(ctx, start, m, A, P, B, Q, sem)
+			r.calculate(ctx, m, end, A, P, B, Q, sem)
+		}
+		if ctx.done() {
+			return
+		}
 
 		// Generically, the following is done
 		//
@@ -435,6 +528,17 @@ func (s *apbqBinarySplitState) calculate(ctx Context, start, end uint64, A, P, B
 	}
 }
 
+// tryAcquire reports whether a slot in sem was acquired without
+// blocking. It always returns false if sem is nil.
+func tryAcquire(sem chan struct{}) bool {
+	select {
+	case sem <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
 // combine computes the following:
 //
 //     B = B_l*B_r
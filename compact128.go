@@ -0,0 +1,306 @@
+package decimal
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+
+	"github.com/ericlagergren/decimal/internal/arith"
+)
+
+// Coefficients too large for compact are stored in unscaled. But
+// big.Int arithmetic is slow and allocates, so coefficients with
+// at most 38 digits, which always fit into two 64-bit words, use
+// the fixed-size word arithmetic in package arith instead. This
+// covers every Context128 value and the intermediate results of
+// arithmetic on them, like the product of two 34-digit
+// coefficients.
+const (
+	// maxWords is the largest number of 64-bit words used by the
+	// word-sized fast paths.
+	maxWords = 4
+
+	// maxWordsDigits is the largest number of digits that always
+	// fits into maxWords words.
+	maxWordsDigits = 76
+
+	// compact128Digits is the largest number of digits that
+	// always fits into two words.
+	compact128Digits = 38
+)
+
+// words sets buf to the coefficient of x as little-endian words
+// and returns the used portion of buf and whether the coefficient
+// fits into len(buf) words.
+func (x *Big) words(buf []uint64) ([]uint64, bool) {
+	if x.isCompact() {
+		if x.compact == 0 {
+			return buf[:0], true
+		}
+		buf[0] = x.compact
+		return buf[:1], true
+	}
+	return arith.Words64(buf, &x.unscaled)
+}
+
+// words128 returns the coefficient of x, which must have at most
+// compact128Digits digits, as little-endian words.
+func (x *Big) words128() (w [2]uint64) {
+	x.words(w[:])
+	return w
+}
+
+// setWords sets z's coefficient to the little-endian words x and
+// returns z.
+func (z *Big) setWords(x []uint64) *Big {
+	arith.SetWords64(&z.unscaled, x)
+	return z.norm()
+}
+
+// addWords is like addBig, but for coefficients that have at most
+// compact128Digits digits. It reports false if X or Y are too
+// large.
+func (c Context) addWords(z *Big, X *Big, Xsign form, Y *Big, Ysign form) (form, bool) {
+	shift := X.exp - Y.exp
+	if X.Precision() > compact128Digits ||
+		Y.Precision() > compact128Digits ||
+		X.Precision()+shift > maxWordsDigits {
+		return 0, false
+	}
+
+	xb, yb := X.words128(), Y.words128()
+	var buf, sum [maxWords + 1]uint64
+	xw := arith.MulPow10Words(buf[:0], norm64(xb[:]), uint64(shift))
+	yw := norm64(yb[:])
+
+	if Xsign == Ysign {
+		z.setWords(arith.AddWords(sum[:], xw, yw))
+		return Xsign, true
+	}
+
+	sign := Xsign
+	// X + (-Y) == X - Y == -(Y - X)
+	// (-X) + Y == Y - X == -(X - Y)
+	if arith.CmpWords(xw, yw) < 0 {
+		sign ^= signbit
+		xw, yw = yw, xw
+	}
+	if z.setWords(arith.SubWords(sum[:], xw, yw)).isZero() {
+		sign = 0
+		if c.RoundingMode == ToNegativeInf {
+			sign = Xsign ^ Ysign // either 0 or 1
+		}
+		sign |= Xsign & Ysign
+	}
+	return sign, true
+}
+
+// mulWords sets z to x * y, where x and y are finite, and reports
+// whether x and y have few enough digits to do so.
+func (z *Big) mulWords(x, y *Big) bool {
+	if x.Precision() > compact128Digits || y.Precision() > compact128Digits {
+		return false
+	}
+	xb, yb := x.words128(), y.words128()
+	p := arith.Mul128(xb[1], xb[0], yb[1], yb[0])
+	z.setWords(p[:])
+	return true
+}
+
+// quoFiniteWords is like quoFinite, but for coefficients that have
+// at most compact128Digits digits and results with few enough
+// digits that the shifted dividend fits into maxWords words. It
+// reports false if x, y, or the result are too large.
+//
+// ideal must be x.exp - y.exp, since z may alias x or y and have
+// already been modified.
+func (c Context) quoFiniteWords(z, x, y *Big, zp, ideal int) bool {
+	xp, yp := x.Precision(), y.Precision()
+	if xp > compact128Digits || yp > compact128Digits || zp+yp > maxWordsDigits {
+		return false
+	}
+
+	xb, yb := x.words128(), y.words128()
+	xw, yw := norm64(xb[:]), norm64(yb[:])
+
+	// Same as cmpNorm.
+	var buf [maxWords + 1]uint64
+	if xp < yp {
+		if arith.CmpWords(arith.MulPow10Words(buf[:0], xw, uint64(yp-xp)), yw) > 0 {
+			yp--
+		}
+	} else if arith.CmpWords(xw, arith.MulPow10Words(buf[:0], yw, uint64(xp-yp))) > 0 {
+		yp--
+	}
+
+	// The shifted dividend has zp+yp digits and the shifted
+	// divisor has at most xp-zp digits, so both fit.
+	shift := zp + yp - xp
+	z.exp = ideal - shift
+	if shift > 0 {
+		xw = arith.MulPow10Words(buf[:0], xw, uint64(shift))
+	} else if shift < 0 {
+		yw = arith.MulPow10Words(buf[:0], yw, uint64(-shift))
+	}
+	var d [2]uint64
+	copy(d[:], yw)
+
+	expadj := ideal - z.exp
+	if z.quoWords(c, xw, x.form, d, y.form) && expadj > 0 {
+		c.reduceIdeal(z, ideal)
+	}
+	return true
+}
+
+// quoWords is like quoBig, but for a dividend with at most
+// maxWords words and a divisor with at most two words.
+func (z *Big) quoWords(c Context, x []uint64, xneg form, y [2]uint64, yneg form) bool {
+	m := c.RoundingMode
+	z.form = xneg ^ yneg
+
+	var buf [maxWords + 2]uint64
+	q, r := arith.QuoRemWords(buf[:], x, y)
+	z.setWords(q)
+	if r == [2]uint64{} {
+		return true
+	}
+
+	z.Context.Conditions |= Inexact | Rounded
+	if m == ToZero {
+		return false
+	}
+	if m == unnecessary {
+		z.setNaN(InvalidOperation|InvalidContext|InsufficientStorage, qnan, quotermexp)
+		return false
+	}
+
+	// Test to see if we accidentally increased precision because
+	// of rounding. See (*Big).quo.
+	p := z.precision
+	if z.setWords(roundWords(q, r, y, z.form != 0, c)).precision != p {
+		var tmp [maxWords + 2]uint64
+		q, _ = arith.QuoRemWords(tmp[:], q, [2]uint64{10})
+		z.setWords(q)
+		z.exp++
+	}
+	return false
+}
+
+// quoPow10 sets z to z / 10**n rounded with c's RoundingMode and
+// reports whether the division was exact.
+func (z *Big) quoPow10(c Context, n uint64) bool {
+	var buf [maxWords]uint64
+	if hi, lo, ok := arith.Pow10x128(n); ok {
+		if x, ok := z.words(buf[:]); ok {
+			return z.quoWords(c, x, z.form, [2]uint64{lo, hi}, 0)
+		}
+	}
+	var r big.Int
+	return z.quoBig(c, &z.unscaled, z.form, arith.BigPow10(n), 0, &r)
+}
+
+// reduceWords removes at most n trailing zeros from z, whose
+// coefficient is the little-endian words x, and returns z.
+func (z *Big) reduceWords(x []uint64, n int) *Big {
+	var buf [maxWords + 1]uint64
+	for n > 0 {
+		d, k := uint64(10), 1
+		if n >= 8 {
+			d, k = 1e8, 8
+		}
+		q, r := arith.QuoRemWords(buf[:], x, [2]uint64{d})
+		if r[0] != 0 {
+			if k == 1 {
+				break
+			}
+			// Fewer than eight trailing zeros.
+			n = 7
+			continue
+		}
+		x = append(x[:0], q...)
+		z.exp += k
+		n -= k
+	}
+	return z.setWords(x)
+}
+
+// simpleReduceWords is like simpleReduce, but for a z whose
+// coefficient fits into maxWords words. It reports false if z is
+// too large.
+func (z *Big) simpleReduceWords() bool {
+	var buf [maxWords]uint64
+	x, ok := z.words(buf[:])
+	if ok {
+		z.reduceWords(x, math.MaxInt32)
+	}
+	return ok
+}
+
+// pow10x2 returns 10**n as little-endian words. n must be in
+// [0, 38].
+func pow10x2(n int) [2]uint64 {
+	hi, lo, _ := arith.Pow10x128(uint64(n))
+	return [2]uint64{lo, hi}
+}
+
+// norm64 returns x without its leading zero words.
+func norm64(x []uint64) []uint64 {
+	for len(x) > 0 && x[len(x)-1] == 0 {
+		x = x[:len(x)-1]
+	}
+	return x
+}
+
+// roundWords rounds the truncated quotient q of a division by d
+// with the remainder r using c's RoundingMode, and returns q. neg
+// is the sign of the quotient.
+func roundWords(q []uint64, r, d [2]uint64, neg bool, c Context) []uint64 {
+	m := c.RoundingMode
+	if r == [2]uint64{} || m == ToZero {
+		return q
+	}
+
+	var rc int
+	if m == Stochastic {
+		// A random number in [0, d).
+		x := c.rand()
+		h1, l1 := bits.Mul64(x, d[1])
+		h0, _ := bits.Mul64(x, d[0])
+		t0, carry := bits.Add64(l1, h0, 0)
+		rc = arith.Cmp128(r[1], r[0], h1+carry, t0)
+	} else {
+		// Compare r to d - r instead of 2r to d, which might
+		// overflow.
+		h, l := d[1], d[0]
+		var b uint64
+		l, b = bits.Sub64(l, r[0], 0)
+		h, _ = bits.Sub64(h, r[1], b)
+		rc = arith.Cmp128(r[1], r[0], h, l)
+	}
+
+	if !m.needsInc(lastDigitWords(q), rc, !neg) {
+		return q
+	}
+	for i := range q {
+		q[i]++
+		if q[i] != 0 {
+			return q
+		}
+	}
+	return append(q, 1)
+}
+
+// lastDigitWords returns the least significant decimal digit of the
+// little-endian words x.
+func lastDigitWords(x []uint64) uint64 {
+	// 2**(64i) ≡ 6 (mod 10) for i > 0.
+	var d uint64
+	for i, w := range x {
+		if i == 0 {
+			d += w % 10
+		} else {
+			d += 6 * (w % 10)
+		}
+	}
+	return d % 10
+}
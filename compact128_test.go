@@ -0,0 +1,109 @@
+package decimal
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ericlagergren/decimal/internal/arith"
+)
+
+// randWords returns a random Big with at most 38 digits.
+func randWords(r *rand.Rand) *Big {
+	var b [16]byte
+	r.Read(b[:])
+	n := new(big.Int).SetBytes(b[:r.Intn(17)])
+	n.Rsh(n, uint(r.Intn(64)))
+	if n.BitLen() > 126 {
+		n.Rsh(n, 2)
+	}
+	if r.Intn(8) == 0 {
+		// All nines, which carries when rounded up.
+		n.Sub(arith.BigPow10(uint64(1+r.Intn(compact128Digits))), big.NewInt(1))
+	}
+	if r.Intn(2) == 0 {
+		n.Neg(n)
+	}
+	return new(Big).SetBigMantScale(n, r.Intn(60)-20)
+}
+
+// padBig returns x with k more trailing zeros, which forces
+// arithmetic on it to use big.Int.
+func padBig(x *Big, k int) *Big {
+	z := new(Big).Copy(x)
+	Context{Precision: UnlimitedPrecision}.shiftl(z, uint64(k))
+	z.exp -= k
+	return z
+}
+
+func TestBig_words(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	modes := []RoundingMode{
+		ToNearestEven, ToNearestAway, ToZero, AwayFromZero,
+		ToNegativeInf, ToPositiveInf, ToNearestTowardZero,
+		ZeroFiveUp, ToOdd,
+	}
+	var scale int
+	ops := []struct {
+		name string
+		fn   func(c Context, z, x, y *Big) *Big
+	}{
+		{"Add", Context.Add},
+		{"Sub", Context.Sub},
+		{"Mul", Context.Mul},
+		{"Quo", Context.Quo},
+		{"Quantize", func(c Context, z, x, y *Big) *Big {
+			return c.Quantize(z.Copy(x), scale)
+		}},
+		{"Reduce", func(c Context, z, x, _ *Big) *Big {
+			return c.Reduce(z.Copy(x))
+		}},
+	}
+	for i := 0; i < 20000; i++ {
+		x, y := randWords(r), randWords(r)
+		scale = r.Intn(60) - 20
+		c := Context128
+		c.RoundingMode = modes[r.Intn(len(modes))]
+		if i%3 == 0 {
+			c.Precision = 1 + r.Intn(38)
+		}
+		for _, op := range ops {
+			if op.name == "Quo" && y.Sign() == 0 {
+				continue
+			}
+			z := op.fn(c, new(Big), x, y)
+			want := op.fn(c, new(Big), padBig(x, 80), padBig(y, 80))
+			if op.name == "Quantize" && want.IsNaN(0) {
+				continue
+			}
+			zi := z.Context.Conditions & Inexact
+			wi := want.Context.Conditions & Inexact
+			if z.Cmp(want) != 0 || zi != wi {
+				t.Fatalf("#%d: %s(%s, %s) %s: wanted (%s, %s), got (%s, %s)",
+					i, op.name, x, y, c.RoundingMode, want, wi, z, zi)
+			}
+		}
+	}
+}
+
+func TestBig_wordsAllocs(t *testing.T) {
+	v := decs("1234567890123456789012345678901.234", "-9876543210987654321098765432.109876", "0.5", "1E-30")
+	x, y, s, e := v[0], v[1], v[2], v[3]
+	z := new(Big)
+	c := Context128
+	n := testing.AllocsPerRun(100, func() {
+		c.Add(z, x, y)
+		c.Sub(z, x, s)
+		c.Add(z, s, e)
+		c.Mul(z, x, y)
+		c.Mul(z, x, s)
+		c.Quo(z, x, y)
+		c.Quo(z, s, x)
+		c.Quantize(z.Copy(x), 2)
+		c.Reduce(z.Copy(x))
+		x.Cmp(y)
+	})
+	if n != 0 {
+		t.Fatalf("wanted 0 allocations, got %f", n)
+	}
+}
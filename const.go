@@ -50,7 +50,6 @@ var (
 	negFour   = newLazyDecimal("-4")
 	negOne    = newLazyDecimal("-1")
 	one       = newLazyDecimal("1")
-	onePtFour = newLazyDecimal("1.4")
 	two       = newLazyDecimal("2")
 	three     = newLazyDecimal("3")
 	four      = newLazyDecimal("4")
@@ -1,7 +1,11 @@
 package decimal
 
 import (
+	"context"
 	"fmt"
+	"math/big"
+	"math/bits"
+	"math/rand"
 	"strings"
 
 	"github.com/ericlagergren/decimal/internal/c"
@@ -54,9 +58,67 @@ type Context struct {
 	// RoundingMode determines how a decimal is rounded.
 	RoundingMode RoundingMode
 
+	// Clamp, if true, limits the exponent of a finite result to
+	// MaxScale - (Precision - 1), as required by the IEEE 754
+	// interchange formats. A larger exponent is decreased by
+	// padding the coefficient with zeros, which raises Clamped.
+	// For example, with a Precision of 16 and a MaxScale of 384,
+	// 1E+384 is stored as 1000000000000000E+369. Clamp has no
+	// effect with UnlimitedPrecision.
+	Clamp bool
+
 	// OperatingMode which dictates how the decimal operates under certain
 	// conditions. See OperatingMode for more information.
 	OperatingMode OperatingMode
+
+	// Options holds settings that most Contexts do not need. A nil
+	// Options is the same as a pointer to a zero ContextOptions.
+	// Copies of a Context share its Options, so they should not be
+	// modified while the Context is in use.
+	Options *ContextOptions
+}
+
+// ContextOptions holds the rarely used settings of a Context. They
+// are kept behind a pointer so that they do not increase the size
+// of every Context and every Big.
+type ContextOptions struct {
+	// MaxDigits, if positive, limits the number of digits in the
+	// coefficient of a result or of an intermediate value. An
+	// operation that would exceed it sets its result to NaN and
+	// raises InsufficientStorage instead. The check is made before
+	// the coefficient is computed, so an estimate that exceeds
+	// MaxDigits is sufficient to raise InsufficientStorage.
+	//
+	// MaxDigits is most useful with UnlimitedPrecision, where, for
+	// example, 10**1000000000 or quantizing 1E+999999999 to a
+	// scale of zero would otherwise allocate gigabytes of memory.
+	MaxDigits int
+
+	// MaxMemory, if positive, limits the approximate number of
+	// bytes of working memory used by each coefficient an
+	// operation creates, including intermediate values computed at
+	// a higher precision than the result. It is otherwise the same
+	// as MaxDigits. If both are set, the smaller limit is used.
+	MaxMemory int
+
+	// Rand is the source of random numbers for the Stochastic
+	// rounding mode. If nil, the default source in math/rand is
+	// used. Rand is not used by any other rounding mode.
+	Rand RandSource
+
+	// Parallelism, if greater than one, is the maximum number of
+	// goroutines BinarySplit and BinarySplitDynamic, and the
+	// operations built on them, like Pi, E, Atan, and Cos, may use.
+	// Independent parts of the computation are evaluated
+	// concurrently and the result is identical to the result with
+	// a Parallelism of one, except with the Stochastic
+	// RoundingMode, for which Rand must also be safe for
+	// concurrent use.
+	Parallelism int
+
+	// done is the Done channel of the context.Context passed to
+	// an operation like ExpContext, or nil.
+	done <-chan struct{}
 }
 
 // dup returns the Context, but with a non-zero Precision.
@@ -66,6 +128,36 @@ func (c Context) dup() Context {
 	return ctx
 }
 
+// withDone returns c, but with a copy of its Options that makes
+// long-running operations stop once ctx is done.
+func (c Context) withDone(ctx context.Context) Context {
+	done := ctx.Done()
+	if done == nil {
+		return c
+	}
+	var opts ContextOptions
+	if c.Options != nil {
+		opts = *c.Options
+	}
+	opts.done = done
+	c.Options = &opts
+	return c
+}
+
+// done reports whether the context.Context passed to the current
+// operation, if any, is done.
+func (c Context) done() bool {
+	if c.Options == nil || c.Options.done == nil {
+		return false
+	}
+	select {
+	case <-c.Options.done:
+		return true
+	default:
+		return false
+	}
+}
+
 func (c Context) precision() int {
 	if c.Precision != 0 {
 		return c.Precision
@@ -118,12 +210,13 @@ func WithPrecision(p int) *Big {
 }
 
 // The following are called ContextXX instead of DecimalXX
-// to reserve the DecimalXX namespace for future decimal types.
+// because the DecimalXX names belong to the fixed-size decimal
+// types, like Decimal64, that the Contexts govern.
 
 // The following Contexts are based on IEEE 754R. Each Context's
-// RoundingMode is ToNearestEven, OperatingMode is GDA, and traps
-// are set to every exception other than Inexact, Rounded, and
-// Subnormal.
+// RoundingMode is ToNearestEven, OperatingMode is GDA, traps are
+// set to every exception other than Inexact, Rounded, and
+// Subnormal, and, except for ContextUnlimited, Clamp is set.
 var (
 	// Context32 is the IEEE 754R Decimal32 format.
 	Context32 = Context{
@@ -133,6 +226,7 @@ var (
 		Traps:         ^(Inexact | Rounded | Subnormal),
 		MaxScale:      96,
 		MinScale:      -95,
+		Clamp:         true,
 	}
 
 	// Context64 is the IEEE 754R Decimal64 format.
@@ -143,6 +237,7 @@ var (
 		Traps:         ^(Inexact | Rounded | Subnormal),
 		MaxScale:      384,
 		MinScale:      -383,
+		Clamp:         true,
 	}
 
 	// Context128 is the IEEE 754R Decimal128 format.
@@ -153,6 +248,7 @@ var (
 		Traps:         ^(Inexact | Rounded | Subnormal),
 		MaxScale:      6144,
 		MinScale:      -6143,
+		Clamp:         true,
 	}
 
 	// ContextUnlimited provides unlimited precision decimals.
@@ -179,6 +275,25 @@ var (
 type RoundingMode uint8
 
 // The following rounding modes are supported.
+//
+// ZeroFiveUp is the General Decimal Arithmetic Specification's
+// round-05up: the result is rounded toward zero unless its last digit
+// would be 0 or 5, in which case it is rounded away from zero.
+//
+// ToOdd rounds an inexact result to whichever neighbor has an odd
+// last digit. Both ZeroFiveUp and ToOdd never produce a result that
+// looks exact when it is not, so they can be used for intermediate
+// results that are later rounded again, for example to a Decimal64,
+// without suffering from double rounding. Intermediate results must
+// have at least one more digit than the final result for ZeroFiveUp
+// and at least two more for ToOdd.
+//
+// Stochastic rounds an inexact result away from zero with a
+// probability equal to the fraction of the distance between its two
+// neighbors, so the expected value of the rounded result is the
+// exact result. It uses the Rand in the Context's Options as its
+// source of random numbers. Results that overflow are set to
+// infinity.
 const (
 	ToNearestEven       RoundingMode = iota // == IEEE 754-2008 roundTiesToEven
 	ToNearestAway                           // == IEEE 754-2008 roundTiesToAway
@@ -187,13 +302,21 @@ const (
 	ToNegativeInf                           // == IEEE 754-2008 roundTowardNegative
 	ToPositiveInf                           // == IEEE 754-2008 roundTowardPositive
 	ToNearestTowardZero                     // no IEEE 754-2008 equivalent
+	ZeroFiveUp                              // no IEEE 754-2008 equivalent
+	ToOdd                                   // no IEEE 754-2008 equivalent
+	Stochastic                              // no IEEE 754-2008 equivalent
 
 	unnecessary // placeholder for x / y with UnlimitedPrecision.
 )
 
 //go:generate stringer -type RoundingMode
 
-func (m RoundingMode) needsInc(odd bool, r int, pos bool) bool {
+// needsInc reports whether a truncated result with the least
+// significant digit d needs to be incremented.
+//
+// For Stochastic, r compares the remainder to a random threshold
+// instead of to one half. See Context.threshold.
+func (m RoundingMode) needsInc(d uint64, r int, pos bool) bool {
 	switch m {
 	case AwayFromZero:
 		return true // always up
@@ -211,16 +334,52 @@ func (m RoundingMode) needsInc(odd bool, r int, pos bool) bool {
 		if r != 0 {
 			return r > 0
 		}
-		return odd
+		return d%2 != 0
 	case ToNearestAway:
 		return r >= 0
 	case ToNearestTowardZero:
 		return r > 0
+	case ZeroFiveUp:
+		return d == 0 || d == 5
+	case ToOdd:
+		return d%2 == 0
+	case Stochastic:
+		return r > 0
 	default:
 		return false
 	}
 }
 
+// RandSource is a source of uniformly distributed random numbers
+// for the Stochastic rounding mode. *math/rand.Rand implements
+// RandSource.
+type RandSource interface {
+	Uint64() uint64
+}
+
+// rand returns a random number from the Context's Options.Rand.
+func (c Context) rand() uint64 {
+	if c.Options != nil && c.Options.Rand != nil {
+		return c.Options.Rand.Uint64()
+	}
+	return rand.Uint64()
+}
+
+// threshold returns a random number in [0, y). Rounding a quotient
+// away from zero if its remainder is greater than the threshold
+// does so with a probability of remainder / y.
+func (c Context) threshold(y uint64) uint64 {
+	hi, _ := bits.Mul64(c.rand(), y)
+	return hi
+}
+
+// thresholdBig is like threshold, but for a big.Int.
+func (c Context) thresholdBig(y *big.Int) *big.Int {
+	t := new(big.Int).SetUint64(c.rand())
+	t.Mul(t, y)
+	return t.Rsh(t, 64)
+}
+
 // OperatingMode dictates how the decimal approaches specific non-numeric
 // operations like conversions to strings and panicking on NaNs.
 type OperatingMode uint8
@@ -275,8 +434,9 @@ const (
 	// Inexact occurs when the result of an operation (e.g. division) is not
 	// exact, or when the Overflow/Underflow Conditions occur.
 	Inexact
-	// InsufficientStorage occurs when the system doesn't have enough storage
-	// (i.e. memory) to store the
+	// InsufficientStorage occurs when the result of an operation, or an
+	// intermediate value, would have more digits than the Context's
+	// MaxDigits or MaxMemory allow.
 	InsufficientStorage
 	// InvalidContext occurs when an invalid context was detected during an
 	// operation. This might occur if, for example, an invalid RoundingMode was
@@ -313,6 +473,9 @@ const (
 	// Underflow occurs when the result is inexact and the adjusted scale would
 	// be smaller (more negative) than MinScale.
 	Underflow
+	// Canceled occurs when a long-running operation, like
+	// ExpContext, is stopped because its context.Context is done.
+	Canceled
 )
 
 func (c Condition) Error() string { return c.String() }
@@ -354,6 +517,8 @@ func (c Condition) String() string {
 			b.WriteString("subnormal, ")
 		case Underflow:
 			b.WriteString("underflow, ")
+		case Canceled:
+			b.WriteString("canceled, ")
 		default:
 			fmt.Fprintf(&b, "unknown(%d), ", i)
 		}
@@ -1,6 +1,13 @@
 package decimal
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestCondition_String(t *testing.T) {
 	for i, test := range [...]struct {
@@ -10,6 +17,7 @@ func TestCondition_String(t *testing.T) {
 		{Clamped, "clamped"},
 		{Clamped | Underflow, "clamped, underflow"},
 		{Inexact | Rounded | Subnormal, "inexact, rounded, subnormal"},
+		{Underflow | Canceled, "underflow, canceled"},
 		{1 << 31, "unknown(2147483648)"},
 	} {
 		s := test.c.String()
@@ -77,6 +85,22 @@ func TestNonStandardRoundingModes(t *testing.T) {
 		{-16, AwayFromZero, -2},
 		{-25, AwayFromZero, -3},
 		{-55, AwayFromZero, -6},
+		{55, ZeroFiveUp, 6},
+		{25, ZeroFiveUp, 2},
+		{16, ZeroFiveUp, 1},
+		{10, ZeroFiveUp, 1},
+		{5, ZeroFiveUp, 1},
+		{-5, ZeroFiveUp, -1},
+		{-16, ZeroFiveUp, -1},
+		{-55, ZeroFiveUp, -6},
+		{55, ToOdd, 5},
+		{25, ToOdd, 3},
+		{16, ToOdd, 1},
+		{20, ToOdd, 2},
+		{4, ToOdd, 1},
+		{-4, ToOdd, -1},
+		{-16, ToOdd, -1},
+		{-25, ToOdd, -3},
 	} {
 		v := New(test.value, 1)
 		v.Context.RoundingMode = test.mode
@@ -89,3 +113,305 @@ func TestNonStandardRoundingModes(t *testing.T) {
 		}
 	}
 }
+
+func TestRoundToPrepare(t *testing.T) {
+	for i, test := range [...]struct {
+		x, y string
+		prec int
+		mode RoundingMode
+		out  string
+	}{
+		{"1", "7", 5, ZeroFiveUp, "0.14286"},
+		{"2", "3", 5, ZeroFiveUp, "0.66666"},
+		{"1", "7", 5, ToOdd, "0.14285"},
+		{"1", "8", 2, ToOdd, "0.13"},
+		{"1", "8", 3, ToOdd, "0.125"},
+		{"3", "7", 25, ZeroFiveUp, "0.4285714285714285714285714"},
+		{"4", "7", 25, ZeroFiveUp, "0.5714285714285714285714286"},
+		{"-16", "17", 25, ZeroFiveUp, "-0.9411764705882352941176471"},
+		{"3", "7", 25, ToOdd, "0.4285714285714285714285715"},
+		{"4", "7", 25, ToOdd, "0.5714285714285714285714285"},
+	} {
+		ctx := Context{Precision: test.prec, RoundingMode: test.mode}
+		x, _ := new(Big).SetString(test.x)
+		y, _ := new(Big).SetString(test.y)
+		z := WithContext(ctx)
+		if ctx.Quo(z, x, y); z.String() != test.out {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.out, z)
+		}
+	}
+
+	// Rounding to an intermediate precision with ZeroFiveUp or ToOdd
+	// does not change the result of rounding to nearest afterward.
+	x, _ := new(Big).SetString("0.123450000000000000000001")
+	for i, test := range [...]struct {
+		mode RoundingMode
+		prec int
+	}{
+		{ZeroFiveUp, 5},
+		{ToOdd, 6},
+		{ToOdd, 20},
+	} {
+		z := WithContext(Context{Precision: test.prec, RoundingMode: test.mode})
+		z.Set(x)
+		Context{Precision: 4}.Round(z)
+		if z.String() != "0.1235" {
+			t.Fatalf("#%d: wanted 0.1235, got %s", i, z)
+		}
+	}
+}
+
+func TestStochastic(t *testing.T) {
+	const n = 10000
+	for i, test := range [...]struct {
+		x    string
+		prec int
+		lo   string
+		frac float64
+	}{
+		{"2.3", 1, "2", 0.3},
+		{"-2.75", 1, "-2", 0.75},
+		{"1.00000000000000000000001", 1, "1", 0},
+		{"123456789012345678901234567890.9", 30, "123456789012345678901234567890", 0.9},
+		{"-12345678901234567890123.05", 23, "-12345678901234567890123", 0.05},
+		{"12.5", 3, "12.5", 0},
+	} {
+		ctx := Context{
+			Precision:    test.prec,
+			RoundingMode: Stochastic,
+			Options:      &ContextOptions{Rand: rand.New(rand.NewSource(int64(i)))},
+		}
+		x, _ := new(Big).SetString(test.x)
+		lo, _ := new(Big).SetString(test.lo)
+		var ups int
+		for j := 0; j < n; j++ {
+			z := ctx.Set(new(Big), x)
+			switch z.CmpAbs(lo) {
+			case 0:
+			case +1:
+				ups++
+			default:
+				t.Fatalf("#%d: %s is not a neighbor of %s", i, z, x)
+			}
+		}
+		if f := float64(ups) / n; f < test.frac-0.02 || f > test.frac+0.02 {
+			t.Fatalf("#%d: wanted %g of results rounded up, got %g", i, test.frac, f)
+		}
+	}
+
+	// Formatting uses the same rounding mode.
+	x, _ := new(Big).SetString("1.23")
+	x.Context.RoundingMode = Stochastic
+	x.Context.Options = &ContextOptions{Rand: rand.New(rand.NewSource(1))}
+	var ups int
+	for j := 0; j < n; j++ {
+		switch s := fmt.Sprintf("%.1f", x); s {
+		case "1.2":
+		case "1.3":
+			ups++
+		default:
+			t.Fatalf("wanted 1.2 or 1.3, got %s", s)
+		}
+	}
+	if f := float64(ups) / n; f < 0.28 || f > 0.32 {
+		t.Fatalf("wanted 0.3 of results rounded up, got %g", f)
+	}
+}
+
+func TestContext_Clamp(t *testing.T) {
+	ctx := Context64
+	ctx.Traps = 0
+	for i, test := range [...]struct {
+		fn    func(z *Big, x, y *Big) *Big
+		x, y  string
+		out   string
+		conds Condition
+	}{
+		{func(z, x, _ *Big) *Big { return ctx.Set(z, x) }, "1E+384", "", "1.000000000000000E+384", Clamped},
+		{func(z, x, _ *Big) *Big { return ctx.Set(z, x) }, "12E+370", "", "1.20E+371", Clamped},
+		{func(z, x, _ *Big) *Big { return ctx.Set(z, x) }, "1E+369", "", "1E+369", 0},
+		{func(z, x, _ *Big) *Big { return ctx.Set(z, x) }, "0E+400", "", "0E+369", Clamped},
+		{ctx.Add, "1E+380", "0E+380", "1.00000000000E+380", Clamped},
+		{ctx.Quo, "1E+384", "1", "1.000000000000000E+384", Clamped},
+		{ctx.Mul, "2E+200", "3E+180", "6.00000000000E+380", Clamped},
+		{ctx.Mul, "1E+384", "10", "Infinity", Overflow | Inexact | Rounded},
+		{func(z, x, _ *Big) *Big { return ctx.Quantize(z.Copy(x), -372) }, "1E+374", "", "1.00000E+374", Clamped},
+		{func(z, x, _ *Big) *Big { return ctx.Quantize(z.Copy(x), -380) }, "0", "", "0E+369", Clamped},
+		{func(z, x, _ *Big) *Big { return ctx.Reduce(z.Copy(x)) }, "9.999999000000000E+380", "", "9.99999900000E+380", 0},
+		{func(z, x, _ *Big) *Big { return ctx.Reduce(z.Copy(x)) }, "9.999999999990000E+384", "", "9.999999999990000E+384", 0},
+	} {
+		x, _ := new(Big).SetString(test.x)
+		y, _ := new(Big).SetString(test.y)
+		z := test.fn(new(Big), x, y)
+		if z.String() != test.out {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.out, z)
+		}
+		if z.Context.Conditions != test.conds {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.conds, z.Context.Conditions)
+		}
+	}
+
+	// Without Clamp, the exponent is not folded down.
+	ctx.Clamp = false
+	x, _ := new(Big).SetString("1E+384")
+	if z := ctx.Set(new(Big), x); z.String() != "1E+384" || z.Context.Conditions != 0 {
+		t.Fatalf("wanted 1E+384, got %s (%s)", z, z.Context.Conditions)
+	}
+}
+
+func TestContext_Canceled(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	x := New(5, 1)
+	for i, test := range [...]struct {
+		name string
+		fn   func(ctx context.Context, c Context, z *Big) *Big
+	}{
+		{"Pi", func(ctx context.Context, c Context, z *Big) *Big { return c.PiContext(ctx, z) }},
+		{"Exp", func(ctx context.Context, c Context, z *Big) *Big { return c.ExpContext(ctx, z, x) }},
+		{"Log", func(ctx context.Context, c Context, z *Big) *Big { return c.LogContext(ctx, z, x) }},
+		{"Log10", func(ctx context.Context, c Context, z *Big) *Big { return c.Log10Context(ctx, z, x) }},
+		{"Sqrt", func(ctx context.Context, c Context, z *Big) *Big { return c.SqrtContext(ctx, z, x) }},
+		{"Atan", func(ctx context.Context, c Context, z *Big) *Big { return c.AtanContext(ctx, z, x) }},
+		{"Cos", func(ctx context.Context, c Context, z *Big) *Big { return c.CosContext(ctx, z, x) }},
+		{"Erf", func(ctx context.Context, c Context, z *Big) *Big { return c.ErfContext(ctx, z, x) }},
+	} {
+		c := Context{Precision: 1000}
+		z := WithContext(c)
+		test.fn(canceled, c, z)
+		if !z.IsNaN(0) || z.Context.Conditions&Canceled == 0 {
+			t.Fatalf("#%d: %s: wanted NaN and Canceled, got %s (%s)",
+				i, test.name, z, z.Context.Conditions)
+		}
+
+		// An unfinished context.Context does not change the
+		// result.
+		ctx, cancel := context.WithCancel(context.Background())
+		z = WithContext(c)
+		test.fn(ctx, c, z)
+		cancel()
+		want := WithContext(c)
+		test.fn(context.Background(), c, want)
+		if z.Cmp(want) != 0 || z.Context.Conditions != want.Context.Conditions {
+			t.Fatalf("#%d: %s: wanted %s, got %s", i, test.name, want, z)
+		}
+	}
+}
+
+func TestContext_CanceledDeadline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	x := New(5, 1)
+	for _, test := range [...]struct {
+		name string
+		fn   func(ctx context.Context, c Context, z *Big) *Big
+	}{
+		{"Pi", func(ctx context.Context, c Context, z *Big) *Big { return c.PiContext(ctx, z) }},
+		{"Exp", func(ctx context.Context, c Context, z *Big) *Big { return c.ExpContext(ctx, z, x) }},
+		{"Sqrt", func(ctx context.Context, c Context, z *Big) *Big { return c.SqrtContext(ctx, z, x) }},
+		{"Atan", func(ctx context.Context, c Context, z *Big) *Big { return c.AtanContext(ctx, z, x) }},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		c := Context{Precision: 1e6}
+		start := time.Now()
+		z := test.fn(ctx, c, WithContext(c))
+		cancel()
+		if d := time.Since(start); d > 5*time.Second {
+			t.Fatalf("%s took %s after the deadline", test.name, d)
+		}
+		if !z.IsNaN(0) || z.Context.Conditions&Canceled == 0 {
+			t.Fatalf("%s: wanted NaN and Canceled, got %s (%s)",
+				test.name, z, z.Context.Conditions)
+		}
+	}
+}
+
+func TestContext_MaxDigits(t *testing.T) {
+	digits := Context{
+		Precision: UnlimitedPrecision,
+		Options:   &ContextOptions{MaxDigits: 1000},
+	}
+	memory := Context{
+		Precision: UnlimitedPrecision,
+		Options:   &ContextOptions{MaxMemory: 100}, // ~241 digits
+	}
+	big := func() *Big {
+		x := WithContext(ContextUnlimited)
+		x.SetString("1" + strings.Repeat("0", 400))
+		return x
+	}
+
+	for i, test := range [...]struct {
+		c    Context
+		fn   func(c Context, z *Big) *Big
+		fail bool
+	}{
+		{digits, func(c Context, z *Big) *Big { return c.Pow(z, New(10, 0), New(1e9, 0)) }, true},
+		{digits, func(c Context, z *Big) *Big { return c.Pow(z, New(10, 0), New(200, 0)) }, false},
+		{memory, func(c Context, z *Big) *Big { return c.Pow(z, New(10, 0), New(300, 0)) }, true},
+		{memory, func(c Context, z *Big) *Big { return c.Pow(z, New(10, 0), New(200, 0)) }, false},
+		{digits, func(c Context, z *Big) *Big { return c.Quantize(z.SetMantScale(1, -999999999), 0) }, true},
+		{digits, func(c Context, z *Big) *Big { return c.Quantize(z.SetMantScale(1, -500), 0) }, false},
+		{digits, func(c Context, z *Big) *Big { return c.Add(z, New(1, -999999999), New(1, 0)) }, true},
+		{digits, func(c Context, z *Big) *Big { return c.QuoInt(z, New(1, -999999999), New(7, 0)) }, true},
+		{digits, func(c Context, z *Big) *Big { return c.Rem(z, New(1, -999999999), New(7, 0)) }, true},
+		{digits, func(c Context, z *Big) *Big { return c.Rem(z, New(1, -500), New(7, 0)) }, false},
+		{digits, func(c Context, z *Big) *Big { return c.Mul(z, big(), big()) }, false},
+		{memory, func(c Context, z *Big) *Big { return c.Mul(z, big(), big()) }, true},
+		{memory, func(c Context, z *Big) *Big { return c.Reduce(z.Copy(big())) }, true},
+		{memory, func(c Context, z *Big) *Big { return z.Copy(big()).SetScale(3) }, true},
+		{memory, func(c Context, z *Big) *Big { return c.Set(z, big()) }, true},
+		{memory, func(c Context, z *Big) *Big {
+			z.SetString("1" + strings.Repeat("0", 300))
+			return z
+		}, true},
+		{memory, func(c Context, z *Big) *Big {
+			z.SetString("1" + strings.Repeat("0", 200))
+			return z
+		}, false},
+		// The exact intermediate sum is too large, even though the
+		// result is not.
+		{Context{Precision: 16, Options: &ContextOptions{MaxDigits: 1000}}, func(c Context, z *Big) *Big {
+			return c.Sum(z, New(1, -999999999), New(1, 0))
+		}, true},
+		{Context{Precision: 16, Options: &ContextOptions{MaxDigits: 1000}}, func(c Context, z *Big) *Big {
+			return c.Variance(z, New(1, -999999999), New(1, 0))
+		}, true},
+	} {
+		z := WithContext(test.c)
+		test.fn(test.c, z)
+		failed := z.IsNaN(0) && z.Context.Conditions&InsufficientStorage != 0
+		if failed != test.fail {
+			t.Fatalf("#%d: wanted InsufficientStorage = %t, got %s (%s)",
+				i, test.fail, z, z.Context.Conditions)
+		}
+	}
+
+	huge := WithContext(digits).SetMantScale(1, -999999999)
+	if huge.Int(nil); huge.Context.Conditions&InsufficientStorage == 0 {
+		t.Fatalf("Int: wanted InsufficientStorage, got %s", huge.Context.Conditions)
+	}
+	if s := fmt.Sprintf("%f %.2f", huge, huge); s != "1E+999999999 1E+999999999" {
+		t.Fatalf("Format: wanted exponential notation, got %q", s)
+	}
+
+	x := big()
+	b, err := x.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := WithContext(memory)
+	if err := z.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if !z.IsNaN(0) || z.Context.Conditions&InsufficientStorage == 0 {
+		t.Fatalf("UnmarshalBinary: wanted NaN and InsufficientStorage, got %s (%s)",
+			z, z.Context.Conditions)
+	}
+	if z.Context.Options != memory.Options {
+		t.Fatalf("UnmarshalBinary: wanted Options %v, got %v",
+			memory.Options, z.Context.Options)
+	}
+}
@@ -1,6 +1,7 @@
 package decimal
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -88,6 +89,9 @@ func (c Context) Wallis(z *Big, g Generator) *Big {
 	}
 
 	for g.Next() && p.IsFinite() {
+		if z.checkDone(c) {
+			return z
+		}
 		t = g.Term()
 
 		z.Copy(a)
@@ -107,6 +111,12 @@ func (c Context) Wallis(z *Big, g Generator) *Big {
 	return z
 }
 
+// WallisContext is like Wallis, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) WallisContext(ctx context.Context, z *Big, g Generator) *Big {
+	return c.withDone(ctx).Wallis(z, g)
+}
+
 // Lentzer, if implemented, allows Generators to provide their own backing
 // storage for the Lentz function.
 type Lentzer interface {
@@ -222,6 +232,9 @@ func (c Context) Lentz(z *Big, g Generator) *Big {
 	}
 
 	for g.Next() && f.IsFinite() {
+		if z.checkDone(c) {
+			return z
+		}
 		t = g.Term()
 
 		// Set D_j = b_j + a_j*D{_j-1}
@@ -260,6 +273,12 @@ func (c Context) Lentz(z *Big, g Generator) *Big {
 	return ctx.Set(z, f)
 }
 
+// LentzContext is like Lentz, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) LentzContext(ctx context.Context, z *Big, g Generator) *Big {
+	return c.withDone(ctx).Lentz(z, g)
+}
+
 /*
 func dump(f, Δ, D, C, eps *Big) {
 	fmt.Printf(`
@@ -0,0 +1,452 @@
+package decimal
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/ericlagergren/decimal/internal/arith"
+)
+
+// Decimal128 is an IEEE 754-2008 128-bit decimal floating-point
+// number. It has 34 digits of precision and is governed by
+// Context128.
+//
+// Like Decimal64, Decimal128 is a fixed-size value type that can
+// be compared with ==, and its zero value is 0E-6176. See
+// Decimal64 for more information.
+type Decimal128 struct {
+	hi, lo uint64 // canonical BID encoding
+}
+
+const (
+	dec128Bias = 6176
+
+	// 10^34 - 1
+	dec128MaxCoeffHi = 0x1ed09bead87c0
+	dec128MaxCoeffLo = 0x378d8e63ffffffff
+
+	// 10^33 - 1
+	dec128MaxPayloadHi = 0x314dc6448d93
+	dec128MaxPayloadLo = 0x38c15b09ffffffff
+)
+
+// NewDecimal128 returns value × 10**-scale as a Decimal128.
+func NewDecimal128(value int64, scale int) Decimal128 {
+	var x Big
+	return x.SetMantScale(value, scale).Decimal128()
+}
+
+// ParseDecimal128 returns s rounded to a Decimal128.
+//
+// See Big.SetString for valid formats. If s is not a valid
+// decimal, ParseDecimal128 returns ConversionSyntax. Unlike a Big,
+// a Decimal128 keeps NaN payloads of up to 33 digits.
+func ParseDecimal128(s string) (Decimal128, error) {
+	if d, ok := parseNaN128(s); ok {
+		return packDecimal128(d), nil
+	}
+	d, err := ieeeParse(Context128, s, ^uint64(0))
+	if err != nil {
+		return Decimal128{}, err
+	}
+	return packDecimal128(d), nil
+}
+
+// Decimal128FromBID returns the Decimal128 with the Binary
+// Integer Decimal (BID) encoding hi<<64 | lo.
+//
+// Non-canonical encodings are canonicalized. For example, a
+// coefficient larger than 10^34-1 is treated as zero.
+func Decimal128FromBID(hi, lo uint64) Decimal128 {
+	return packDecimal128(unpackBID128(hi, lo))
+}
+
+// Decimal128FromDPD returns the Decimal128 with the Densely
+// Packed Decimal (DPD) encoding hi<<64 | lo.
+//
+// Non-canonical encodings are canonicalized.
+func Decimal128FromDPD(hi, lo uint64) Decimal128 {
+	return packDecimal128(unpackDPD128(hi, lo))
+}
+
+// BID returns the Binary Integer Decimal (BID) encoding of x as
+// hi<<64 | lo.
+func (x Decimal128) BID() (hi, lo uint64) {
+	return x.hi, x.lo
+}
+
+// DPD returns the Densely Packed Decimal (DPD) encoding of x as
+// hi<<64 | lo.
+func (x Decimal128) DPD() (hi, lo uint64) {
+	d := x.unpack()
+
+	hi = uint64(d.form&signbit) << 63
+	switch {
+	case d.form&nan != 0:
+		hi |= 0x1f << 58
+		if d.form&snan != 0 {
+			hi |= 1 << 57
+		}
+	case d.form&inf != 0:
+		return hi | 0x1e<<58, 0
+	}
+
+	chi, clo := d.hi, d.lo
+	for i := uint(0); i < 11; i++ {
+		var r uint64
+		chi, clo, r = arith.QuoRem128(chi, clo, 1000)
+		hi, lo = setDeclet128(hi, lo, i, uint64(binToDPD[r]))
+	}
+	if d.form&nan != 0 {
+		return hi, lo
+	}
+
+	// clo is the most significant digit.
+	e := uint64(d.exp + dec128Bias)
+	if clo < 8 {
+		hi |= (e>>12<<3 | clo) << 58
+	} else {
+		hi |= (3<<3 | e>>12<<1 | clo&1) << 58
+	}
+	return hi | (e&0xfff)<<46, lo
+}
+
+// unpack returns x's components.
+func (x Decimal128) unpack() ieeeDecimal {
+	return unpackBID128(x.hi, x.lo)
+}
+
+// unpackBID128 decodes the BID encoding hi<<64 | lo.
+func unpackBID128(hi, lo uint64) (d ieeeDecimal) {
+	d.form = form(hi>>63) & signbit
+	switch {
+	case hi>>58&0x1f == 0x1f:
+		if hi>>57&1 != 0 {
+			d.form |= snan
+		} else {
+			d.form |= qnan
+		}
+		d.hi, d.lo = hi&(1<<46-1), lo
+		if gt128(d.hi, d.lo, dec128MaxPayloadHi, dec128MaxPayloadLo) {
+			d.hi, d.lo = 0, 0
+		}
+		return d
+	case hi>>58&0x1f == 0x1e:
+		d.form |= pinf
+		return d
+	case hi>>61&3 == 3:
+		// The coefficient would be at least 2^113, which is
+		// always larger than 10^34-1.
+		d.exp = int(hi>>47&0x3fff) - dec128Bias
+		return d
+	default:
+		d.exp = int(hi>>49&0x3fff) - dec128Bias
+		d.hi, d.lo = hi&(1<<49-1), lo
+	}
+	if gt128(d.hi, d.lo, dec128MaxCoeffHi, dec128MaxCoeffLo) {
+		d.hi, d.lo = 0, 0
+	}
+	return d
+}
+
+// unpackDPD128 decodes the DPD encoding hi<<64 | lo.
+func unpackDPD128(hi, lo uint64) (d ieeeDecimal) {
+	d.form = form(hi>>63) & signbit
+
+	var c uint64
+	comb := hi >> 58 & 0x1f
+	switch {
+	case comb == 0x1f:
+		if hi>>57&1 != 0 {
+			d.form |= snan
+		} else {
+			d.form |= qnan
+		}
+	case comb == 0x1e:
+		d.form |= pinf
+		return d
+	case comb>>3 == 3:
+		c = 8 | comb&1
+		d.exp = int(comb>>1&3<<12|hi>>46&0xfff) - dec128Bias
+	default:
+		c = comb & 7
+		d.exp = int(comb>>3<<12|hi>>46&0xfff) - dec128Bias
+	}
+
+	var chi uint64
+	for i := 10; i >= 0; i-- {
+		v := uint64(dpdToBin[declet128(hi, lo, uint(i))])
+		chi, c = mulAdd128(chi, c, 1000, v)
+	}
+	d.hi, d.lo = chi, c
+	return d
+}
+
+// packDecimal128 encodes d, which must be canonical, as a
+// Decimal128.
+func packDecimal128(d ieeeDecimal) Decimal128 {
+	hi := uint64(d.form&signbit) << 63
+	switch {
+	case d.form&nan != 0:
+		if gt128(d.hi, d.lo, dec128MaxPayloadHi, dec128MaxPayloadLo) {
+			d.hi, d.lo = 0, 0
+		}
+		hi |= 0x1f<<58 | d.hi
+		if d.form&snan != 0 {
+			hi |= 1 << 57
+		}
+		return Decimal128{hi: hi, lo: d.lo}
+	case d.form&inf != 0:
+		return Decimal128{hi: hi | 0x1e<<58}
+	default:
+		e := uint64(d.exp + dec128Bias)
+		return Decimal128{hi: hi | e<<49 | d.hi, lo: d.lo}
+	}
+}
+
+// declet128 returns the ith declet of the trailing significand
+// hi<<64 | lo.
+func declet128(hi, lo uint64, i uint) uint64 {
+	switch s := 10 * i; {
+	case s >= 64:
+		return hi >> (s - 64) & 0x3ff
+	case s+10 <= 64:
+		return lo >> s & 0x3ff
+	default:
+		return (lo>>s | hi<<(64-s)) & 0x3ff
+	}
+}
+
+// setDeclet128 sets the ith declet of the trailing significand
+// hi<<64 | lo to v, which must be zero beforehand.
+func setDeclet128(hi, lo uint64, i uint, v uint64) (uint64, uint64) {
+	s := 10 * i
+	if s >= 64 {
+		return hi | v<<(s-64), lo
+	}
+	lo |= v << s
+	if s+10 > 64 {
+		hi |= v >> (64 - s)
+	}
+	return hi, lo
+}
+
+// mulAdd128 returns hi<<64 | lo times m plus a.
+func mulAdd128(hi, lo, m, a uint64) (uint64, uint64) {
+	h, l := bits.Mul64(lo, m)
+	l, carry := bits.Add64(l, a, 0)
+	return hi*m + h + carry, l
+}
+
+// gt128 reports whether ahi<<64 | alo > bhi<<64 | blo.
+func gt128(ahi, alo, bhi, blo uint64) bool {
+	return ahi > bhi || ahi == bhi && alo > blo
+}
+
+// parseNaN128 parses s as a NaN with a payload that is too wide
+// for a Big. It reports false if s is not such a NaN.
+func parseNaN128(s string) (d ieeeDecimal, ok bool) {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		if s[i] == '-' {
+			d.form = signbit
+		}
+		i++
+	}
+	switch {
+	case i < len(s) && s[i]|0x20 == 's':
+		d.form |= snan
+		i++
+	case i < len(s) && s[i]|0x20 == 'q':
+		d.form |= qnan
+		i++
+	default:
+		d.form |= qnan
+	}
+	if n := matchFold([]byte(s[i:]), "nan"); n != len("nan") {
+		return d, false
+	}
+	i += len("nan")
+
+	// Every 38-digit payload fits in 128 bits.
+	nd := 0
+	for ; i < len(s); i++ {
+		v := uint64(s[i] - '0')
+		if v > 9 {
+			return d, false
+		}
+		if d.hi == 0 && d.lo == 0 && v == 0 {
+			continue
+		}
+		if nd++; nd > 38 {
+			return d, false
+		}
+		d.hi, d.lo = mulAdd128(d.hi, d.lo, 10, v)
+	}
+	return d, d.hi != 0
+}
+
+// appendNaN128 appends the string form of d, a NaN with a payload
+// that is too wide for a Big, to b.
+func appendNaN128(b []byte, d ieeeDecimal) []byte {
+	b = append(b, d.form.String()...)
+	var buf [39]byte
+	i := len(buf)
+	for hi, lo := d.hi, d.lo; hi != 0 || lo != 0; {
+		var r uint64
+		hi, lo, r = arith.QuoRem128(hi, lo, 10)
+		i--
+		buf[i] = byte('0' + r)
+	}
+	return append(b, buf[i:]...)
+}
+
+// SetDecimal128 sets z to exactly x and returns z.
+//
+// If x is a NaN with a payload larger than 1<<64-1, the payload
+// is discarded.
+func (z *Big) SetDecimal128(x Decimal128) *Big {
+	return x.unpack().big(z)
+}
+
+// Decimal128 returns x rounded to a Decimal128 using x's
+// RoundingMode.
+func (x *Big) Decimal128() Decimal128 {
+	return packDecimal128(ieeeFromBig(Context128, x, ^uint64(0)))
+}
+
+// big sets z to x and returns z.
+func (x Decimal128) big(z *Big) *Big {
+	z.Context = Context128
+	return z.SetDecimal128(x)
+}
+
+// unary returns op(x) computed with Context128.
+func (x Decimal128) unary(op func(Context, *Big, *Big) *Big) Decimal128 {
+	z, t := getDec(Context128), getDec(Context128)
+	r := op(Context128, z, x.big(t)).Decimal128()
+	putDec(z)
+	putDec(t)
+	return r
+}
+
+// binary returns op(x, y) computed with Context128.
+func (x Decimal128) binary(y Decimal128, op func(Context, *Big, *Big, *Big) *Big) Decimal128 {
+	z, t, u := getDec(Context128), getDec(Context128), getDec(Context128)
+	r := op(Context128, z, x.big(t), y.big(u)).Decimal128()
+	putDec(z)
+	putDec(t)
+	putDec(u)
+	return r
+}
+
+// Abs returns |x|.
+func (x Decimal128) Abs() Decimal128 {
+	return x.unary(Context.Abs)
+}
+
+// Add returns x + y.
+func (x Decimal128) Add(y Decimal128) Decimal128 {
+	return x.binary(y, Context.Add)
+}
+
+// Cmp compares x and y and returns:
+//
+//   -1 if x <  y
+//    0 if x == y
+//   +1 if x >  y
+//
+// The result is undefined if either x or y are NaN.
+func (x Decimal128) Cmp(y Decimal128) int {
+	t, u := getDec(Context128), getDec(Context128)
+	r := x.big(t).Cmp(y.big(u))
+	putDec(t)
+	putDec(u)
+	return r
+}
+
+// Format implements the fmt.Formatter interface. See Big.Format
+// for more information.
+func (x Decimal128) Format(s fmt.State, c rune) {
+	if d := x.unpack(); d.form&nan != 0 && d.hi != 0 {
+		// A Big cannot hold the payload.
+		w, _ := s.Width()
+		if s.Flag('-') {
+			w = -w
+		}
+		fmt.Fprintf(s, "%*s", w, appendNaN128(nil, d))
+		return
+	}
+	t := getDec(Context128)
+	x.big(t).Format(s, c)
+	putDec(t)
+}
+
+// IsFinite reports whether x is finite.
+func (x Decimal128) IsFinite() bool {
+	return x.hi>>59&0xf != 0xf
+}
+
+// IsInf reports whether x is an infinity according to sign. See
+// Big.IsInf for more information.
+func (x Decimal128) IsInf(sign int) bool {
+	d := x.unpack()
+	return d.form&inf != 0 &&
+		(sign >= 0 && d.form&signbit == 0 || sign <= 0 && d.form&signbit != 0)
+}
+
+// IsNaN reports whether x is NaN. See Big.IsNaN for more
+// information.
+func (x Decimal128) IsNaN(quiet int) bool {
+	d := x.unpack()
+	return quiet >= 0 && d.form&qnan != 0 || quiet <= 0 && d.form&snan != 0
+}
+
+// Mul returns x * y.
+func (x Decimal128) Mul(y Decimal128) Decimal128 {
+	return x.binary(y, Context.Mul)
+}
+
+// Neg returns -x.
+func (x Decimal128) Neg() Decimal128 {
+	return x.unary(Context.Neg)
+}
+
+// Quo returns x / y.
+func (x Decimal128) Quo(y Decimal128) Decimal128 {
+	return x.binary(y, Context.Quo)
+}
+
+// Sign returns:
+//
+//    -1 if x <  0
+//     0 if x == 0
+//    +1 if x >  0
+//
+// No distinction is made between +0 and -0. The result is
+// undefined if x is a NaN value.
+func (x Decimal128) Sign() int {
+	return x.unpack().sign()
+}
+
+// Signbit reports whether x is negative, negative zero, negative
+// infinity, or negative NaN.
+func (x Decimal128) Signbit() bool {
+	return x.hi>>63 != 0
+}
+
+// String returns the string representation of x. See Big.String
+// for more information.
+func (x Decimal128) String() string {
+	if d := x.unpack(); d.form&nan != 0 && d.hi != 0 {
+		// A Big cannot hold the payload.
+		return string(appendNaN128(nil, d))
+	}
+	var t Big
+	return x.big(&t).String()
+}
+
+// Sub returns x - y.
+func (x Decimal128) Sub(y Decimal128) Decimal128 {
+	return x.binary(y, Context.Sub)
+}
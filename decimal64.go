@@ -0,0 +1,331 @@
+package decimal
+
+import "fmt"
+
+// Decimal64 is an IEEE 754-2008 64-bit decimal floating-point
+// number. It has 16 digits of precision and is governed by
+// Context64.
+//
+// Unlike Big, Decimal64 is a fixed-size value type: it can be
+// copied, compared with ==, and used as a map key. Note that ==
+// compares representations, not values. For example, 1.0 and
+// 1.00 are different Decimal64s, as are +0 and -0. Use Cmp to
+// compare values.
+//
+// The zero value is 0E-398, whose BID and DPD encodings are both
+// all zero bits.
+//
+// Arithmetic is performed as if by Context64, except that
+// exceptional conditions are not reported. Instead, each
+// operation returns the default result specified by IEEE
+// 754-2008; for example, an invalid operation results in a quiet
+// NaN and an overflow results in an infinity.
+type Decimal64 struct {
+	bits uint64 // canonical BID encoding
+}
+
+const (
+	dec64Bias       = 398
+	dec64MaxCoeff   = 9999999999999999 // 10^16 - 1
+	dec64MaxPayload = 999999999999999  // 10^15 - 1
+)
+
+// NewDecimal64 returns value × 10**-scale rounded to a Decimal64.
+func NewDecimal64(value int64, scale int) Decimal64 {
+	var x Big
+	return x.SetMantScale(value, scale).Decimal64()
+}
+
+// ParseDecimal64 returns s rounded to a Decimal64.
+//
+// See Big.SetString for valid formats. If s is not a valid
+// decimal, ParseDecimal64 returns ConversionSyntax.
+func ParseDecimal64(s string) (Decimal64, error) {
+	d, err := ieeeParse(Context64, s, dec64MaxPayload)
+	if err != nil {
+		return Decimal64{}, err
+	}
+	return packDecimal64(d), nil
+}
+
+// Decimal64FromBID returns the Decimal64 with the Binary Integer
+// Decimal (BID) encoding b.
+//
+// Non-canonical encodings are canonicalized. For example, a
+// coefficient larger than 10^16-1 is treated as zero.
+func Decimal64FromBID(b uint64) Decimal64 {
+	return packDecimal64(unpackBID64(b))
+}
+
+// Decimal64FromDPD returns the Decimal64 with the Densely Packed
+// Decimal (DPD) encoding b.
+//
+// Non-canonical encodings are canonicalized.
+func Decimal64FromDPD(b uint64) Decimal64 {
+	return packDecimal64(unpackDPD64(b))
+}
+
+// BID returns the Binary Integer Decimal (BID) encoding of x.
+func (x Decimal64) BID() uint64 {
+	return x.bits
+}
+
+// DPD returns the Densely Packed Decimal (DPD) encoding of x.
+func (x Decimal64) DPD() uint64 {
+	d := x.unpack()
+
+	b := uint64(d.form&signbit) << 63
+	switch {
+	case d.form&nan != 0:
+		b |= 0x1f << 58
+		if d.form&snan != 0 {
+			b |= 1 << 57
+		}
+	case d.form&inf != 0:
+		return b | 0x1e<<58
+	}
+
+	c := d.lo
+	for i := 0; i < 5; i++ {
+		b |= uint64(binToDPD[c%1000]) << (10 * i)
+		c /= 1000
+	}
+	if d.form&nan != 0 {
+		return b
+	}
+
+	// c is the most significant digit.
+	e := uint64(d.exp + dec64Bias)
+	if c < 8 {
+		b |= (e>>8<<3 | c) << 58
+	} else {
+		b |= (3<<3 | e>>8<<1 | c&1) << 58
+	}
+	return b | (e&0xff)<<50
+}
+
+// unpack returns x's components.
+func (x Decimal64) unpack() ieeeDecimal {
+	return unpackBID64(x.bits)
+}
+
+// unpackBID64 decodes the BID encoding b.
+func unpackBID64(b uint64) (d ieeeDecimal) {
+	d.form = form(b>>63) & signbit
+	switch {
+	case b>>58&0x1f == 0x1f:
+		if b>>57&1 != 0 {
+			d.form |= snan
+		} else {
+			d.form |= qnan
+		}
+		d.lo = b & (1<<50 - 1)
+		if d.lo > dec64MaxPayload {
+			d.lo = 0
+		}
+		return d
+	case b>>58&0x1f == 0x1e:
+		d.form |= pinf
+		return d
+	case b>>61&3 == 3:
+		// The coefficient is 0b100 followed by the trailing
+		// 51 bits.
+		d.exp = int(b>>51&0x3ff) - dec64Bias
+		d.lo = 1<<53 | b&(1<<51-1)
+	default:
+		d.exp = int(b>>53&0x3ff) - dec64Bias
+		d.lo = b & (1<<53 - 1)
+	}
+	if d.lo > dec64MaxCoeff {
+		d.lo = 0
+	}
+	return d
+}
+
+// unpackDPD64 decodes the DPD encoding b.
+func unpackDPD64(b uint64) (d ieeeDecimal) {
+	d.form = form(b>>63) & signbit
+
+	var c uint64
+	comb := b >> 58 & 0x1f
+	switch {
+	case comb == 0x1f:
+		if b>>57&1 != 0 {
+			d.form |= snan
+		} else {
+			d.form |= qnan
+		}
+	case comb == 0x1e:
+		d.form |= pinf
+		return d
+	case comb>>3 == 3:
+		c = 8 | comb&1
+		d.exp = int(comb>>1&3<<8|b>>50&0xff) - dec64Bias
+	default:
+		c = comb & 7
+		d.exp = int(comb>>3<<8|b>>50&0xff) - dec64Bias
+	}
+
+	for i := 4; i >= 0; i-- {
+		c = c*1000 + uint64(dpdToBin[b>>(10*i)&0x3ff])
+	}
+	d.lo = c
+	return d
+}
+
+// packDecimal64 encodes d, which must be canonical, as a
+// Decimal64.
+func packDecimal64(d ieeeDecimal) Decimal64 {
+	b := uint64(d.form&signbit) << 63
+	switch {
+	case d.form&nan != 0:
+		b |= 0x1f<<58 | d.lo
+		if d.form&snan != 0 {
+			b |= 1 << 57
+		}
+	case d.form&inf != 0:
+		b |= 0x1e << 58
+	default:
+		e := uint64(d.exp + dec64Bias)
+		if d.lo < 1<<53 {
+			b |= e<<53 | d.lo
+		} else {
+			b |= 3<<61 | e<<51 | d.lo&(1<<51-1)
+		}
+	}
+	return Decimal64{bits: b}
+}
+
+// SetDecimal64 sets z to exactly x and returns z.
+func (z *Big) SetDecimal64(x Decimal64) *Big {
+	return x.unpack().big(z)
+}
+
+// Decimal64 returns x rounded to a Decimal64 using x's
+// RoundingMode.
+func (x *Big) Decimal64() Decimal64 {
+	return packDecimal64(ieeeFromBig(Context64, x, dec64MaxPayload))
+}
+
+// big sets z to x and returns z.
+func (x Decimal64) big(z *Big) *Big {
+	z.Context = Context64
+	return z.SetDecimal64(x)
+}
+
+// unary returns op(x) computed with Context64.
+func (x Decimal64) unary(op func(Context, *Big, *Big) *Big) Decimal64 {
+	z, t := getDec(Context64), getDec(Context64)
+	r := op(Context64, z, x.big(t)).Decimal64()
+	putDec(z)
+	putDec(t)
+	return r
+}
+
+// binary returns op(x, y) computed with Context64.
+func (x Decimal64) binary(y Decimal64, op func(Context, *Big, *Big, *Big) *Big) Decimal64 {
+	z, t, u := getDec(Context64), getDec(Context64), getDec(Context64)
+	r := op(Context64, z, x.big(t), y.big(u)).Decimal64()
+	putDec(z)
+	putDec(t)
+	putDec(u)
+	return r
+}
+
+// Abs returns |x|.
+func (x Decimal64) Abs() Decimal64 {
+	return x.unary(Context.Abs)
+}
+
+// Add returns x + y.
+func (x Decimal64) Add(y Decimal64) Decimal64 {
+	return x.binary(y, Context.Add)
+}
+
+// Cmp compares x and y and returns:
+//
+//   -1 if x <  y
+//    0 if x == y
+//   +1 if x >  y
+//
+// The result is undefined if either x or y are NaN.
+func (x Decimal64) Cmp(y Decimal64) int {
+	t, u := getDec(Context64), getDec(Context64)
+	r := x.big(t).Cmp(y.big(u))
+	putDec(t)
+	putDec(u)
+	return r
+}
+
+// Format implements the fmt.Formatter interface. See Big.Format
+// for more information.
+func (x Decimal64) Format(s fmt.State, c rune) {
+	t := getDec(Context64)
+	x.big(t).Format(s, c)
+	putDec(t)
+}
+
+// IsFinite reports whether x is finite.
+func (x Decimal64) IsFinite() bool {
+	return x.bits>>59&0xf != 0xf
+}
+
+// IsInf reports whether x is an infinity according to sign. See
+// Big.IsInf for more information.
+func (x Decimal64) IsInf(sign int) bool {
+	d := x.unpack()
+	return d.form&inf != 0 &&
+		(sign >= 0 && d.form&signbit == 0 || sign <= 0 && d.form&signbit != 0)
+}
+
+// IsNaN reports whether x is NaN. See Big.IsNaN for more
+// information.
+func (x Decimal64) IsNaN(quiet int) bool {
+	d := x.unpack()
+	return quiet >= 0 && d.form&qnan != 0 || quiet <= 0 && d.form&snan != 0
+}
+
+// Mul returns x * y.
+func (x Decimal64) Mul(y Decimal64) Decimal64 {
+	return x.binary(y, Context.Mul)
+}
+
+// Neg returns -x.
+func (x Decimal64) Neg() Decimal64 {
+	return x.unary(Context.Neg)
+}
+
+// Quo returns x / y.
+func (x Decimal64) Quo(y Decimal64) Decimal64 {
+	return x.binary(y, Context.Quo)
+}
+
+// Sign returns:
+//
+//    -1 if x <  0
+//     0 if x == 0
+//    +1 if x >  0
+//
+// No distinction is made between +0 and -0. The result is
+// undefined if x is a NaN value.
+func (x Decimal64) Sign() int {
+	return x.unpack().sign()
+}
+
+// Signbit reports whether x is negative, negative zero, negative
+// infinity, or negative NaN.
+func (x Decimal64) Signbit() bool {
+	return x.bits>>63 != 0
+}
+
+// String returns the string representation of x. See Big.String
+// for more information.
+func (x Decimal64) String() string {
+	var t Big
+	return x.big(&t).String()
+}
+
+// Sub returns x - y.
+func (x Decimal64) Sub(y Decimal64) Decimal64 {
+	return x.binary(y, Context.Sub)
+}
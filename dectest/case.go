@@ -18,6 +18,11 @@ type Case struct {
 	Inputs     []Data
 	Output     Data
 	Conditions Condition
+
+	// Hex is true if an operand or the result is written as a
+	// hexadecimal IEEE 754 interchange encoding, like
+	// #2238000000000000. Those operands are not parsed.
+	Hex bool
 }
 
 func (c Case) String() string {
@@ -257,8 +262,8 @@ var roundingModes = map[string]RoundingMode{
 	"half_down": ToNearestTowardZero,
 	"half_even": ToNearestEven,
 	"half_up":   ToNearestAway,
-	"up":        0,
-	"05up":      0,
+	"up":        AwayFromZero,
+	"05up":      ZeroFiveUp,
 }
 
 //go:generate stringer -type=Op
@@ -3,6 +3,7 @@ package dectest
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"strconv"
@@ -33,16 +34,94 @@ func (s *Scanner) Scan() bool {
 		if !s.s.Scan() {
 			return false
 		}
-		if s.err = s.parse(s.s.Bytes()); s.err != nil {
+		line := s.s.Bytes()
+		if s.err = s.parse(line); s.err != nil {
 			return false
 		}
 		if s.c != nil {
+			s.c.Hex = hasHex(line)
+			if len(s.c.Inputs) == 0 || s.c.Output == "" {
+				parseFields(s.c, line)
+			}
 			return true
 		}
 	}
 	return false
 }
 
+// hasHex reports whether the test case on line has an operand or
+// result written in hexadecimal, like #2238000000000000.
+func hasHex(line []byte) bool {
+	if i := bytes.Index(line, []byte("--")); i >= 0 {
+		line = line[:i]
+	}
+	return bytes.IndexByte(line, '#') >= 0
+}
+
+// parseFields fills in c's operands, result, and conditions from
+// the fields of line. It is a fallback for test cases the parser
+// cannot read, like
+//
+//	basx500 toSci '1..2' -> NaN Conversion_syntax
+//
+// and leaves c unchanged if line is not a test case.
+func parseFields(c *Case, line []byte) {
+	f := fields(line)
+	i := 2
+	for i < len(f) && f[i] != "->" {
+		i++
+	}
+	if i == 2 || i+1 >= len(f) {
+		return
+	}
+	var conds Condition
+	for _, s := range f[i+2:] {
+		cond, ok := conditions[strings.ToLower(s)]
+		if !ok {
+			return
+		}
+		conds |= cond
+	}
+	c.Inputs = c.Inputs[:0]
+	for _, s := range f[2:i] {
+		c.Inputs = append(c.Inputs, Data(s))
+	}
+	c.Output = Data(f[i+1])
+	c.Conditions = conds
+}
+
+// fields splits line into whitespace-separated fields, stopping
+// at a comment. A quoted field may contain spaces and has its
+// quotes removed.
+func fields(line []byte) []string {
+	var f []string
+	for len(line) > 0 {
+		line = bytes.TrimLeft(line, " \t")
+		if len(line) == 0 || bytes.HasPrefix(line, []byte("--")) {
+			break
+		}
+		if q := line[0]; q == '\'' || q == '"' {
+			i := bytes.IndexByte(line[1:], q)
+			if i < 0 {
+				i = len(line) - 1
+			}
+			f = append(f, string(line[1:i+1]))
+			line = line[i+1:]
+			if len(line) > 0 {
+				line = line[1:]
+			}
+			continue
+		}
+		i := bytes.IndexAny(line, " \t")
+		if i < 0 {
+			i = len(line)
+		}
+		f = append(f, string(line[:i]))
+		line = line[i:]
+	}
+	return f
+}
+
 func (s *Scanner) Case() *Case {
 	s.c.Clamp = s.clamp == 1
 	s.c.Prec = s.precision
@@ -9,7 +9,6 @@ import (
 	"testing"
 
 	. "github.com/ericlagergren/decimal"
-	"github.com/ericlagergren/decimal/internal/arith"
 )
 
 func Test(t *testing.T, file string) {
@@ -36,6 +35,7 @@ func Test(t *testing.T, file string) {
 
 var nilary = map[Op]func(ctx Context, z *Big) *Big{
 	OpReduce:      (Context).Reduce,
+	OpToIntegral:  (Context).RoundToIntegral,
 	OpToIntegralX: (Context).RoundToInt,
 }
 
@@ -55,7 +55,8 @@ var unary = map[Op]func(ctx Context, z, x *Big) *Big{
 		return z.CopyNeg(x)
 	},
 	OpExp:        Context.Exp,
-	OpLogB:       Context.Log10,
+	OpInvert:     Context.Invert,
+	OpLogB:       Context.Logb,
 	OpLog10:      Context.Log10,
 	OpLn:         Context.Log,
 	OpMinus:      Context.Neg,
@@ -65,28 +66,29 @@ var unary = map[Op]func(ctx Context, z, x *Big) *Big{
 }
 
 var binary = map[Op]func(ctx Context, z, x, y *Big) *Big{
-	OpAdd: Context.Add,
+	OpAdd:        Context.Add,
+	OpAnd:        Context.And,
+	OpCompareSig: Context.CmpSignal,
 	OpCopySign: func(_ Context, z, x, y *Big) *Big {
 		return z.CopySign(x, y)
 	},
-	OpDivide:    Context.Quo,
-	OpDivideInt: Context.QuoInt,
-	OpMax: func(_ Context, _, x, y *Big) *Big {
-		return Max(x, y)
-	},
-	OpMaxMag: func(_ Context, _, x, y *Big) *Big {
-		return MaxAbs(x, y)
-	},
-	OpMin: func(_ Context, _, x, y *Big) *Big {
-		return Min(x, y)
-	},
-	OpMinMag: func(_ Context, _, x, y *Big) *Big {
-		return MinAbs(x, y)
-	},
-	OpMultiply:  Context.Mul,
-	OpPower:     Context.Pow,
-	OpRemainder: Context.Rem,
-	OpSubtract:  Context.Sub,
+	OpDivide:        Context.Quo,
+	OpDivideInt:     Context.QuoInt,
+	OpMax:           Context.Max,
+	OpMaxMag:        Context.MaxAbs,
+	OpMin:           Context.Min,
+	OpMinMag:        Context.MinAbs,
+	OpMultiply:      Context.Mul,
+	OpNextToward:    Context.NextToward,
+	OpOr:            Context.Or,
+	OpPower:         Context.Pow,
+	OpRemainder:     Context.Rem,
+	OpRemainderNear: Context.RemNear,
+	OpRotate:        Context.Rotate,
+	OpScaleB:        Context.Scaleb,
+	OpShift:         Context.Shift,
+	OpSubtract:      Context.Sub,
+	OpXor:           Context.Xor,
 }
 
 var ternary = map[Op]func(ctx Context, z, x, y, u *Big) *Big{
@@ -144,6 +146,7 @@ func execute(t *testing.T, c *Case) {
 		RoundingMode:  c.Mode,
 		MinScale:      c.MinScale,
 		MaxScale:      c.MaxScale,
+		Clamp:         c.Clamp,
 	}
 
 	z, x, y, u := parseInputs(ctx, c)
@@ -160,7 +163,8 @@ func execute(t *testing.T, c *Case) {
 	} else {
 		switch c.Op {
 		case OpClass:
-			assert(t, c, x.Class(), r)
+			x.Context = ctx
+			assert(t, c, x.Class(), string(c.Output))
 		case OpCompare:
 			rv := x.Cmp(y)
 			r, _, snan := cmp(t, c)
@@ -176,23 +180,15 @@ func execute(t *testing.T, c *Case) {
 			r, _, snan := cmp(t, c)
 			assert(t, c, rv, r)
 			assert(t, c, snan, x.Context.Conditions&InvalidOperation != 0)
-		case OpMax:
-			check(t, z.Set(Max(x, y)), r, c, flags)
-		case OpMin:
-			check(t, z.Set(Min(x, y)), r, c, flags)
 		case OpQuantize:
-			v, _ := y.Int64()
-			if v > arith.MaxInt {
-				t.Logf("%s: int out of range: %d", c.ID, v)
-				return
-			}
-			check(t, x.Quantize(int(v)), r, c, flags)
+			check(t, quantize(ctx, z, x, y), r, c, flags)
 		case OpSameQuantum:
 			rv := x.SameQuantum(y)
 			assert(t, c, rv, c.Output == Data("1"))
 		case OpToSci:
-			rv := fmt.Sprintf("%E", x)
-			assert(t, c, rv, string(c.Output))
+			assert(t, c, convert(ctx, c).String(), string(c.Output))
+		case OpToEng:
+			assert(t, c, convert(ctx, c).EngString(), string(c.Output))
 		default:
 			t.Fatalf("unknown op: " + c.Op.String())
 		}
@@ -200,7 +196,17 @@ func execute(t *testing.T, c *Case) {
 }
 
 func isSupported(c *Case) bool {
-	if c.Clamp {
+	if c.Hex {
+		return false
+	}
+
+	// A Context treats a MaxScale or MinScale of zero as unset.
+	if c.MaxScale == 0 || c.MinScale == 0 {
+		return false
+	}
+
+	// Power with three operands is modular exponentiation.
+	if c.Op == OpPower && len(c.Inputs) == 3 {
 		return false
 	}
 
@@ -220,7 +226,7 @@ func isSupported(c *Case) bool {
 	} else {
 		switch c.Op {
 		case OpClass, OpCompare, OpCompareTotal, OpCompareTotMag,
-			OpMax, OpMin, OpQuantize, OpSameQuantum, OpToSci:
+			OpQuantize, OpSameQuantum, OpToSci, OpToEng:
 			opSupported = true
 		}
 	}
@@ -253,6 +259,34 @@ func (d Data) toBig(ctx Context) *Big {
 	return &z
 }
 
+// convert implements the toSci and toEng operations, which round
+// their operand to ctx.
+func convert(ctx Context, c *Case) *Big {
+	z := WithContext(ctx)
+	if _, ok := ctx.SetString(z, string(c.Inputs[0].TrimQuotes())); !ok {
+		z.SetNaN(false)
+	}
+	return z
+}
+
+// quantize implements the quantize operation, which takes its
+// exponent from y. Context.Quantize takes an exponent, so the
+// cases where y is not finite are handled here.
+func quantize(ctx Context, z, x, y *Big) *Big {
+	switch {
+	case y.IsFinite():
+		return ctx.Quantize(z.Copy(x), y.Scale())
+	case x.IsNaN(0) || y.IsNaN(0):
+		// NaNs propagate like they do for any other operation.
+		return ctx.Add(z, x, y)
+	case x.IsInf(0):
+		return z.Copy(x)
+	default:
+		z.Context.Conditions |= InvalidOperation
+		return z.SetNaN(false)
+	}
+}
+
 func parseInputs(ctx Context, c *Case) (z, x, y, u *Big) {
 	z = new(Big)
 	switch len(c.Inputs) {
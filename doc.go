@@ -15,7 +15,11 @@
 // While lean, this package is full of features. It implements interfaces like
 // ``fmt.Formatter'' and intuitively utilizes verbs and flags as described in
 // the ``fmt'' package. (Also included: ``fmt.Scanner'', ``fmt.Stringer'',
-// ``encoding.TextUnmarshaler'', and ``encoding.TextMarshaler''.)
+// ``encoding.TextUnmarshaler'', ``encoding.TextMarshaler'',
+// ``encoding.BinaryMarshaler'', ``encoding.BinaryUnmarshaler'',
+// ``json.Marshaler'', ``json.Unmarshaler'', ``gob.GobEncoder'', and
+// ``gob.GobDecoder''.) Locale-specific formatting, such as "1.234.567,89" or
+// "(1,234.50)", is supported via the Locale type.
 //
 // It allows users to specific explicit contexts for arithmetic operations, but
 // doesn't require it. It provides access to NaN payloads and is more lenient
@@ -57,9 +61,10 @@
 //
 // Usage
 //
-// The following type is supported:
+// The following types are supported:
 //
 //     Big decimal numbers
+//     Decimal64 and Decimal128 IEEE 754-2008 decimal numbers
 //
 // The zero value for a Big corresponds with 0, meaning all the following are
 // valid:
@@ -0,0 +1,208 @@
+package decimal
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Big)(nil)
+	_ encoding.BinaryUnmarshaler = (*Big)(nil)
+)
+
+// binaryVersion is the current version of the binary encoding.
+//
+// Version 1 is laid out as follows:
+//
+//    version        byte
+//    form           byte
+//    rounding mode  byte
+//    operating mode byte
+//    flags          byte
+//    precision      varint
+//    max scale      varint
+//    min scale      varint
+//    traps          uvarint
+//    conditions     uvarint
+//
+// followed by, for NaN values,
+//
+//    payload        uvarint
+//
+// or, for finite values,
+//
+//    exponent       varint
+//    coefficient    remaining bytes, big-endian
+//
+// Infinities have nothing after the Context. The only flag is
+// flagClamp.
+const binaryVersion = 1
+
+// flagClamp is set in the flags if the Context's Clamp is set.
+const flagClamp = 1 << 0
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// The encoding includes x's Context, NaN payload, and whether x
+// is a signaling NaN. It is versioned so that future versions
+// of this package can decode it.
+func (x *Big) MarshalBinary() ([]byte, error) {
+	if debug {
+		x.validate()
+	}
+
+	var (
+		buf = make([]byte, 0, 5+7*binary.MaxVarintLen64+8)
+		tmp [binary.MaxVarintLen64]byte
+	)
+	putVarint := func(v int64) {
+		buf = append(buf, tmp[:binary.PutVarint(tmp[:], v)]...)
+	}
+	putUvarint := func(v uint64) {
+		buf = append(buf, tmp[:binary.PutUvarint(tmp[:], v)]...)
+	}
+
+	var flags byte
+	if x.Context.Clamp {
+		flags |= flagClamp
+	}
+	buf = append(buf,
+		binaryVersion,
+		byte(x.form),
+		byte(x.Context.RoundingMode),
+		byte(x.Context.OperatingMode),
+		flags,
+	)
+	putVarint(int64(x.Context.Precision))
+	putVarint(int64(x.Context.MaxScale))
+	putVarint(int64(x.Context.MinScale))
+	putUvarint(uint64(x.Context.Traps))
+	putUvarint(uint64(x.Context.Conditions))
+
+	switch {
+	case x.IsNaN(0):
+		putUvarint(x.compact)
+	case x.IsFinite():
+		putVarint(int64(x.exp))
+		if !x.isCompact() {
+			return append(buf, x.unscaled.Bytes()...), nil
+		}
+		binary.BigEndian.PutUint64(tmp[:8], x.compact)
+		i := 0
+		for i < 8 && tmp[i] == 0 {
+			i++
+		}
+		buf = append(buf, tmp[i:8]...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It
+// decodes data, which must have been created by MarshalBinary,
+// into z, including z's Context.
+//
+// z's Options are not part of the encoding and are kept. If the
+// decoded coefficient exceeds their MaxDigits or MaxMemory, z is
+// set to NaN and InsufficientStorage is raised.
+func (z *Big) UnmarshalBinary(data []byte) error {
+	if len(data) < 5 {
+		return errors.New("Big.UnmarshalBinary: data too short")
+	}
+	if v := data[0]; v != binaryVersion {
+		return fmt.Errorf("Big.UnmarshalBinary: unknown version: %d", v)
+	}
+
+	f := form(data[1])
+	switch f {
+	case finite, finite | signbit, pinf, ninf, snan, ssnan, qnan, sqnan:
+	default:
+		return fmt.Errorf("Big.UnmarshalBinary: invalid form: %#x", data[1])
+	}
+
+	ctx := Context{
+		RoundingMode:  RoundingMode(data[2]),
+		OperatingMode: OperatingMode(data[3]),
+		Clamp:         data[4]&flagClamp != 0,
+		Options:       z.Context.Options,
+	}
+	data = data[5:]
+
+	var err error
+	varint := func() int64 {
+		v, n := binary.Varint(data)
+		if n <= 0 {
+			err = errors.New("Big.UnmarshalBinary: invalid varint")
+			return 0
+		}
+		data = data[n:]
+		return v
+	}
+	uvarint := func() uint64 {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			err = errors.New("Big.UnmarshalBinary: invalid uvarint")
+			return 0
+		}
+		data = data[n:]
+		return v
+	}
+
+	ctx.Precision = int(varint())
+	ctx.MaxScale = int(varint())
+	ctx.MinScale = int(varint())
+	ctx.Traps = Condition(uvarint())
+	ctx.Conditions = Condition(uvarint())
+
+	var (
+		payload uint64
+		exp     int64
+	)
+	switch {
+	case f&nan != 0:
+		payload = uvarint()
+	case f&inf == 0:
+		exp = varint()
+	}
+	if err != nil {
+		return err
+	}
+	if f&special != 0 && len(data) != 0 {
+		return errors.New("Big.UnmarshalBinary: trailing data")
+	}
+
+	z.Context = ctx
+	switch {
+	case f&nan != 0:
+		z.form = f
+		z.compact = payload
+	case f&inf != 0:
+		z.SetInf(f&signbit != 0)
+	case len(data) <= 8:
+		var tmp [8]byte
+		copy(tmp[8-len(data):], data)
+		z.SetUint64(binary.BigEndian.Uint64(tmp[:]))
+		z.exp = int(exp)
+		z.form = f
+	default:
+		z.unscaled.SetBytes(data)
+		z.norm()
+		z.exp = int(exp)
+		z.form = f
+		z.checkStorage(ctx, int64(z.Precision()))
+	}
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder. It uses the same
+// encoding as MarshalBinary.
+func (x *Big) GobEncode() ([]byte, error) {
+	return x.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder. It decodes data created
+// by GobEncode or MarshalBinary.
+func (z *Big) GobDecode(data []byte) error {
+	return z.UnmarshalBinary(data)
+}
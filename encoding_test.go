@@ -0,0 +1,162 @@
+package decimal
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestBig_MarshalJSON(t *testing.T) {
+	for i, test := range [...]struct {
+		in      string
+		str     string
+		num     string
+		numeric bool
+	}{
+		{"0", `"0"`, `0`, true},
+		{"-1.25", `"-1.25"`, `-1.25`, true},
+		{"1E+20", `"1E+20"`, `1E+20`, true},
+		{"123456789012345678901234567890", `"123456789012345678901234567890"`, `123456789012345678901234567890`, true},
+		{"Inf", `"Infinity"`, `"Infinity"`, false},
+		{"-Inf", `"-Infinity"`, `"-Infinity"`, false},
+		{"NaN", `"NaN"`, `"NaN"`, false},
+	} {
+		x, _ := new(Big).SetString(test.in)
+
+		b, err := json.Marshal(x)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if string(b) != test.str {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.str, b)
+		}
+
+		b, err = json.Marshal((*JSONNumber)(x))
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if string(b) != test.num {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.num, b)
+		}
+
+		var z JSONNumber
+		if err := json.Unmarshal(b, &z); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if y := (*Big)(&z); y.String() != x.String() {
+			t.Fatalf("#%d: wanted %s, got %s", i, x, y)
+		}
+	}
+
+	var v struct {
+		X *Big
+		Y JSONNumber
+	}
+	b, err := json.Marshal(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"X":null,"Y":0}`; string(b) != want {
+		t.Fatalf("wanted %s, got %s", want, b)
+	}
+	if b, err = json.Marshal(v); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"X":null,"Y":0}`; string(b) != want {
+		t.Fatalf("by value: wanted %s, got %s", want, b)
+	}
+	y := (*Big)(&v.Y)
+	y.SetMantScale(42, 0)
+	if err := json.Unmarshal([]byte(`{"X":null,"Y":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.X != nil || y.Cmp(New(42, 0)) != 0 {
+		t.Fatalf("null modified the decimals: %v, %v", v.X, y)
+	}
+}
+
+func TestBig_MarshalBinary(t *testing.T) {
+	for i, s := range [...]string{
+		"0",
+		"-0",
+		"1",
+		"-1.25",
+		"18446744073709551615",
+		"18446744073709551616",
+		"-123456789012345678901234567890E-50",
+		"1E+999999",
+		"Inf",
+		"-Inf",
+		"NaN",
+		"-NaN123",
+		"sNaN",
+		"-sNaN42",
+	} {
+		x, _ := WithContext(Context128).SetString(s)
+		x.Context.Conditions = Inexact | Rounded
+		x.Context.RoundingMode = ToNegativeInf
+
+		b, err := x.MarshalBinary()
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		var z Big
+		if err := z.UnmarshalBinary(b); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if z.Context != x.Context {
+			t.Fatalf("#%d: wanted %+v, got %+v", i, x.Context, z.Context)
+		}
+		if z.String() != x.String() || z.form != x.form || z.Payload() != x.Payload() {
+			t.Fatalf("#%d: wanted %s, got %s", i, x, &z)
+		}
+		if x.IsFinite() && (z.Scale() != x.Scale() || z.Precision() != x.Precision()) {
+			t.Fatalf("#%d: wanted %s, got %s", i, x, &z)
+		}
+	}
+
+	for i, b := range [...][]byte{
+		nil,
+		{binaryVersion, 0, 0},
+		{binaryVersion + 1, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{binaryVersion, 0, 0, 0},
+		{binaryVersion, 0xf0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{binaryVersion, 0, 0, 0, 0x80},
+		{binaryVersion, byte(pinf), 0, 0, 0, 0, 0, 0, 0, 0, 1},
+	} {
+		var z Big
+		if err := z.UnmarshalBinary(b); err == nil {
+			t.Fatalf("#%d: expected an error", i)
+		}
+	}
+}
+
+func TestBig_Gob(t *testing.T) {
+	type T struct {
+		X *Big
+		Y []*Big
+	}
+	in := T{
+		X: New(12345, 2),
+		Y: []*Big{new(Big).SetNaN(true), WithContext(Context32).SetMantScale(-7, 30)},
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	var out T
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.X.Cmp(in.X) != 0 || out.X.Scale() != 2 {
+		t.Fatalf("wanted %s, got %s", in.X, out.X)
+	}
+	if !out.Y[0].IsNaN(-1) {
+		t.Fatalf("wanted sNaN, got %s", out.Y[0])
+	}
+	if out.Y[1].Cmp(in.Y[1]) != 0 || out.Y[1].Context != Context32 {
+		t.Fatalf("wanted %s, got %s", in.Y[1], out.Y[1])
+	}
+}
@@ -0,0 +1,702 @@
+package decimal
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+
+	"github.com/ericlagergren/decimal/internal/arith"
+)
+
+const (
+	// MaxFixed64Scale is the largest scale of a Fixed64.
+	MaxFixed64Scale = 18
+
+	// MaxFixed128Scale is the largest scale of a Fixed128.
+	MaxFixed128Scale = 38
+)
+
+// Fixed64 is a fixed-point decimal number with the value
+// unscaled × 10**-scale, where unscaled is an int64 and scale is
+// in [0, MaxFixed64Scale].
+//
+// Unlike Big, Fixed64 is a small value type whose operations never
+// allocate, which makes it suitable for hot paths like matching
+// prices. It has no special values and records no conditions.
+// Instead, operations that cannot produce a representable result
+// return an error: Overflow if the result does not fit,
+// DivisionByZero if the divisor is zero, and InvalidContext if the
+// RoundingMode is invalid.
+//
+// Operations on Fixed64s with different scales produce a result
+// with the larger of the two scales. Add and Sub are exact, while
+// Mul and Quo round their results using the Context's
+// RoundingMode.
+//
+// Like Decimal64, == compares representations, not values. For
+// example, 1.0 and 1.00 are different Fixed64s. Use Cmp to compare
+// values.
+type Fixed64 struct {
+	v     int64
+	scale int8
+}
+
+// NewFixed64 returns unscaled × 10**-scale as a Fixed64.
+//
+// NewFixed64 panics if scale is outside [0, MaxFixed64Scale].
+func NewFixed64(unscaled int64, scale int) Fixed64 {
+	checkFixedScale(scale, MaxFixed64Scale)
+	return Fixed64{v: unscaled, scale: int8(scale)}
+}
+
+// Unscaled returns x's unscaled value.
+func (x Fixed64) Unscaled() int64 {
+	return x.v
+}
+
+// Scale returns x's scale.
+func (x Fixed64) Scale() int {
+	return int(x.scale)
+}
+
+// Sign returns -1 if x < 0, 0 if x == 0, and +1 if x > 0.
+func (x Fixed64) Sign() int {
+	switch {
+	case x.v < 0:
+		return -1
+	case x.v > 0:
+		return +1
+	default:
+		return 0
+	}
+}
+
+// Cmp compares x and y and returns:
+//
+//   -1 if x <  y
+//    0 if x == y
+//   +1 if x >  y
+//
+func (x Fixed64) Cmp(y Fixed64) int {
+	if x.scale == y.scale {
+		switch {
+		case x.v < y.v:
+			return -1
+		case x.v > y.v:
+			return +1
+		default:
+			return 0
+		}
+	}
+	return x.fixed().cmp(int(x.scale), y.fixed(), int(y.scale))
+}
+
+// Add returns x + y.
+func (x Fixed64) Add(y Fixed64) (Fixed64, error) {
+	if x.scale == y.scale {
+		z := x.v + y.v
+		if (z^x.v)&(z^y.v) < 0 {
+			return Fixed64{}, Overflow
+		}
+		return Fixed64{v: z, scale: x.scale}, nil
+	}
+	z, s := x.fixed().add(int(x.scale), y.fixed(), int(y.scale), false)
+	return fixed64(z, s)
+}
+
+// Sub returns x - y.
+func (x Fixed64) Sub(y Fixed64) (Fixed64, error) {
+	if x.scale == y.scale {
+		z := x.v - y.v
+		if (x.v^y.v)&(z^x.v) < 0 {
+			return Fixed64{}, Overflow
+		}
+		return Fixed64{v: z, scale: x.scale}, nil
+	}
+	z, s := x.fixed().add(int(x.scale), y.fixed(), int(y.scale), true)
+	return fixed64(z, s)
+}
+
+// Mul returns x * y rounded using c's RoundingMode.
+func (x Fixed64) Mul(y Fixed64, c Context) (Fixed64, error) {
+	z, s, err := x.fixed().mul(int(x.scale), y.fixed(), int(y.scale), c)
+	if err != nil {
+		return Fixed64{}, err
+	}
+	return fixed64(z, s)
+}
+
+// Quo returns x / y rounded using c's RoundingMode.
+func (x Fixed64) Quo(y Fixed64, c Context) (Fixed64, error) {
+	z, s, err := x.fixed().quo(int(x.scale), y.fixed(), int(y.scale), c)
+	if err != nil {
+		return Fixed64{}, err
+	}
+	return fixed64(z, s)
+}
+
+// Rescale returns x with the scale, rounded using c's
+// RoundingMode if the scale is smaller than x's.
+//
+// Rescale panics if scale is outside [0, MaxFixed64Scale].
+func (x Fixed64) Rescale(scale int, c Context) (Fixed64, error) {
+	checkFixedScale(scale, MaxFixed64Scale)
+	z, err := x.fixed().rescale(int(x.scale), scale, c)
+	if err != nil {
+		return Fixed64{}, err
+	}
+	return fixed64(z, scale)
+}
+
+// Fixed128 returns x as a Fixed128.
+func (x Fixed64) Fixed128() Fixed128 {
+	return x.fixed().fixed128(int(x.scale))
+}
+
+// String returns x in plain notation with exactly x.Scale() digits
+// after the decimal point.
+func (x Fixed64) String() string {
+	return x.fixed().string(int(x.scale))
+}
+
+// fixed returns x's unscaled value in sign-magnitude form.
+func (x Fixed64) fixed() fixedInt {
+	return fixedInt{neg: x.v < 0, lo: arith.Abs(x.v)}
+}
+
+// fixed64 returns z with the scale as a Fixed64, or Overflow if it
+// does not fit.
+func fixed64(z fixedInt, scale int) (Fixed64, error) {
+	if z.hi != 0 || z.lo > 1<<63 || z.lo == 1<<63 && !z.neg {
+		return Fixed64{}, Overflow
+	}
+	v := int64(z.lo)
+	if z.neg {
+		v = -v
+	}
+	return Fixed64{v: v, scale: int8(scale)}, nil
+}
+
+// Fixed128 is like Fixed64, but its unscaled value is a 128-bit
+// two's complement integer and its scale is in
+// [0, MaxFixed128Scale].
+type Fixed128 struct {
+	hi, lo uint64
+	scale  int8
+}
+
+// NewFixed128 returns unscaled × 10**-scale as a Fixed128.
+//
+// NewFixed128 panics if scale is outside [0, MaxFixed128Scale].
+func NewFixed128(unscaled int64, scale int) Fixed128 {
+	checkFixedScale(scale, MaxFixed128Scale)
+	return Fixed128{
+		hi:    uint64(unscaled >> 63),
+		lo:    uint64(unscaled),
+		scale: int8(scale),
+	}
+}
+
+// Fixed128FromParts returns (hi<<64 | lo) × 10**-scale as a
+// Fixed128, where hi<<64 | lo is a 128-bit two's complement
+// integer.
+//
+// Fixed128FromParts panics if scale is outside
+// [0, MaxFixed128Scale].
+func Fixed128FromParts(hi, lo uint64, scale int) Fixed128 {
+	checkFixedScale(scale, MaxFixed128Scale)
+	return Fixed128{hi: hi, lo: lo, scale: int8(scale)}
+}
+
+// Unscaled returns x's unscaled value as the 128-bit two's
+// complement integer hi<<64 | lo.
+func (x Fixed128) Unscaled() (hi, lo uint64) {
+	return x.hi, x.lo
+}
+
+// Scale returns x's scale.
+func (x Fixed128) Scale() int {
+	return int(x.scale)
+}
+
+// Sign returns -1 if x < 0, 0 if x == 0, and +1 if x > 0.
+func (x Fixed128) Sign() int {
+	switch {
+	case int64(x.hi) < 0:
+		return -1
+	case x.hi|x.lo != 0:
+		return +1
+	default:
+		return 0
+	}
+}
+
+// Cmp compares x and y and returns:
+//
+//   -1 if x <  y
+//    0 if x == y
+//   +1 if x >  y
+//
+func (x Fixed128) Cmp(y Fixed128) int {
+	return x.fixed().cmp(int(x.scale), y.fixed(), int(y.scale))
+}
+
+// Add returns x + y.
+func (x Fixed128) Add(y Fixed128) (Fixed128, error) {
+	z, s := x.fixed().add(int(x.scale), y.fixed(), int(y.scale), false)
+	return fixed128(z, s)
+}
+
+// Sub returns x - y.
+func (x Fixed128) Sub(y Fixed128) (Fixed128, error) {
+	z, s := x.fixed().add(int(x.scale), y.fixed(), int(y.scale), true)
+	return fixed128(z, s)
+}
+
+// Mul returns x * y rounded using c's RoundingMode.
+func (x Fixed128) Mul(y Fixed128, c Context) (Fixed128, error) {
+	z, s, err := x.fixed().mul(int(x.scale), y.fixed(), int(y.scale), c)
+	if err != nil {
+		return Fixed128{}, err
+	}
+	return fixed128(z, s)
+}
+
+// Quo returns x / y rounded using c's RoundingMode.
+func (x Fixed128) Quo(y Fixed128, c Context) (Fixed128, error) {
+	z, s, err := x.fixed().quo(int(x.scale), y.fixed(), int(y.scale), c)
+	if err != nil {
+		return Fixed128{}, err
+	}
+	return fixed128(z, s)
+}
+
+// Rescale returns x with the scale, rounded using c's
+// RoundingMode if the scale is smaller than x's.
+//
+// Rescale panics if scale is outside [0, MaxFixed128Scale].
+func (x Fixed128) Rescale(scale int, c Context) (Fixed128, error) {
+	checkFixedScale(scale, MaxFixed128Scale)
+	z, err := x.fixed().rescale(int(x.scale), scale, c)
+	if err != nil {
+		return Fixed128{}, err
+	}
+	return fixed128(z, scale)
+}
+
+// String returns x in plain notation with exactly x.Scale() digits
+// after the decimal point.
+func (x Fixed128) String() string {
+	return x.fixed().string(int(x.scale))
+}
+
+// fixed returns x's unscaled value in sign-magnitude form.
+func (x Fixed128) fixed() fixedInt {
+	z := fixedInt{hi: x.hi, lo: x.lo}
+	if int64(x.hi) < 0 {
+		z.neg = true
+		z.hi, z.lo = neg128(x.hi, x.lo)
+	}
+	return z
+}
+
+// fixed128 returns z with the scale as a Fixed128, or Overflow if
+// it does not fit.
+func fixed128(z fixedInt, scale int) (Fixed128, error) {
+	if z.hi > 1<<63 || z.hi == 1<<63 && (z.lo != 0 || !z.neg) {
+		return Fixed128{}, Overflow
+	}
+	return z.fixed128(scale), nil
+}
+
+// SetFixed64 sets z to exactly x and returns z.
+func (z *Big) SetFixed64(x Fixed64) *Big {
+	return z.SetMantScale(x.v, int(x.scale))
+}
+
+// SetFixed128 sets z to exactly x and returns z.
+func (z *Big) SetFixed128(x Fixed128) *Big {
+	f := x.fixed()
+	d := ieeeDecimal{hi: f.hi, lo: f.lo, exp: -int(x.scale)}
+	if f.neg {
+		d.form = signbit
+	}
+	return d.big(z)
+}
+
+// Fixed64 returns x as a Fixed64 with the scale.
+//
+// The conversion is lossless: if x is not finite, Fixed64 returns
+// InvalidOperation; if x has non-zero digits past the scale, it
+// returns Inexact; and if x is too large, it returns Overflow. Use
+// Quantize to round x to the scale beforehand.
+//
+// Fixed64 panics if scale is outside [0, MaxFixed64Scale].
+func (x *Big) Fixed64(scale int) (Fixed64, error) {
+	checkFixedScale(scale, MaxFixed64Scale)
+	z, err := x.fixed(scale)
+	if err != nil {
+		return Fixed64{}, err
+	}
+	return fixed64(z, scale)
+}
+
+// Fixed128 is like Fixed64, but returns a Fixed128.
+//
+// Fixed128 panics if scale is outside [0, MaxFixed128Scale].
+func (x *Big) Fixed128(scale int) (Fixed128, error) {
+	checkFixedScale(scale, MaxFixed128Scale)
+	z, err := x.fixed(scale)
+	if err != nil {
+		return Fixed128{}, err
+	}
+	return fixed128(z, scale)
+}
+
+// fixed returns x's unscaled value at the scale in sign-magnitude
+// form.
+func (x *Big) fixed(scale int) (z fixedInt, err error) {
+	if !x.IsFinite() {
+		return z, InvalidOperation
+	}
+	if x.isZero() {
+		return z, nil
+	}
+	z.neg = x.Signbit()
+
+	if x.isCompact() {
+		z.lo = x.compact
+	} else if x.unscaled.BitLen() <= 128 {
+		var buf [16]byte
+		x.unscaled.FillBytes(buf[:])
+		z.hi = binary.BigEndian.Uint64(buf[0:8])
+		z.lo = binary.BigEndian.Uint64(buf[8:16])
+	} else {
+		// The coefficient might have trailing zeros, so only
+		// reject it once it's been scaled.
+		if x.exp+scale >= 0 {
+			return z, Overflow
+		}
+		var q, r big.Int
+		q.QuoRem(&x.unscaled, arith.BigPow10(uint64(-(x.exp + scale))), &r)
+		if r.Sign() != 0 {
+			return z, Inexact
+		}
+		if q.BitLen() > 128 {
+			return z, Overflow
+		}
+		var buf [16]byte
+		q.FillBytes(buf[:])
+		z.hi = binary.BigEndian.Uint64(buf[0:8])
+		z.lo = binary.BigEndian.Uint64(buf[8:16])
+		return z, nil
+	}
+
+	var w [2]uint64
+	var buf [fixedWords]uint64
+	switch k := x.exp + scale; {
+	case k > 0:
+		m := arith.MulPow10Words(buf[:0], z.words(&w), uint64(k))
+		if len(m) > 2 {
+			return z, Overflow
+		}
+		z.setWords(m)
+	case k < 0:
+		// Every non-zero 128-bit integer is less than 10**39.
+		if k < -38 {
+			return z, Inexact
+		}
+		m, r := arith.QuoRemWords(buf[:], z.words(&w), pow10x2(-k))
+		if r != [2]uint64{} {
+			return z, Inexact
+		}
+		z.setWords(m)
+	}
+	return z, nil
+}
+
+// checkFixedScale panics if scale is outside [0, max].
+func checkFixedScale(scale, max int) {
+	if scale < 0 || scale > max {
+		panic("decimal: fixed-point scale out of range")
+	}
+}
+
+// fixedWords is the number of words needed for the intermediate
+// results of Fixed128 arithmetic. The largest is the dividend of
+// Quo, which is a 128-bit integer times 10**76.
+const fixedWords = 8
+
+// fixedInt is the unscaled value of a Fixed64 or Fixed128 in
+// sign-magnitude form.
+type fixedInt struct {
+	neg    bool
+	hi, lo uint64 // magnitude
+}
+
+// fixed128 returns z with the scale as a Fixed128. z must fit.
+func (z fixedInt) fixed128(scale int) Fixed128 {
+	hi, lo := z.hi, z.lo
+	if z.neg {
+		hi, lo = neg128(hi, lo)
+	}
+	return Fixed128{hi: hi, lo: lo, scale: int8(scale)}
+}
+
+// words returns z's magnitude as little-endian words stored in
+// buf.
+func (z fixedInt) words(buf *[2]uint64) []uint64 {
+	buf[0], buf[1] = z.lo, z.hi
+	return norm64(buf[:])
+}
+
+// setWords sets z's magnitude to m, which must have at most two
+// words.
+func (z *fixedInt) setWords(m []uint64) {
+	z.hi, z.lo = 0, 0
+	switch len(m) {
+	case 2:
+		z.hi = m[1]
+		fallthrough
+	case 1:
+		z.lo = m[0]
+	}
+	if z.hi|z.lo == 0 {
+		z.neg = false
+	}
+}
+
+// scaled returns z × 10**n and whether the magnitude fits into 128
+// bits.
+func (z fixedInt) scaled(n int) (fixedInt, bool) {
+	if n == 0 {
+		return z, true
+	}
+	var w [2]uint64
+	var buf [fixedWords]uint64
+	m := arith.MulPow10Words(buf[:0], z.words(&w), uint64(n))
+	if len(m) > 2 {
+		return z, false
+	}
+	z.setWords(m)
+	return z, true
+}
+
+// cmp compares z × 10**-zs and x × 10**-xs.
+func (z fixedInt) cmp(zs int, x fixedInt, xs int) int {
+	zsign, xsign := z.sign(), x.sign()
+	if zsign != xsign {
+		if zsign < xsign {
+			return -1
+		}
+		return +1
+	}
+
+	s := max(zs, xs)
+	z0, zok := z.scaled(s - zs)
+	x0, xok := x.scaled(s - xs)
+
+	// At most one of z and x is scaled, so an overflow means that
+	// one is larger.
+	r := 0
+	switch {
+	case !zok:
+		r = +1
+	case !xok:
+		r = -1
+	default:
+		r = arith.Cmp128(z0.hi, z0.lo, x0.hi, x0.lo)
+	}
+	return r * zsign
+}
+
+// sign returns the sign of z.
+func (z fixedInt) sign() int {
+	switch {
+	case z.hi|z.lo == 0:
+		return 0
+	case z.neg:
+		return -1
+	default:
+		return +1
+	}
+}
+
+// add returns z + x, or z - x if sub is true, and its scale, which
+// is the larger of zs and xs. The magnitude of the result is zero if
+// it does not fit into 128 bits, which the caller treats as an
+// overflow.
+func (z fixedInt) add(zs int, x fixedInt, xs int, sub bool) (fixedInt, int) {
+	s := max(zs, xs)
+	z, zok := z.scaled(s - zs)
+	x, xok := x.scaled(s - xs)
+	if !zok || !xok {
+		return fixedOverflow, s
+	}
+	if sub {
+		x.neg = !x.neg
+	}
+
+	var r fixedInt
+	if z.neg == x.neg {
+		var c uint64
+		r.neg = z.neg
+		r.lo, c = bits.Add64(z.lo, x.lo, 0)
+		r.hi, c = bits.Add64(z.hi, x.hi, c)
+		if c != 0 {
+			return fixedOverflow, s
+		}
+		return r, s
+	}
+
+	if arith.Cmp128(z.hi, z.lo, x.hi, x.lo) < 0 {
+		z, x = x, z
+	}
+	var b uint64
+	r.neg = z.neg
+	r.lo, b = bits.Sub64(z.lo, x.lo, 0)
+	r.hi, _ = bits.Sub64(z.hi, x.hi, b)
+	if r.hi|r.lo == 0 {
+		r.neg = false
+	}
+	return r, s
+}
+
+// fixedOverflow is a fixedInt too large for either Fixed64 or
+// Fixed128.
+var fixedOverflow = fixedInt{hi: ^uint64(0), lo: ^uint64(0)}
+
+// mul returns z × x rounded to the larger of zs and xs, and its
+// scale.
+func (z fixedInt) mul(zs int, x fixedInt, xs int, c Context) (fixedInt, int, error) {
+	if c.RoundingMode >= unnecessary {
+		return z, 0, InvalidContext
+	}
+
+	// The exact product has a scale of zs + xs.
+	var p [4]uint64
+	h0, l0 := bits.Mul64(z.lo, x.lo)
+	h1, l1 := bits.Mul64(z.lo, x.hi)
+	h2, l2 := bits.Mul64(z.hi, x.lo)
+	h3, l3 := bits.Mul64(z.hi, x.hi)
+	var c0, c1 uint64
+	p[0] = l0
+	p[1], c0 = bits.Add64(h0, l1, 0)
+	p[1], c1 = bits.Add64(p[1], l2, 0)
+	p[2], c0 = bits.Add64(h1, h2, c0)
+	p[2], c1 = bits.Add64(p[2], l3, c1)
+	p[3] = h3 + c0 + c1
+
+	s := max(zs, xs)
+	r := fixedInt{neg: z.neg != x.neg}
+	var buf [fixedWords]uint64
+	m, rem := arith.QuoRemWords(buf[:], norm64(p[:]), pow10x2(zs+xs-s))
+	m = roundWords(m, rem, pow10x2(zs+xs-s), r.neg, c)
+	if len(m) > 2 {
+		return fixedOverflow, s, nil
+	}
+	r.setWords(m)
+	return r, s, nil
+}
+
+// quo returns z / x rounded to the larger of zs and xs, and its
+// scale.
+func (z fixedInt) quo(zs int, x fixedInt, xs int, c Context) (fixedInt, int, error) {
+	if c.RoundingMode >= unnecessary {
+		return z, 0, InvalidContext
+	}
+	if x.hi|x.lo == 0 {
+		return z, 0, DivisionByZero
+	}
+
+	// z × 10**-zs / (x × 10**-xs) × 10**s
+	//   = z × 10**(s-zs+xs) / x
+	s := max(zs, xs)
+	var w [2]uint64
+	var nbuf, qbuf [fixedWords]uint64
+	n := arith.MulPow10Words(nbuf[:0], z.words(&w), uint64(s-zs+xs))
+	d := [2]uint64{x.lo, x.hi}
+	m, rem := arith.QuoRemWords(qbuf[:], n, d)
+
+	r := fixedInt{neg: z.neg != x.neg}
+	m = roundWords(m, rem, d, r.neg, c)
+	if len(m) > 2 {
+		return fixedOverflow, s, nil
+	}
+	r.setWords(m)
+	return r, s, nil
+}
+
+// rescale returns z × 10**(scale-zs) rounded using c's
+// RoundingMode.
+func (z fixedInt) rescale(zs, scale int, c Context) (fixedInt, error) {
+	if c.RoundingMode >= unnecessary {
+		return z, InvalidContext
+	}
+	if scale >= zs {
+		r, ok := z.scaled(scale - zs)
+		if !ok {
+			return fixedOverflow, nil
+		}
+		return r, nil
+	}
+
+	d := pow10x2(zs - scale)
+	var w [2]uint64
+	var buf [fixedWords]uint64
+	m, rem := arith.QuoRemWords(buf[:], z.words(&w), d)
+	m = roundWords(m, rem, d, z.neg, c)
+	r := fixedInt{neg: z.neg}
+	r.setWords(m)
+	return r, nil
+}
+
+// string returns z × 10**-scale in plain notation.
+func (z fixedInt) string(scale int) string {
+	// A 128-bit integer has at most 39 digits, so it's split
+	// into a leading digit and two 19-digit halves.
+	const p19 = 1e19
+	qhi, qlo, r0 := arith.QuoRem128(z.hi, z.lo, p19)
+	_, top, r1 := arith.QuoRem128(qhi, qlo, p19)
+
+	var digits [1 + 2*19]byte
+	putDigits(digits[1:20], r1)
+	putDigits(digits[20:], r0)
+	digits[0] = byte('0' + top)
+
+	// Trim leading zeros, but keep one before the decimal point.
+	i := 0
+	for i < len(digits)-scale-1 && digits[i] == '0' {
+		i++
+	}
+
+	var buf [2 + len(digits)]byte
+	n := 0
+	if z.neg {
+		buf[n] = '-'
+		n++
+	}
+	n += copy(buf[n:], digits[i:len(digits)-scale])
+	if scale > 0 {
+		buf[n] = '.'
+		n++
+		n += copy(buf[n:], digits[len(digits)-scale:])
+	}
+	return string(buf[:n])
+}
+
+// putDigits writes the decimal digits of x into b, padded on the
+// left with zeros.
+func putDigits(b []byte, x uint64) {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = byte('0' + x%10)
+		x /= 10
+	}
+}
+
+// neg128 returns the two's complement negation of hi<<64 | lo.
+func neg128(hi, lo uint64) (uint64, uint64) {
+	lo, b := bits.Sub64(0, lo, 0)
+	hi, _ = bits.Sub64(0, hi, b)
+	return hi, lo
+}
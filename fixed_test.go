@@ -0,0 +1,271 @@
+package decimal
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// fixedBig returns the unscaled value and scale of x as a Big.
+func fixedBig(x interface{}) *Big {
+	switch x := x.(type) {
+	case Fixed64:
+		return new(Big).SetFixed64(x)
+	case Fixed128:
+		return new(Big).SetFixed128(x)
+	default:
+		panic("unknown fixed-point type")
+	}
+}
+
+// wantFixed returns x rounded to the scale using the rounding mode,
+// or nil if the result does not fit into the given number of bits.
+func wantFixed(x *Big, scale int, m RoundingMode, nbits int) *Big {
+	z := new(Big).Copy(x)
+	Context{Precision: UnlimitedPrecision, RoundingMode: m}.Quantize(z, scale)
+	u := new(Big).Copy(z)
+	u.exp = 0
+	if n := u.Int(nil); n.BitLen() >= nbits && !(n.Sign() < 0 && n.BitLen() == nbits && n.TrailingZeroBits() == uint(nbits-1)) {
+		return nil
+	}
+	return z
+}
+
+// wantQuo returns x / y rounded to the scale using the rounding mode.
+func wantQuo(x, y *Big, scale int, m RoundingMode) *Big {
+	// x / y × 10**scale = xc × 10**k / yc, where xc and yc are the
+	// coefficients of x and y.
+	coeff := func(x *Big) *big.Int {
+		t := new(Big).Copy(x)
+		t.exp = 0
+		return t.Int(nil)
+	}
+	k := scale - x.Scale() + y.Scale()
+	n := coeff(x)
+	n.Mul(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(k)), nil))
+	d := coeff(y)
+
+	// Reduce the quotient to an integer and a fractional digit that
+	// says whether the remainder is less than, equal to, or greater
+	// than one half, which is all that's needed to round it.
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	var frac int64
+	if r.Sign() != 0 {
+		frac = int64(5 + 2*new(big.Int).Lsh(r, 1).CmpAbs(d))
+	}
+	q.Abs(q).Mul(q, big.NewInt(10)).Add(q, big.NewInt(frac))
+	if x.Sign()*y.Sign() < 0 {
+		q.Neg(q)
+	}
+	return new(Big).SetBigMantScale(q, scale+1)
+}
+
+func randFixed64(r *rand.Rand) Fixed64 {
+	v := r.Int63() >> uint(r.Intn(63))
+	if r.Intn(2) == 0 {
+		v = -v
+	}
+	return NewFixed64(v, r.Intn(MaxFixed64Scale+1))
+}
+
+func randFixed128(r *rand.Rand) Fixed128 {
+	hi := r.Uint64() >> uint(r.Intn(65))
+	lo := r.Uint64()
+	if hi == 0 {
+		lo >>= uint(r.Intn(64))
+	}
+	x := Fixed128{hi: hi &^ (1 << 63), lo: lo, scale: int8(r.Intn(MaxFixed128Scale + 1))}
+	if r.Intn(2) == 0 {
+		x.hi, x.lo = neg128(x.hi, x.lo)
+	}
+	return x
+}
+
+func TestFixed(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	modes := []RoundingMode{
+		ToNearestEven, ToNearestAway, ToZero, AwayFromZero,
+		ToNegativeInf, ToPositiveInf, ToNearestTowardZero,
+		ZeroFiveUp, ToOdd,
+	}
+	type op struct {
+		name string
+		fn   func(x, y interface{}, c Context) (interface{}, error)
+		want func(x, y *Big, s int, m RoundingMode) *Big
+	}
+	ops := []op{
+		{"Add", func(x, y interface{}, c Context) (interface{}, error) {
+			if x, ok := x.(Fixed64); ok {
+				return x.Add(y.(Fixed64))
+			}
+			return x.(Fixed128).Add(y.(Fixed128))
+		}, func(x, y *Big, s int, m RoundingMode) *Big {
+			return Context{Precision: UnlimitedPrecision}.Add(new(Big), x, y)
+		}},
+		{"Sub", func(x, y interface{}, c Context) (interface{}, error) {
+			if x, ok := x.(Fixed64); ok {
+				return x.Sub(y.(Fixed64))
+			}
+			return x.(Fixed128).Sub(y.(Fixed128))
+		}, func(x, y *Big, s int, m RoundingMode) *Big {
+			return Context{Precision: UnlimitedPrecision}.Sub(new(Big), x, y)
+		}},
+		{"Mul", func(x, y interface{}, c Context) (interface{}, error) {
+			if x, ok := x.(Fixed64); ok {
+				return x.Mul(y.(Fixed64), c)
+			}
+			return x.(Fixed128).Mul(y.(Fixed128), c)
+		}, func(x, y *Big, s int, m RoundingMode) *Big {
+			return Context{Precision: UnlimitedPrecision}.Mul(new(Big), x, y)
+		}},
+		{"Quo", func(x, y interface{}, c Context) (interface{}, error) {
+			if x, ok := x.(Fixed64); ok {
+				return x.Quo(y.(Fixed64), c)
+			}
+			return x.(Fixed128).Quo(y.(Fixed128), c)
+		}, wantQuo},
+	}
+
+	for i := 0; i < 20000; i++ {
+		var x, y interface{}
+		nbits := 64
+		if i%2 == 0 {
+			x, y = randFixed64(r), randFixed64(r)
+		} else {
+			x, y = randFixed128(r), randFixed128(r)
+			nbits = 128
+		}
+		xb, yb := fixedBig(x), fixedBig(y)
+		s := max(xb.Scale(), yb.Scale())
+		m := modes[r.Intn(len(modes))]
+		for _, op := range ops {
+			if op.name == "Quo" && yb.Sign() == 0 {
+				continue
+			}
+			z, err := op.fn(x, y, Context{RoundingMode: m})
+			want := wantFixed(op.want(xb, yb, s, m), s, m, nbits)
+			if want == nil {
+				if err != Overflow {
+					t.Fatalf("#%d: %s(%s, %s, %s): wanted overflow, got (%s, %v)",
+						i, op.name, x, y, m, z, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("#%d: %s(%s, %s, %s): unexpected error: %v",
+					i, op.name, x, y, m, err)
+			}
+			if zb := fixedBig(z); zb.Cmp(want) != 0 || zb.Scale() != s {
+				t.Fatalf("#%d: %s(%s, %s, %s): wanted %s, got %s",
+					i, op.name, x, y, m, want, z)
+			}
+		}
+	}
+}
+
+func TestFixed_Errors(t *testing.T) {
+	max64 := NewFixed64(math.MaxInt64, 2)
+	if _, err := max64.Add(NewFixed64(1, 2)); err != Overflow {
+		t.Fatalf("Add: wanted Overflow, got %v", err)
+	}
+	if _, err := NewFixed64(math.MinInt64, 0).Sub(NewFixed64(1, 0)); err != Overflow {
+		t.Fatalf("Sub: wanted Overflow, got %v", err)
+	}
+	if _, err := max64.Rescale(3, Context{}); err != Overflow {
+		t.Fatalf("Rescale: wanted Overflow, got %v", err)
+	}
+	if _, err := NewFixed64(1, 0).Quo(NewFixed64(0, 5), Context{}); err != DivisionByZero {
+		t.Fatalf("Quo: wanted DivisionByZero, got %v", err)
+	}
+	if _, err := NewFixed128(1, 0).Mul(NewFixed128(1, 0), Context{RoundingMode: 100}); err != InvalidContext {
+		t.Fatalf("Mul: wanted InvalidContext, got %v", err)
+	}
+	if z, err := NewFixed64(math.MinInt64, 0).Add(NewFixed64(0, 0)); err != nil || z.Unscaled() != math.MinInt64 {
+		t.Fatalf("Add: wanted (%d, nil), got (%s, %v)", int64(math.MinInt64), z, err)
+	}
+}
+
+func TestFixed_Big(t *testing.T) {
+	for i, tc := range []struct {
+		x     string
+		scale int
+		r     string
+		err   error
+	}{
+		{"1.5", 2, "1.50", nil},
+		{"-0.000001", 8, "-0.00000100", nil},
+		{"1.2300", 2, "1.23", nil},
+		{"1.235", 2, "", Inexact},
+		{"92233720368547758.07", 2, "92233720368547758.07", nil},
+		{"-92233720368547758.08", 2, "-92233720368547758.08", nil},
+		{"92233720368547758.08", 2, "", Overflow},
+		{"1E+100", 0, "", Overflow},
+		{"1E-100", 18, "", Inexact},
+		{"0E-100", 18, "0.000000000000000000", nil},
+		{"123456789012345678901234567890000000000000000000000000E-30", 6, "", Overflow},
+		{"123456789012345678901234567890123456789000E-40", 0, "", Inexact},
+		{"12345678901234567890000000000000000000000000E-30", 6, "", Overflow},
+		{"Inf", 0, "", InvalidOperation},
+		{"NaN", 0, "", InvalidOperation},
+	} {
+		x := decs(tc.x)[0]
+		z, err := x.Fixed64(tc.scale)
+		if err != tc.err || err == nil && z.String() != tc.r {
+			t.Fatalf("#%d: Fixed64(%s, %d): wanted (%s, %v), got (%s, %v)",
+				i, tc.x, tc.scale, tc.r, tc.err, z, err)
+		}
+		if err == nil && new(Big).SetFixed64(z).Cmp(x) != 0 {
+			t.Fatalf("#%d: SetFixed64(%s) != %s", i, z, x)
+		}
+	}
+
+	for i, tc := range []struct {
+		x     string
+		scale int
+		r     string
+		err   error
+	}{
+		{"1.5", 2, "1.50", nil},
+		{"170141183460469231731687303715884105727", 0, "170141183460469231731687303715884105727", nil},
+		{"-170141183460469231731.687303715884105728", 18, "-170141183460469231731.687303715884105728", nil},
+		{"170141183460469231731687303715884105728", 0, "", Overflow},
+		{"-1.70141183460469231731687303715884105728", 38, "-1.70141183460469231731687303715884105728", nil},
+		{"123456789012345678901234567890123456789000E-40", 0, "12", Inexact},
+		{"123456789012345678901234567890123456700000000E-40", 1, "12345.6", Inexact},
+		{"123456789012345678901234567890123456700000000E-40", 35, "", Overflow},
+		{"1234567890123456789012345678901234567000000000000000E-40", 26, "123456789012.34567890123456789012345670", nil},
+	} {
+		x := decs(tc.x)[0]
+		z, err := x.Fixed128(tc.scale)
+		if err != tc.err || err == nil && z.String() != tc.r {
+			t.Fatalf("#%d: Fixed128(%s, %d): wanted (%s, %v), got (%s, %v)",
+				i, tc.x, tc.scale, tc.r, tc.err, z, err)
+		}
+		if err == nil && new(Big).SetFixed128(z).Cmp(x) != 0 {
+			t.Fatalf("#%d: SetFixed128(%s) != %s", i, z, x)
+		}
+	}
+}
+
+func TestFixed_Allocs(t *testing.T) {
+	x64, y64 := NewFixed64(123456789, 4), NewFixed64(-987654, 2)
+	x128 := Fixed128FromParts(1<<40, 12345, 30)
+	y128 := NewFixed128(-987654, 2)
+	c := Context{RoundingMode: ToNearestEven}
+	n := testing.AllocsPerRun(100, func() {
+		x64.Add(y64)
+		x64.Sub(y64)
+		x64.Mul(y64, c)
+		x64.Quo(y64, c)
+		x64.Cmp(y64)
+		x128.Add(y128)
+		x128.Mul(y128, c)
+		x128.Quo(y128, c)
+		x128.Rescale(2, c)
+		x128.Cmp(y128)
+	})
+	if n != 0 {
+		t.Fatalf("wanted 0 allocations, got %f", n)
+	}
+}
@@ -1,11 +1,9 @@
 package decimal
 
 import (
-	"bytes"
-	"fmt"
-	"io"
-	"math/big"
 	"strconv"
+
+	"github.com/ericlagergren/decimal/internal/arith"
 )
 
 // allZeros returns true if every character in b is '0'.
@@ -18,12 +16,11 @@ func allZeros(b []byte) bool {
 	return true
 }
 
-var zero = []byte{'0'}
-
-// roundString rounds the plain numeric string (e.g., "1234") b.
-func roundString(b []byte, mode RoundingMode, pos bool, prec int) []byte {
+// roundString rounds the plain numeric string (e.g., "1234") b
+// using the Context's RoundingMode.
+func roundString(b []byte, c Context, pos bool, prec int) []byte {
 	if prec >= len(b) {
-		return append(b, bytes.Repeat(zero, prec-len(b))...)
+		return appendZeros(b, prec-len(b))
 	}
 
 	// Trim zeros until prec. This is useful when we can round exactly by simply
@@ -32,6 +29,12 @@ func roundString(b []byte, mode RoundingMode, pos bool, prec int) []byte {
 		return b[:prec]
 	}
 
+	mode := c.RoundingMode
+	var up bool
+	if mode == Stochastic {
+		up = stochasticString(b[prec:], c)
+	}
+
 	b = b[:prec+1]
 	i := prec - 1
 
@@ -61,6 +64,18 @@ func roundString(b []byte, mode RoundingMode, pos bool, prec int) []byte {
 		if b[i+1] > '5' {
 			b[i]++
 		}
+	case ZeroFiveUp:
+		if b[i] == '0' || b[i] == '5' {
+			b[i]++
+		}
+	case ToOdd:
+		if b[i]%2 == 0 {
+			b[i]++
+		}
+	case Stochastic:
+		if up {
+			b[i]++
+		}
 	}
 
 	if b[i] != '9'+1 {
@@ -91,21 +106,75 @@ func roundString(b []byte, mode RoundingMode, pos bool, prec int) []byte {
 	return b[:prec]
 }
 
-// formatCompact formats the compact decimal, x, as an unsigned integer.
-func formatCompact(x uint64) []byte {
-	var b [20]byte
-	return strconv.AppendUint(b[0:0], uint64(x), 10)
+// stochasticString reports whether a number with the fractional
+// digits b should be rounded away from zero using the Stochastic
+// rounding mode. Only the first 19 digits of b are used.
+func stochasticString(b []byte, c Context) bool {
+	const n = 19
+	var f uint64
+	for i := 0; i < n; i++ {
+		f *= 10
+		if i < len(b) {
+			f += uint64(b[i] - '0')
+		}
+	}
+	return f > c.threshold(1e19)
+}
+
+// appendCoeff appends the coefficient of x to b as an unsigned
+// integer. It only allocates if b does not have enough capacity
+// or the coefficient has more than maxWords words.
+func appendCoeff(b []byte, x *Big) []byte {
+	if x.isCompact() {
+		return strconv.AppendUint(b, x.compact, 10)
+	}
+	var buf [maxWords]uint64
+	if w, ok := x.words(buf[:]); ok {
+		return appendWords(b, w)
+	}
+	n := len(b)
+	b = x.unscaled.Append(b, 10)
+	if b[n] == '-' {
+		b = append(b[:n], b[n+1:]...)
+	}
+	return b
+}
+
+// appendWords appends the little-endian words x, which must have
+// at most maxWords words, to b as an unsigned integer.
+func appendWords(b []byte, x []uint64) []byte {
+	// Split x into base 10**19 chunks, least significant first.
+	const base = 1e19
+	var (
+		chunks [maxWords + 1]uint64
+		bufs   [2][maxWords + 1]uint64
+		n      int
+	)
+	x = norm64(x)
+	for len(x) > 0 {
+		var r [2]uint64
+		x, r = arith.QuoRemWords(bufs[n%2][:], x, [2]uint64{base})
+		chunks[n] = r[0]
+		n++
+	}
+	if n == 0 {
+		return append(b, '0')
+	}
+
+	b = strconv.AppendUint(b, chunks[n-1], 10)
+	for i := n - 2; i >= 0; i-- {
+		var tmp [20]byte
+		d := strconv.AppendUint(tmp[:0], chunks[i], 10)
+		b = appendZeros(b, 19-len(d))
+		b = append(b, d...)
+	}
+	return b
 }
 
-// formatUnscaled formats the unscaled (non-compact) decimal, unscaled, as an
-// unsigned integer.
-func formatUnscaled(unscaled *big.Int) []byte {
-	// math/big.MarshalText never returns an error, only nil, so there's no need
-	// to check for an error. Use MarshalText instead of Append because it limits
-	// us to one allocation.
-	b, _ := unscaled.MarshalText()
-	if b[0] == '-' {
-		b = b[1:]
+// appendZeros appends n '0' characters to b.
+func appendZeros(b []byte, n int) []byte {
+	for ; n > 0; n-- {
+		b = append(b, '0')
 	}
 	return b
 }
@@ -119,44 +188,25 @@ const (
 	normal format = iota // either sci or plain, depending on x
 	plain                // forced plain
 	sci                  // forced sci
+	eng                  // either eng or plain, depending on x
+	si                   // forced eng with an SI prefix
 )
 
 //go:generate stringer -type=format
 
+// formatter appends formatted decimals to b.
 type formatter struct {
-	w interface {
-		io.Writer
-		io.ByteWriter
-		WriteString(string) (int, error)
-	}
-	sign  byte  // leading '+' or ' ' flag
-	prec  int   // total precision
-	width int   // min width
-	n     int64 // cumulative number of bytes written to w
-}
-
-func (f *formatter) WriteByte(c byte) error {
-	f.n++
-	return f.w.WriteByte(c)
-}
-
-func (f *formatter) WriteString(s string) (int, error) {
-	m, err := f.w.WriteString(s)
-	f.n += int64(m)
-	return m, err
-}
-
-func (f *formatter) Write(p []byte) (n int, err error) {
-	n, err = f.w.Write(p)
-	f.n += int64(n)
-	return n, err
+	b     []byte // output
+	sign  byte   // leading '+' or ' ' flag
+	prec  int    // total precision
+	width int    // min width
 }
 
 var sciE = [2]byte{GDA: 'E', Go: 'e'}
 
 func (f *formatter) format(x *Big, format format, e byte) {
 	if x == nil {
-		f.WriteString("<nil>")
+		f.b = append(f.b, "<nil>"...)
 		return
 	}
 
@@ -164,17 +214,17 @@ func (f *formatter) format(x *Big, format format, e byte) {
 	if x.isSpecial() {
 		switch o {
 		case GDA:
-			f.WriteString(x.form.String())
+			f.b = append(f.b, x.form.String()...)
 			if x.IsNaN(0) && x.compact != 0 {
-				f.WriteString(strconv.FormatUint(x.compact, 10))
+				f.b = strconv.AppendUint(f.b, x.compact, 10)
 			}
 		case Go:
 			if x.IsNaN(0) {
-				f.WriteString("NaN")
+				f.b = append(f.b, "NaN"...)
 			} else if x.IsInf(+1) {
-				f.WriteString("+Inf")
+				f.b = append(f.b, "+Inf"...)
 			} else {
-				f.WriteString("-Inf")
+				f.b = append(f.b, "-Inf"...)
 			}
 		}
 		return
@@ -183,39 +233,46 @@ func (f *formatter) format(x *Big, format format, e byte) {
 	if x.isZero() && o == Go {
 		// Go mode prints zeros different than GDA.
 		if f.width == noWidth {
-			f.WriteByte('0')
+			f.b = append(f.b, '0')
 		} else {
-			f.WriteString("0.")
-			io.CopyN(f, zeroReader{}, int64(f.width))
+			f.b = append(f.b, "0."...)
+			f.b = appendZeros(f.b, f.width)
 		}
 		return
 	}
 
 	neg := x.Signbit()
 	if neg {
-		f.WriteByte('-')
+		f.b = append(f.b, '-')
 	} else if f.sign != 0 {
-		f.WriteByte(f.sign)
+		f.b = append(f.b, f.sign)
+	}
+
+	if format == plain && f.prec > 0 &&
+		x.Context.tooLarge(plainDigits(f.prec, x.exp+x.Precision()-f.prec)) {
+		// Writing out every digit would use more memory than x's
+		// Context allows.
+		f.prec = x.Precision()
+		format, e = sci, sciE[o]
 	}
 
 	var (
+		// buf holds the digits of every coefficient with at most
+		// maxWords words, so formatting them does not allocate.
+		buf [maxWordsDigits + 4]byte
 		b   []byte
 		exp int
 	)
 	if f.prec > 0 {
-		if x.isCompact() {
-			b = formatCompact(x.compact)
-		} else {
-			b = formatUnscaled(&x.unscaled)
-		}
+		b = appendCoeff(buf[:0], x)
 		orig := len(b)
-		b = roundString(b, x.Context.RoundingMode, !neg, f.prec)
+		b = roundString(b, x.Context, !neg, f.prec)
 		exp = int(x.exp) + orig - len(b)
 	} else if f.prec < 0 {
 		f.prec = -f.prec
 		exp = -f.prec
 	} else {
-		b = []byte{'0'}
+		b = append(buf[:0], '0')
 	}
 
 	// "Next, the adjusted exponent is calculated; this is the exponent, plus
@@ -223,6 +280,14 @@ func (f *formatter) format(x *Big, format format, e byte) {
 	// is, exponent+(clength-1), where clength is the length of the coefficient
 	// in decimal digits.
 	adj := exp + (len(b) - 1)
+	if format == eng || format == si {
+		if format == eng && exp <= 0 && adj >= -6 {
+			f.formatPlain(b, exp)
+			return
+		}
+		f.formatEng(b, exp, x.isZero(), format == si, e)
+		return
+	}
 	if format != sci {
 		if exp <= 0 && (format == plain || adj >= -6) {
 			// "If the exponent is less than or equal to zero and the adjusted
@@ -236,29 +301,174 @@ func (f *formatter) format(x *Big, format format, e byte) {
 
 		// No decimal places, write b and fill with zeros.
 		if format == plain && exp > 0 {
-			f.Write(b)
-			io.CopyN(f, zeroReader{}, int64(exp))
+			f.b = append(f.b, b...)
+			f.b = appendZeros(f.b, exp)
 			return
 		}
 	}
 	f.formatSci(b, adj, e)
 }
 
+// plainDigits returns the number of digits needed to write a
+// coefficient with n digits and the exponent exp without
+// exponential notation.
+func plainDigits(n, exp int) int64 {
+	if exp > 0 {
+		return int64(n) + int64(exp)
+	}
+	return int64(n) - int64(exp)
+}
+
+// AppendFormat appends x, formatted according to the format fmt
+// and precision prec, to dst and returns the extended buffer.
+//
+// The format fmt is one of the verbs supported by (*Big).Format
+// that take no flags: 's', 'd', 'e', 'E', 'f', 'F', 'g', 'G', or
+// 'n'. prec has the same meaning as the precision of those verbs.
+// A negative prec means no precision was given. For example,
+//
+// 	AppendFormat(dst, x, 'f', 2)
+//
+// is the same as
+//
+// 	fmt.Appendf(dst, "%.2f", x)
+//
+// An unknown format appends '%' followed by fmt, like
+// strconv.AppendFloat.
+//
+// AppendFormat does not allocate if dst has enough capacity and
+// x's coefficient has at most 76 digits.
+func AppendFormat(dst []byte, x *Big, fmt byte, prec int) []byte {
+	if x == nil {
+		return append(dst, "<nil>"...)
+	}
+	if debug {
+		x.validate()
+	}
+
+	hasPrec := prec >= 0
+	if !hasPrec {
+		prec = x.Precision()
+	}
+	f := formatter{b: dst, prec: prec, width: noWidth}
+	if !f.formatVerb(x, fmt, hasPrec) {
+		return append(dst, '%', fmt)
+	}
+	return f.b
+}
+
+// formatVerb formats x using one of the verbs shared by Format and
+// AppendFormat and reports whether c is one of those verbs. hasPrec
+// reports whether f.prec was provided by the caller.
+func (f *formatter) formatVerb(x *Big, c byte, hasPrec bool) bool {
+	// noE is a placeholder for formats that do not use scientific notation
+	// and don't require 'e' or 'E'
+	const noE = 0
+	e := sciE[x.Context.OperatingMode]
+	switch c {
+	case 's', 'd':
+		f.format(x, normal, e)
+	case 'e', 'E':
+		f.format(x, sci, c)
+	case 'n':
+		f.format(x, eng, e)
+	case 'f', 'F':
+		if hasPrec {
+			// %f's precision means "number of digits after the radix"
+			if x.exp > 0 {
+				f.prec += (x.exp + x.Precision())
+			} else {
+				if adj := x.exp + x.Precision(); adj > -f.prec {
+					f.prec += adj
+				} else {
+					f.prec = -f.prec
+				}
+			}
+		}
+		f.format(x, plain, noE)
+	case 'g', 'G':
+		// %g's precision means "number of significant digits"
+		f.format(x, plain, noE)
+	default:
+		return false
+	}
+	return true
+}
+
 // formatSci returns the scientific version of b.
 func (f *formatter) formatSci(b []byte, adj int, e byte) {
-	f.WriteByte(b[0])
+	f.b = append(f.b, b[0])
 
 	if len(b) > 1 {
-		f.WriteByte('.')
-		f.Write(b[1:])
+		f.b = append(f.b, '.')
+		f.b = append(f.b, b[1:]...)
 	}
 
-	// If negative, the call to strconv.Itoa will add the minus sign for us.
-	f.WriteByte(e)
+	f.formatExp(adj, e)
+}
+
+// formatExp writes the exponent adj using e as the exponent character.
+func (f *formatter) formatExp(adj int, e byte) {
+	// If negative, the call to strconv.AppendInt will add the minus sign
+	// for us.
+	f.b = append(f.b, e)
 	if adj > 0 {
-		f.WriteByte('+')
+		f.b = append(f.b, '+')
 	}
-	f.WriteString(strconv.Itoa(adj))
+	f.b = strconv.AppendInt(f.b, int64(adj), 10)
+}
+
+// formatEng writes b, which has the exponent exp, in engineering
+// notation: the exponent is a multiple of three and, unless b is
+// zero, there are one to three digits before the radix. If si is
+// true, the exponent is written as an SI prefix, if one exists.
+//
+// Zeros are written as described in
+// http://speleotrove.com/decimal/daconvs.html#reftoeng
+func (f *formatter) formatEng(b []byte, exp int, zero, si bool, e byte) {
+	// left is the number of digits to the left of the radix in
+	// plain notation and dot is the number after adjusting the
+	// exponent.
+	left := exp + len(b)
+	var dot int
+	if zero {
+		dot = mod3(left+1) - 1
+	} else {
+		dot = mod3(left-1) + 1
+	}
+
+	switch {
+	case dot <= 0:
+		f.b = append(f.b, "0."...)
+		f.b = appendZeros(f.b, -dot)
+		f.b = append(f.b, b...)
+	case dot >= len(b):
+		f.b = append(f.b, b...)
+		f.b = appendZeros(f.b, dot-len(b))
+	default:
+		f.b = append(f.b, b[:dot]...)
+		f.b = append(f.b, '.')
+		f.b = append(f.b, b[dot:]...)
+	}
+
+	adj := left - dot
+	if si {
+		if p, ok := siPrefixes[adj]; ok {
+			f.b = append(f.b, p...)
+			return
+		}
+	}
+	if adj != 0 {
+		f.formatExp(adj, e)
+	}
+}
+
+// mod3 returns x modulo 3 in the range [0, 3).
+func mod3(x int) int {
+	if x %= 3; x < 0 {
+		x += 3
+	}
+	return x
 }
 
 // formatPlain returns the plain string version of b.
@@ -268,27 +478,27 @@ func (f *formatter) formatPlain(b []byte, exp int) {
 	switch radix := len(b) + exp; {
 	// log10(b) == scale, so immediately before b: 0.123456
 	case radix == 0:
-		f.WriteString(zeroRadix)
-		f.Write(b)
+		f.b = append(f.b, zeroRadix...)
+		f.b = append(f.b, b...)
 
 	// log10(b) > scale, so somewhere inside b: 123.456
 	case radix > 0:
-		f.Write(b[:radix])
+		f.b = append(f.b, b[:radix]...)
 		if radix < len(b) {
-			f.WriteByte('.')
-			f.Write(b[radix:])
+			f.b = append(f.b, '.')
+			f.b = append(f.b, b[radix:]...)
 		}
 
 	// log10(b) < scale, so before p "0s" and before b: 0.00000123456
 	default:
-		f.WriteString(zeroRadix)
-		io.CopyN(f, zeroReader{}, -int64(radix))
+		f.b = append(f.b, zeroRadix...)
+		f.b = appendZeros(f.b, -radix)
 
 		end := len(b)
 		if f.prec < end {
 			end = f.prec
 		}
-		f.Write(b[:end])
+		f.b = append(f.b, b[:end]...)
 	}
 }
 
@@ -332,16 +542,3 @@ func (s spaceReader) Read(p []byte) (n int, err error) {
 	}
 	return n, nil
 }
-
-// stateWrapper is a wrapper around an io.Writer to add WriteByte and
-// WriteString methods.
-type stateWrapper struct{ fmt.State }
-
-func (w stateWrapper) WriteByte(c byte) error {
-	_, err := w.Write([]byte{c})
-	return err
-}
-
-func (w stateWrapper) WriteString(s string) (int, error) {
-	return io.WriteString(w.State, s)
-}
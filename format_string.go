@@ -11,11 +11,13 @@ func _() {
 	_ = x[normal-0]
 	_ = x[plain-1]
 	_ = x[sci-2]
+	_ = x[eng-3]
+	_ = x[si-4]
 }
 
-const _format_name = "normalplainsci"
+const _format_name = "normalplainsciengsi"
 
-var _format_index = [...]uint8{0, 6, 11, 14}
+var _format_index = [...]uint8{0, 6, 11, 14, 17, 19}
 
 func (i format) String() string {
 	if i >= format(len(_format_index)-1) {
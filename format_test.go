@@ -2,6 +2,7 @@ package decimal
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
 )
 
@@ -46,6 +47,13 @@ func TestRoundString(t *testing.T) {
 		{"+12395", ToNearestEven, 4, "1240"},
 		{"+99", ToNearestEven, 1, "10"},
 		{"+400", ToZero /* mode is irrelevant */, 1, "4"},
+		{"+125", ZeroFiveUp, 2, "12"},
+		{"-105", ZeroFiveUp, 2, "11"},
+		{"+1051", ZeroFiveUp, 3, "106"},
+		{"+1050", ZeroFiveUp, 3, "105"},
+		{"+124", ToOdd, 2, "13"},
+		{"-134", ToOdd, 2, "13"},
+		{"+1999", ToOdd, 2, "19"},
 	}
 	tests = append(tests, even...)
 	tests = append(tests, away...)
@@ -56,7 +64,7 @@ func TestRoundString(t *testing.T) {
 	for i, test := range tests {
 		pos := test.input[0] == '+'
 		inp := test.input[1:]
-		got := roundString([]byte(inp), test.mode, pos, test.prec)
+		got := roundString([]byte(inp), Context{RoundingMode: test.mode}, pos, test.prec)
 		if string(got) != test.expect {
 			t.Fatalf(`#%d:
 [round(%q, %s, %d)]
@@ -76,6 +84,10 @@ func TestDecimal_Format(t *testing.T) {
 		{"%.10f", "0.1234567891", "0.1234567891"},
 		{"%.10f", "0.01", "0.0100000000"},
 		{"%.10f", "0.0000000000000000000000000000000000000000000000000000000000001", "0.0000000000"},
+		{"%n", "1.23E+7", "12.3E+6"},
+		{"%n", "0.0001", "0.0001"},
+		{"%.2n", "123456", "120E+3"},
+		{"%+n", "1E-7", "+100E-9"},
 	} {
 		z, _ := new(Big).SetString(s.input)
 		got := fmt.Sprintf(s.format, z)
@@ -87,3 +99,162 @@ wanted: %s
 		}
 	}
 }
+
+func TestBig_EngString(t *testing.T) {
+	for i, test := range [...]struct {
+		input, want string
+	}{
+		{"0", "0"},
+		{"123", "123"},
+		{"-123", "-123"},
+		{"1.23E+3", "1.23E+3"},
+		{"123E+3", "123E+3"},
+		{"12E-5", "0.00012"},
+		{"1E-7", "100E-9"},
+		{"1E+1", "10"},
+		{"1E+3", "1E+3"},
+		{"-1.2E+5", "-120E+3"},
+		{"0E+1", "0.00E+3"},
+		{"0E+2", "0.0E+3"},
+		{"0E+3", "0E+3"},
+		{"0E-7", "0.0E-6"},
+		{"0E-8", "0.00E-6"},
+		{"-0E-9", "-0E-9"},
+		{"0.000001", "0.000001"},
+		{"123456789012345678901234567890E+7", "1.23456789012345678901234567890E+36"},
+		{"Inf", "Infinity"},
+		{"-NaN5", "-NaN5"},
+	} {
+		x, _ := new(Big).SetString(test.input)
+		if got := x.EngString(); got != test.want {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.want, got)
+		}
+		// The result is exact.
+		y, _ := new(Big).SetString(test.want)
+		if x.IsFinite() && x.Cmp(y) != 0 {
+			t.Fatalf("#%d: %q does not round trip", i, test.want)
+		}
+	}
+}
+
+func TestBig_SIString(t *testing.T) {
+	for i, test := range [...]struct {
+		input, want string
+	}{
+		{"0", "0"},
+		{"100", "100"},
+		{"1500", "1.500k"},
+		{"1.5E+3", "1.5k"},
+		{"-2.5E+6", "-2.5M"},
+		{"1E+18", "1E"},
+		{"0.00025", "250µ"},
+		{"0.5", "500m"},
+		{"1E-30", "1q"},
+		{"12E+30", "12Q"},
+		{"1E+33", "1E+33"},
+		{"1E-33", "1E-33"},
+		{"0E+1", "0.00k"},
+		{"0E-5", "0.00m"},
+		{"Inf", "Infinity"},
+		{"-Inf", "-Infinity"},
+		{"NaN", "NaN"},
+	} {
+		x, _ := new(Big).SetString(test.input)
+		got := x.SIString()
+		if got != test.want {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.want, got)
+		}
+		y, _ := new(Big).SetSIString(got)
+		if y.Context.Conditions&ConversionSyntax != 0 {
+			t.Fatalf("#%d: could not parse %q", i, got)
+		}
+		if x.IsFinite() && x.Cmp(y) != 0 || !x.IsFinite() && y.String() != x.String() {
+			t.Fatalf("#%d: %q: wanted %s, got %s", i, got, x, y)
+		}
+	}
+
+	for i, test := range [...]struct {
+		input, want string
+	}{
+		{"3u", "0.000003"},
+		{"3μ", "0.000003"},
+		{"-4.7n", "-4.7E-9"},
+		{"1.5", "1.5"},
+		{"-Inf", "-Infinity"},
+		{"k", "NaN"},
+		{"1.5kk", "NaN"},
+	} {
+		x, _ := new(Big).SetSIString(test.input)
+		if !strEq(x, test.want) {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.want, x)
+		}
+	}
+}
+
+func TestAppendFormat(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	xs := decs("0", "-0", "1E+5", "0.000123", "-12.5E-10", "Inf", "-NaN12", "sNaN")
+	for i := 0; i < 500; i++ {
+		x := randWords(r)
+		xs = append(xs, x, padBig(x, r.Intn(60)))
+	}
+	for i, x := range xs {
+		for _, verb := range []byte("sdeEfFgGn") {
+			for _, prec := range []int{-1, 0, 3, 40} {
+				format := "%" + string(verb)
+				if prec >= 0 {
+					format = fmt.Sprintf("%%.%d%c", prec, verb)
+				}
+				want := "prefix" + fmt.Sprintf(format, x)
+				got := AppendFormat([]byte("prefix"), x, verb, prec)
+				if string(got) != want {
+					t.Fatalf("#%d: AppendFormat(%s, %q): wanted %q, got %q",
+						i, x, format, want, got)
+				}
+			}
+		}
+
+		want, _ := x.MarshalText()
+		got, err := x.AppendText([]byte("prefix"))
+		if err != nil || string(got) != "prefix"+string(want) {
+			t.Fatalf("#%d: AppendText(%s): wanted %q, got (%q, %v)", i, x, want, got, err)
+		}
+
+		// Check the coefficient against math/big.
+		if x.IsFinite() {
+			n := new(Big).Copy(x)
+			n.exp = 0
+			if got, want := string(appendCoeff(nil, x)), n.Int(nil).String(); got != want && "-"+got != want {
+				t.Fatalf("#%d: appendCoeff(%s): wanted %s, got %s", i, x, want, got)
+			}
+		}
+	}
+
+	if got := string(AppendFormat(nil, xs[0], 'x', 2)); got != "%x" {
+		t.Fatalf("wanted %q, got %q", "%x", got)
+	}
+}
+
+func TestAppendFormat_Allocs(t *testing.T) {
+	x := decs("-1234567.891", "12345678901234567890123456789.01234567", "1E-30")
+	buf := make([]byte, 0, 128)
+	n := testing.AllocsPerRun(100, func() {
+		for _, x := range x {
+			AppendFormat(buf, x, 'e', -1)
+			AppendFormat(buf, x, 'f', 5)
+			AppendFormat(buf, x, 'n', 3)
+			x.AppendText(buf)
+		}
+	})
+	if n != 0 {
+		t.Fatalf("wanted 0 allocations, got %f", n)
+	}
+}
+
+func BenchmarkAppendFormat(b *testing.B) {
+	x := new(Big).SetMantScale(-123456789, 4)
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		buf = AppendFormat(buf[:0], x, 'f', 2)
+	}
+}
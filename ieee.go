@@ -0,0 +1,202 @@
+package decimal
+
+import "encoding/binary"
+
+// ieeeDecimal is an unpacked IEEE 754-2008 decimal interchange
+// value. It's the intermediate form between Big and the
+// fixed-size Decimal64 and Decimal128 types.
+type ieeeDecimal struct {
+	form   form
+	hi, lo uint64 // coefficient, or payload if a NaN
+	exp    int
+}
+
+// big sets z to d and returns z.
+func (d ieeeDecimal) big(z *Big) *Big {
+	switch {
+	case d.form&nan != 0:
+		z.form = d.form
+		z.compact = 0
+		if d.hi == 0 {
+			z.compact = d.lo
+		}
+		return z
+	case d.form&inf != 0:
+		return z.SetInf(d.form&signbit != 0)
+	}
+	if d.hi == 0 {
+		z.SetUint64(d.lo)
+	} else {
+		var buf [16]byte
+		binary.BigEndian.PutUint64(buf[0:8], d.hi)
+		binary.BigEndian.PutUint64(buf[8:16], d.lo)
+		z.unscaled.SetBytes(buf[:])
+		z.norm()
+	}
+	z.exp = d.exp
+	z.form = d.form & signbit
+	return z
+}
+
+// ieeeFromBig returns x rounded to fit the IEEE 754-2008 format
+// described by ctx.
+//
+// x is rounded using x's RoundingMode. NaN payloads that are
+// larger than maxPayload are discarded.
+func ieeeFromBig(ctx Context, x *Big, maxPayload uint64) (d ieeeDecimal) {
+	d.form = x.form
+	switch {
+	case x.IsNaN(0):
+		if x.compact <= maxPayload {
+			d.lo = x.compact
+		}
+		return d
+	case x.IsInf(0):
+		return d
+	}
+
+	ctx.RoundingMode = x.Context.RoundingMode
+	t := ctx.Set(getDec(ctx), x)
+	defer putDec(t)
+	if !t.IsFinite() {
+		// Overflow.
+		d.form = t.form
+		return d
+	}
+
+	// IEEE 754-2008 formats have a fixed-size coefficient, so
+	// the largest exponent is lower than Emax. Pad the
+	// coefficient with zeros until the exponent fits.
+	if top := ctx.etop(); t.exp > top {
+		if !t.isZero() {
+			ctx.shiftl(t, uint64(t.exp-top))
+		}
+		t.exp = top
+	}
+
+	d.form = t.form
+	d.exp = t.exp
+	if t.isCompact() {
+		d.lo = t.compact
+		return d
+	}
+	var buf [16]byte
+	t.unscaled.FillBytes(buf[:])
+	d.hi = binary.BigEndian.Uint64(buf[0:8])
+	d.lo = binary.BigEndian.Uint64(buf[8:16])
+	return d
+}
+
+// ieeeParse parses s as a decimal in the IEEE 754-2008 format
+// described by ctx.
+func ieeeParse(ctx Context, s string, maxPayload uint64) (ieeeDecimal, error) {
+	var x Big
+	if _, ok := x.SetString(s); !ok || x.Context.Conditions&ConversionSyntax != 0 {
+		return ieeeDecimal{}, ConversionSyntax
+	}
+	return ieeeFromBig(ctx, &x, maxPayload), nil
+}
+
+// Declets are 10-bit groups of bits that encode three decimal
+// digits in the densely packed decimal (DPD) encoding.
+var (
+	dpdToBin [1 << 10]uint16
+	binToDPD [1000]uint16
+)
+
+func init() {
+	for i := range dpdToBin {
+		dpdToBin[i] = decodeDeclet(uint16(i))
+	}
+	for i := range binToDPD {
+		binToDPD[i] = encodeDeclet(uint16(i))
+	}
+}
+
+// decodeDeclet returns the three digits encoded in the declet d.
+//
+// Each of the 24 non-canonical declets decodes to the same
+// value as its canonical counterpart.
+func decodeDeclet(d uint16) uint16 {
+	// Bits pqr stu v wxy, most significant first.
+	var (
+		pqr = d >> 7 & 7
+		stu = d >> 4 & 7
+		v   = d >> 3 & 1
+		wx  = d >> 1 & 3
+		y   = d & 1
+		st  = d >> 5 & 3
+		pq  = d >> 8 & 3
+		r   = pqr & 1
+		u   = stu & 1
+	)
+
+	var d2, d1, d0 uint16
+	switch {
+	case v == 0:
+		d2, d1, d0 = pqr, stu, wx<<1|y
+	case wx == 0:
+		d2, d1, d0 = pqr, stu, 8|y
+	case wx == 1:
+		d2, d1, d0 = pqr, 8|u, st<<1|y
+	case wx == 2:
+		d2, d1, d0 = 8|r, stu, pq<<1|y
+	case st == 0:
+		d2, d1, d0 = 8|r, 8|u, pq<<1|y
+	case st == 1:
+		d2, d1, d0 = 8|r, pq<<1|u, 8|y
+	case st == 2:
+		d2, d1, d0 = pqr, 8|u, 8|y
+	default:
+		d2, d1, d0 = 8|r, 8|u, 8|y
+	}
+	return d2*100 + d1*10 + d0
+}
+
+// encodeDeclet returns the canonical declet for n, which must be
+// in the range [0, 999].
+func encodeDeclet(n uint16) uint16 {
+	d2, d1, d0 := n/100, n/10%10, n%10
+
+	// Digits abcd efgh ijkm, most significant first.
+	var (
+		a, bcd = d2 >> 3, d2 & 7
+		e, fgh = d1 >> 3, d1 & 7
+		i, jkm = d0 >> 3, d0 & 7
+		d      = d2 & 1
+		h      = d1 & 1
+		m      = d0 & 1
+		fg     = d1 >> 1 & 3
+		jk     = d0 >> 1 & 3
+	)
+
+	switch a<<2 | e<<1 | i {
+	case 0: // bcd fgh 0 jkm
+		return bcd<<7 | fgh<<4 | jkm
+	case 1: // bcd fgh 1 00m
+		return bcd<<7 | fgh<<4 | 8 | m
+	case 2: // bcd jkh 1 01m
+		return bcd<<7 | jk<<5 | h<<4 | 10 | m
+	case 4: // jkd fgh 1 10m
+		return jk<<8 | d<<7 | fgh<<4 | 12 | m
+	case 6: // jkd 00h 1 11m
+		return jk<<8 | d<<7 | h<<4 | 14 | m
+	case 5: // fgd 01h 1 11m
+		return fg<<8 | d<<7 | 1<<5 | h<<4 | 14 | m
+	case 3: // bcd 10h 1 11m
+		return bcd<<7 | 2<<5 | h<<4 | 14 | m
+	default: // 00d 11h 1 11m
+		return d<<7 | 3<<5 | h<<4 | 14 | m
+	}
+}
+
+// sign returns the sign of d, as described by Big.Sign.
+func (d ieeeDecimal) sign() int {
+	if d.form&nan != 0 || (d.form&inf == 0 && d.hi == 0 && d.lo == 0) {
+		return 0
+	}
+	if d.form&signbit != 0 {
+		return -1
+	}
+	return 1
+}
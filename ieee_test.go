@@ -0,0 +1,244 @@
+package decimal
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDeclet(t *testing.T) {
+	for n := uint16(0); n < 1000; n++ {
+		d := encodeDeclet(n)
+		if d >= 1<<10 {
+			t.Fatalf("%d: declet out of range: %#x", n, d)
+		}
+		if got := decodeDeclet(d); got != n {
+			t.Fatalf("%d: wanted %d, got %d (declet %#x)", n, n, got, d)
+		}
+	}
+	for d := uint16(0); d < 1<<10; d++ {
+		n := decodeDeclet(d)
+		if n >= 1000 {
+			t.Fatalf("%#x: value out of range: %d", d, n)
+		}
+		// Non-canonical declets must decode to a value whose
+		// canonical declet differs only in the ignored bits.
+		if c := encodeDeclet(n); c != d && d&0x6e != 0x6e {
+			t.Fatalf("%#x: non-canonical declet for %d, canonical %#x", d, n, c)
+		}
+	}
+}
+
+func TestDecimal64_Encoding(t *testing.T) {
+	for i, test := range [...]struct {
+		s   string
+		bid uint64
+		dpd uint64
+	}{
+		{"0", 0x31c0000000000000, 0x2238000000000000},
+		{"-7.50", 0xb1800000000002ee, 0xa2300000000003d0},
+		{"1E-398", 0x0000000000000001, 0x0000000000000001},
+		{"9.999999999999999E+384", 0x77fb86f26fc0ffff, 0x77fcff3fcff3fcff},
+		{"1.000000000000000E+384", 0x5fe38d7ea4c68000, 0x47fc000000000000},
+		{"Infinity", 0x7800000000000000, 0x7800000000000000},
+		{"-Infinity", 0xf800000000000000, 0xf800000000000000},
+		{"NaN", 0x7c00000000000000, 0x7c00000000000000},
+		{"sNaN", 0x7e00000000000000, 0x7e00000000000000},
+	} {
+		x, err := ParseDecimal64(test.s)
+		if err != nil {
+			t.Fatalf("#%d: ParseDecimal64(%q): %v", i, test.s, err)
+		}
+		if got := x.BID(); got != test.bid {
+			t.Fatalf("#%d: BID: wanted %#x, got %#x", i, test.bid, got)
+		}
+		if got := x.DPD(); got != test.dpd {
+			t.Fatalf("#%d: DPD: wanted %#x, got %#x", i, test.dpd, got)
+		}
+		if got := Decimal64FromBID(test.bid); got != x {
+			t.Fatalf("#%d: Decimal64FromBID: wanted %s, got %s", i, x, got)
+		}
+		if got := Decimal64FromDPD(test.dpd); got != x {
+			t.Fatalf("#%d: Decimal64FromDPD: wanted %s, got %s", i, x, got)
+		}
+		if got := x.String(); got != test.s {
+			t.Fatalf("#%d: String: wanted %q, got %q", i, test.s, got)
+		}
+	}
+}
+
+func TestDecimal64_NonCanonical(t *testing.T) {
+	for i, test := range [...]struct {
+		bid uint64
+		s   string
+	}{
+		// Coefficient is 10^16.
+		{3<<61 | 398<<51 | 0x386f26fc10000, "0"},
+		// NaN with trailing bits in the exponent continuation.
+		{0x7c3c000000000001, "NaN1"},
+		// Infinity with trailing bits.
+		{0x7a000000000000ff, "Infinity"},
+	} {
+		x := Decimal64FromBID(test.bid)
+		if got := x.String(); got != test.s {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.s, got)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		b := rng.Uint64()
+		x := Decimal64FromDPD(b)
+		if y := Decimal64FromDPD(x.DPD()); y != x {
+			t.Fatalf("%#x: wanted %s, got %s", b, x, y)
+		}
+		x = Decimal64FromBID(b)
+		if y := Decimal64FromBID(x.BID()); y != x {
+			t.Fatalf("%#x: wanted %s, got %s", b, x, y)
+		}
+		var z Big
+		if x.IsNaN(0) {
+			continue
+		}
+		if y := z.SetDecimal64(x).Decimal64(); y != x {
+			t.Fatalf("%#x: wanted %s, got %s", b, x, y)
+		}
+	}
+}
+
+func TestDecimal64_Arith(t *testing.T) {
+	dec := func(s string) Decimal64 {
+		x, err := ParseDecimal64(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return x
+	}
+	for i, test := range [...]struct {
+		got  Decimal64
+		want string
+	}{
+		{dec("1.1").Add(dec("2.2")), "3.3"},
+		{dec("1").Quo(dec("3")), "0.3333333333333333"},
+		{dec("2").Quo(dec("3")), "0.6666666666666667"},
+		{dec("9999999999999999").Add(dec("1")), "1.000000000000000E+16"},
+		{dec("9.999999999999999E+384").Mul(dec("10")), "Infinity"},
+		{dec("1E-398").Quo(dec("2")), "0E-398"},
+		{dec("1E-383").Quo(dec("10")), "1E-384"},
+		{dec("1.20").Sub(dec("1.2")), "0.00"},
+		{dec("1").Quo(dec("0")), "Infinity"},
+		{dec("-5").Abs(), "5"},
+		{dec("5").Neg(), "-5"},
+		{NewDecimal64(12345, 2), "123.45"},
+		{NewDecimal64(1, -380), "1.00000000000E+380"},
+		{NewDecimal64(1, -400), "Infinity"},
+	} {
+		if got := test.got.String(); got != test.want {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.want, got)
+		}
+	}
+}
+
+func TestDecimal128_Encoding(t *testing.T) {
+	for i, test := range [...]struct {
+		s            string
+		bidHi, bidLo uint64
+		dpdHi, dpdLo uint64
+	}{
+		{"0", 0x3040000000000000, 0, 0x2208000000000000, 0},
+		{"1", 0x3040000000000000, 1, 0x2208000000000000, 1},
+		{"-7.50", 0xb03c000000000000, 0x2ee, 0xa207800000000000, 0x3d0},
+		{"1E-6176", 0, 1, 0, 1},
+		{
+			"9.999999999999999999999999999999999E+6144",
+			0x5fffed09bead87c0, 0x378d8e63ffffffff,
+			0x77ffcff3fcff3fcf, 0xf3fcff3fcff3fcff,
+		},
+		{"Infinity", 0x7800000000000000, 0, 0x7800000000000000, 0},
+		{"-NaN", 0xfc00000000000000, 0, 0xfc00000000000000, 0},
+		{"sNaN", 0x7e00000000000000, 0, 0x7e00000000000000, 0},
+		{
+			"NaN799799799799799799799799799799799",
+			0x7c00276ee4885174, 0xb90d72d6cbfadff7,
+			0x7c003dff7fdff7fd, 0xff7fdff7fdff7fdf,
+		},
+	} {
+		x, err := ParseDecimal128(test.s)
+		if err != nil {
+			t.Fatalf("#%d: ParseDecimal128(%q): %v", i, test.s, err)
+		}
+		if hi, lo := x.BID(); hi != test.bidHi || lo != test.bidLo {
+			t.Fatalf("#%d: BID: wanted %#x %#x, got %#x %#x",
+				i, test.bidHi, test.bidLo, hi, lo)
+		}
+		if hi, lo := x.DPD(); hi != test.dpdHi || lo != test.dpdLo {
+			t.Fatalf("#%d: DPD: wanted %#x %#x, got %#x %#x",
+				i, test.dpdHi, test.dpdLo, hi, lo)
+		}
+		if got := Decimal128FromBID(test.bidHi, test.bidLo); got != x {
+			t.Fatalf("#%d: Decimal128FromBID: wanted %s, got %s", i, x, got)
+		}
+		if got := Decimal128FromDPD(test.dpdHi, test.dpdLo); got != x {
+			t.Fatalf("#%d: Decimal128FromDPD: wanted %s, got %s", i, x, got)
+		}
+		if got := x.String(); got != test.s {
+			t.Fatalf("#%d: String: wanted %q, got %q", i, test.s, got)
+		}
+	}
+
+	const sNaN999 = "sNaN999999999999999999999999999999999"
+	if got := Decimal128FromDPD(1<<63-1, 1<<64-1).String(); got != sNaN999 {
+		t.Fatalf("Decimal128FromDPD: wanted %q, got %q", sNaN999, got)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		hi, lo := rng.Uint64(), rng.Uint64()
+		x := Decimal128FromDPD(hi, lo)
+		if y := Decimal128FromDPD(x.DPD()); y != x {
+			t.Fatalf("%#x %#x: wanted %s, got %s", hi, lo, x, y)
+		}
+		if y := Decimal128FromBID(x.BID()); y != x {
+			t.Fatalf("%#x %#x: wanted %s, got %s", hi, lo, x, y)
+		}
+		if y, err := ParseDecimal128(x.String()); err != nil || y != x {
+			t.Fatalf("%#x %#x: wanted %s, got %s (%v)", hi, lo, x, y, err)
+		}
+		var z Big
+		if x.IsNaN(0) {
+			continue
+		}
+		if y := z.SetDecimal128(x).Decimal128(); y != x {
+			t.Fatalf("%#x %#x: wanted %s, got %s", hi, lo, x, y)
+		}
+	}
+}
+
+func TestDecimal128_Arith(t *testing.T) {
+	dec := func(s string) Decimal128 {
+		x, err := ParseDecimal128(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return x
+	}
+	for i, test := range [...]struct {
+		got  Decimal128
+		want string
+	}{
+		{dec("1").Quo(dec("3")), "0.3333333333333333333333333333333333"},
+		{dec("9999999999999999999999999999999999").Add(dec("1")), "1.000000000000000000000000000000000E+34"},
+		{dec("12345678901234567890").Mul(dec("12345678901234567890")), "1.524157875323883675019051998750191E+38"},
+		{dec("-1E-6176").Quo(dec("2")), "-0E-6176"},
+		{NewDecimal128(-25, 1), "-2.5"},
+	} {
+		if got := test.got.String(); got != test.want {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.want, got)
+		}
+	}
+	if NewDecimal128(10, 1).Cmp(NewDecimal128(100, 2)) != 0 {
+		t.Fatal("1.0 != 1.00")
+	}
+	if NewDecimal128(10, 1) == NewDecimal128(100, 2) {
+		t.Fatal("1.0 and 1.00 have the same representation")
+	}
+}
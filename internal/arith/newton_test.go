@@ -0,0 +1,60 @@
+package arith
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestQuoRemBig(t *testing.T) {
+	setThreshold(t, &NTTThreshold, 50)
+	setThreshold(t, &NewtonThreshold, 20)
+
+	r := rand.New(rand.NewSource(1))
+	for _, n := range [...][2]int{
+		{40, 20}, {41, 20}, {100, 20}, {100, 50}, {100, 99},
+		{500, 250}, {1000, 300}, {3000, 1000}, {3000, 2980},
+	} {
+		x, y := randInt(r, n[0]), randInt(r, n[1])
+		q0, r0 := new(big.Int).QuoRem(x, y, new(big.Int))
+		q1, r1 := QuoRemBig(new(big.Int), x, y, new(big.Int))
+		if q1.Cmp(q0) != 0 || r1.Cmp(r0) != 0 {
+			t.Fatalf("%v: x / y: wrong result", n)
+		}
+
+		// Exact divisions and remainders of y - 1 are the edge cases
+		// of the correction steps.
+		x.Mul(y, q0)
+		for _, d := range [...]int64{0, -1} {
+			x.Add(x, y)
+			x.Add(x, big.NewInt(d))
+			q0, r0 := new(big.Int).QuoRem(x, y, new(big.Int))
+			// q and r may alias x and y.
+			q1, r1 := new(big.Int).Set(x), new(big.Int).Set(y)
+			QuoRemBig(q1, q1, r1, r1)
+			if q1.Cmp(q0) != 0 || r1.Cmp(r0) != 0 {
+				t.Fatalf("%v: (x + %d) / y: wrong result", n, d)
+			}
+		}
+	}
+}
+
+func BenchmarkQuoRemBig(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range [...]int{1e5, 2e5, 2.5e5, 3e5, 5e5} {
+		x, y := randInt(r, 2*n), randInt(r, n)
+		var q, m big.Int
+		b.Run(fmt.Sprintf("big/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				q.QuoRem(x, y, &m)
+			}
+		})
+		b.Run(fmt.Sprintf("newton/%d", n), func(b *testing.B) {
+			setThreshold(b, &NewtonThreshold, n/2)
+			for i := 0; i < b.N; i++ {
+				QuoRemBig(&q, x, y, &m)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package arith
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// randInt returns a random n-word integer with a random sign.
+func randInt(r *rand.Rand, n int) *big.Int {
+	x := new(big.Int).Lsh(big.NewInt(1), uint(n*_W))
+	x.Rand(r, x)
+	if r.Intn(2) == 0 {
+		x.Neg(x)
+	}
+	return x
+}
+
+func setThreshold(t testing.TB, p *int, v int) {
+	old := *p
+	*p = v
+	t.Cleanup(func() { *p = old })
+}
+
+func TestMulBig(t *testing.T) {
+	setThreshold(t, &NTTThreshold, 1)
+
+	r := rand.New(rand.NewSource(1))
+	for _, n := range [...][2]int{
+		{1, 1}, {1, 2}, {2, 3}, {7, 1}, {16, 16}, {100, 3},
+		{255, 257}, {1000, 1000}, {1234, 4321}, {5000, 20},
+	} {
+		x, y := randInt(r, n[0]), randInt(r, n[1])
+		want := new(big.Int).Mul(x, y)
+		if got := MulBig(new(big.Int), x, y); got.Cmp(want) != 0 {
+			t.Fatalf("%v: x × y: wrong result", n)
+		}
+		want.Mul(x, x)
+		if got := MulBig(new(big.Int), x, x); got.Cmp(want) != 0 {
+			t.Fatalf("%v: x × x: wrong result", n)
+		}
+		// z may alias x and y.
+		z := new(big.Int).Set(x)
+		if MulBig(z, z, z); z.Cmp(want) != 0 {
+			t.Fatalf("%v: z × z: wrong result", n)
+		}
+	}
+
+	// All ones maximizes the size of the elements of the product.
+	x := new(big.Int).Lsh(big.NewInt(1), 1<<16)
+	x.Sub(x, big.NewInt(1))
+	want := new(big.Int).Mul(x, x)
+	if got := MulBig(new(big.Int), x, x); got.Cmp(want) != 0 {
+		t.Fatal("(2**65536 - 1)**2: wrong result")
+	}
+
+	var z big.Int
+	if MulBig(&z, x, new(big.Int)); z.Sign() != 0 {
+		t.Fatalf("x × 0: wanted 0, got %s", &z)
+	}
+}
+
+func BenchmarkMulBig(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range [...]int{1e4, 5e4, 52e3, 54e3, 56e3, 6e4, 7e4, 1e5, 3e5} {
+		x, y := randInt(r, n), randInt(r, n)
+		var z big.Int
+		b.Run(fmt.Sprintf("big/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				z.Mul(x, y)
+			}
+		})
+		b.Run(fmt.Sprintf("ntt/%d", n), func(b *testing.B) {
+			setThreshold(b, &NTTThreshold, 1)
+			for i := 0; i < b.N; i++ {
+				MulBig(&z, x, y)
+			}
+		})
+	}
+}
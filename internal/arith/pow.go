@@ -13,7 +13,7 @@ const (
 	PowTabLen = 20
 
 	// BigPowTabLen is the largest cached power for *big.Ints.
-	BigPowTabLen = 1e5
+	BigPowTabLen = 1 << 14
 )
 
 var (
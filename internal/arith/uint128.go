@@ -0,0 +1,294 @@
+package arith
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// The following functions operate on 128-bit integers, given as
+// a pair of high and low uint64s, and on short little-endian
+// slices of uint64 words. Unlike big.Int, they never allocate,
+// which lets callers handle coefficients that are too large for
+// a uint64 but small enough for a few words on the stack.
+
+// Add128 returns x + y and the carry.
+func Add128(xhi, xlo, yhi, ylo uint64) (hi, lo, carry uint64) {
+	lo, carry = bits.Add64(xlo, ylo, 0)
+	hi, carry = bits.Add64(xhi, yhi, carry)
+	return hi, lo, carry
+}
+
+// Sub128 returns x - y and the borrow.
+func Sub128(xhi, xlo, yhi, ylo uint64) (hi, lo, borrow uint64) {
+	lo, borrow = bits.Sub64(xlo, ylo, 0)
+	hi, borrow = bits.Sub64(xhi, yhi, borrow)
+	return hi, lo, borrow
+}
+
+// Mul128 returns the 256-bit product of x and y as little-endian
+// words.
+func Mul128(xhi, xlo, yhi, ylo uint64) (z [4]uint64) {
+	h0, l0 := bits.Mul64(xlo, ylo)
+	h1, l1 := bits.Mul64(xhi, ylo)
+	h2, l2 := bits.Mul64(xlo, yhi)
+	h3, l3 := bits.Mul64(xhi, yhi)
+
+	var c, c2 uint64
+	z[0] = l0
+	z[1], c = bits.Add64(h0, l1, 0)
+	z[1], c2 = bits.Add64(z[1], l2, 0)
+	z[2], c = bits.Add64(h1, h2, c)
+	z[3], _ = bits.Add64(h3, 0, c)
+	z[2], c = bits.Add64(z[2], l3, c2)
+	z[3], _ = bits.Add64(z[3], 0, c)
+	return z
+}
+
+// QuoRem128 returns the quotient and remainder of hi<<64 | lo
+// divided by d.
+func QuoRem128(hi, lo, d uint64) (qhi, qlo, r uint64) {
+	qhi, r = bits.Div64(0, hi, d)
+	qlo, r = bits.Div64(r, lo, d)
+	return qhi, qlo, r
+}
+
+// Cmp128 compares x and y.
+func Cmp128(xhi, xlo, yhi, ylo uint64) int {
+	switch {
+	case xhi > yhi || xhi == yhi && xlo > ylo:
+		return +1
+	case xhi < yhi || xlo < ylo:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// Pow10x128 returns 10**e as a 128-bit integer and a boolean
+// indicating whether the result fits into 128 bits.
+func Pow10x128(e uint64) (hi, lo uint64, ok bool) {
+	if e < PowTabLen {
+		return 0, pow10tab[e], true
+	}
+	if e >= 2*PowTabLen-1 {
+		return 0, 0, false
+	}
+	hi, lo = bits.Mul64(pow10tab[PowTabLen-1], pow10tab[e-(PowTabLen-1)])
+	return hi, lo, true
+}
+
+// Words64 sets buf to the little-endian 64-bit words of |x| and
+// returns the used portion of buf and a boolean indicating
+// whether x fits into len(buf) words.
+func Words64(buf []uint64, x *big.Int) ([]uint64, bool) {
+	xw := x.Bits()
+	if bits.UintSize == 32 {
+		if (len(xw)+1)/2 > len(buf) {
+			return nil, false
+		}
+		z := buf[:(len(xw)+1)/2]
+		for i := range z {
+			z[i] = uint64(xw[2*i])
+			if 2*i+1 < len(xw) {
+				z[i] |= uint64(xw[2*i+1]) << 32
+			}
+		}
+		return z, true
+	}
+	if len(xw) > len(buf) {
+		return nil, false
+	}
+	z := buf[:len(xw)]
+	for i, w := range xw {
+		z[i] = uint64(w)
+	}
+	return z, true
+}
+
+// SetWords64 sets z to the little-endian 64-bit words x and
+// returns z. It only allocates if z does not have enough
+// capacity.
+func SetWords64(z *big.Int, x []uint64) *big.Int {
+	x = normWords(x)
+	if bits.UintSize == 32 {
+		zw := makeWord(z.Bits(), 2*len(x))
+		for i, w := range x {
+			zw[2*i] = big.Word(w)
+			zw[2*i+1] = big.Word(w >> 32)
+		}
+		return z.SetBits(norm(zw))
+	}
+	zw := makeWord(z.Bits(), len(x))
+	for i, w := range x {
+		zw[i] = big.Word(w)
+	}
+	return z.SetBits(zw)
+}
+
+// CmpWords compares x and y, neither of which may have leading
+// zero words.
+func CmpWords(x, y []uint64) int {
+	if len(x) != len(y) {
+		if len(x) < len(y) {
+			return -1
+		}
+		return +1
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		if x[i] != y[i] {
+			if x[i] < y[i] {
+				return -1
+			}
+			return +1
+		}
+	}
+	return 0
+}
+
+// AddWords sets z to x + y and returns z. z must have room for
+// max(len(x), len(y))+1 words and may alias x or y.
+func AddWords(z, x, y []uint64) []uint64 {
+	if len(x) < len(y) {
+		x, y = y, x
+	}
+	z = z[:len(x)+1]
+	var c uint64
+	for i := range x {
+		var w uint64
+		if i < len(y) {
+			w = y[i]
+		}
+		z[i], c = bits.Add64(x[i], w, c)
+	}
+	z[len(x)] = c
+	return normWords(z)
+}
+
+// SubWords sets z to x - y and returns z. x must be greater than
+// or equal to y, z must have room for len(x) words, and z may
+// alias x or y.
+func SubWords(z, x, y []uint64) []uint64 {
+	z = z[:len(x)]
+	var b uint64
+	for i := range x {
+		var w uint64
+		if i < len(y) {
+			w = y[i]
+		}
+		z[i], b = bits.Sub64(x[i], w, b)
+	}
+	if b != 0 {
+		panic("underflow")
+	}
+	return normWords(z)
+}
+
+// MulPow10Words sets z to x * 10**n and returns z. z must not
+// alias x and must have enough capacity for the result.
+func MulPow10Words(z, x []uint64, n uint64) []uint64 {
+	z = append(z[:0], x...)
+	for n > 0 && len(z) > 0 {
+		k := n
+		if k >= PowTabLen {
+			k = PowTabLen - 1
+		}
+		p := pow10tab[k]
+		var c uint64
+		for i, w := range z {
+			h, l := bits.Mul64(w, p)
+			var cc uint64
+			z[i], cc = bits.Add64(l, c, 0)
+			c = h + cc
+		}
+		if c != 0 {
+			z = append(z, c)
+		}
+		n -= k
+	}
+	return z
+}
+
+// QuoRemWords sets q to x / d and returns q and the remainder. d
+// must be non-zero and q must have room for len(x)+1 words. q may
+// not alias x.
+func QuoRemWords(q, x []uint64, d [2]uint64) ([]uint64, [2]uint64) {
+	q = q[:len(x)+1]
+	if d[1] == 0 {
+		var r uint64
+		q[len(x)] = 0
+		for i := len(x) - 1; i >= 0; i-- {
+			q[i], r = bits.Div64(r, x[i], d[0])
+		}
+		return normWords(q), [2]uint64{r, 0}
+	}
+
+	// Normalize d so that its most significant bit is set and
+	// compute each word of the quotient by dividing three words
+	// of the shifted dividend by d. See Knuth, TAOCP vol. 2,
+	// section 4.3.1, algorithm D.
+	s := uint(bits.LeadingZeros64(d[1]))
+	d1 := d[1]<<s | d[0]>>(64-s)
+	d0 := d[0] << s
+
+	var r1, r0 uint64
+	for i := len(x); i >= 0; i-- {
+		var u uint64
+		if i < len(x) {
+			u = x[i] << s
+		}
+		if i > 0 && s != 0 {
+			u |= x[i-1] >> (64 - s)
+		}
+		q[i], r1, r0 = div3by2(r1, r0, u, d1, d0)
+	}
+	if s != 0 {
+		r0 = r0>>s | r1<<(64-s)
+		r1 >>= s
+	}
+	return normWords(q), [2]uint64{r0, r1}
+}
+
+// div3by2 returns the quotient and remainder of u2:u1:u0 divided
+// by d1:d0, where the most significant bit of d1 is set and
+// u2:u1 < d1:d0.
+func div3by2(u2, u1, u0, d1, d0 uint64) (q, r1, r0 uint64) {
+	// Estimate q from the leading words and then correct it using
+	// d0. Since the divisor has only two words the corrected
+	// estimate is exact.
+	var rhat uint64
+	over := false
+	if u2 >= d1 {
+		q = ^uint64(0)
+		var c uint64
+		rhat, c = bits.Add64(u1, d1, 0)
+		over = c != 0
+	} else {
+		q, rhat = bits.Div64(u2, u1, d1)
+	}
+	for !over {
+		ph, pl := bits.Mul64(q, d0)
+		if ph < rhat || ph == rhat && pl <= u0 {
+			break
+		}
+		q--
+		var c uint64
+		rhat, c = bits.Add64(rhat, d1, 0)
+		over = c != 0
+	}
+
+	h0, l0 := bits.Mul64(q, d0)
+	_, l1 := bits.Mul64(q, d1)
+	p1, _ := bits.Add64(l1, h0, 0)
+	var b uint64
+	r0, b = bits.Sub64(u0, l0, 0)
+	r1, _ = bits.Sub64(u1, p1, b)
+	return q, r1, r0
+}
+
+// normWords returns x without its leading zero words.
+func normWords(x []uint64) []uint64 {
+	for len(x) > 0 && x[len(x)-1] == 0 {
+		x = x[:len(x)-1]
+	}
+	return x
+}
@@ -0,0 +1,93 @@
+package arith
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// randWords returns a random integer with at most n 64-bit words.
+func randWords(r *rand.Rand, n int) *big.Int {
+	x := new(big.Int).Lsh(big.NewInt(1), uint(64*n))
+	x.Rand(r, x)
+	return x.Rsh(x, uint(r.Intn(64*n)))
+}
+
+func words(x *big.Int) []uint64 {
+	z, ok := Words64(make([]uint64, 8), x)
+	if !ok {
+		panic("too many words")
+	}
+	return z
+}
+
+func TestUint128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		x, y := randWords(r, 2), randWords(r, 2)
+		xw, yw := words(x), words(y)
+		xb, yb := [2]uint64{}, [2]uint64{}
+		copy(xb[:], xw)
+		copy(yb[:], yw)
+
+		p := Mul128(xb[1], xb[0], yb[1], yb[0])
+		want := new(big.Int).Mul(x, y)
+		if got := SetWords64(new(big.Int), p[:]); got.Cmp(want) != 0 {
+			t.Fatalf("#%d: %s × %s: wanted %s, got %s", i, x, y, want, got)
+		}
+
+		if got, want := Cmp128(xb[1], xb[0], yb[1], yb[0]), x.Cmp(y); got != want {
+			t.Fatalf("#%d: Cmp128(%s, %s): wanted %d, got %d", i, x, y, want, got)
+		}
+		if got, want := CmpWords(xw, yw), x.Cmp(y); got != want {
+			t.Fatalf("#%d: CmpWords(%s, %s): wanted %d, got %d", i, x, y, want, got)
+		}
+
+		sum := AddWords(make([]uint64, 3), xw, yw)
+		want.Add(x, y)
+		if got := SetWords64(new(big.Int), sum); got.Cmp(want) != 0 {
+			t.Fatalf("#%d: %s + %s: wanted %s, got %s", i, x, y, want, got)
+		}
+		if x.Cmp(y) >= 0 {
+			diff := SubWords(make([]uint64, 2), xw, yw)
+			want.Sub(x, y)
+			if got := SetWords64(new(big.Int), diff); got.Cmp(want) != 0 {
+				t.Fatalf("#%d: %s - %s: wanted %s, got %s", i, x, y, want, got)
+			}
+		}
+
+		// Divide products, which are up to four words, by
+		// divisors of one and two words.
+		if y.Sign() == 0 {
+			continue
+		}
+		n := new(big.Int).Mul(x, randWords(r, 2))
+		q, rem := QuoRemWords(make([]uint64, 5), words(n), yb)
+		wq, wr := new(big.Int).QuoRem(n, y, new(big.Int))
+		if got := SetWords64(new(big.Int), q); got.Cmp(wq) != 0 {
+			t.Fatalf("#%d: %s / %s: wanted %s, got %s", i, n, y, wq, got)
+		}
+		if got := SetWords64(new(big.Int), rem[:]); got.Cmp(wr) != 0 {
+			t.Fatalf("#%d: %s %% %s: wanted %s, got %s", i, n, y, wr, got)
+		}
+	}
+}
+
+func TestPow10x128(t *testing.T) {
+	for e := uint64(0); e < 45; e++ {
+		want := BigPow10(e)
+		hi, lo, ok := Pow10x128(e)
+		if ok != (want.BitLen() <= 128) {
+			t.Fatalf("10**%d: wanted %t, got %t", e, !ok, ok)
+		}
+		if ok {
+			if got := Set(new(big.Int), hi, lo); got.Cmp(want) != 0 {
+				t.Fatalf("10**%d: wanted %s, got %s", e, want, got)
+			}
+			m := MulPow10Words(make([]uint64, 0, 3), []uint64{7}, e)
+			if got := SetWords64(new(big.Int), m); got.Cmp(new(big.Int).Mul(want, big.NewInt(7))) != 0 {
+				t.Fatalf("7 × 10**%d: wrong result %s", e, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,593 @@
+package decimal
+
+// An Interval is a closed interval [Lo, Hi] of decimals.
+//
+// Interval arithmetic is built on directed rounding: each
+// operation rounds its lower bound toward negative infinity and
+// its upper bound toward positive infinity. If the exact operands
+// lie inside x and y, the exact result therefore lies inside z, no
+// matter how many roundings occurred along the way.
+//
+// Operations allocate z's bounds if they're nil. The bounds of
+// operands must not be nil.
+//
+// An Interval with a NaN bound or a lower bound greater than its
+// upper bound is invalid. An operation with an invalid operand sets
+// both of z's bounds to NaN.
+type Interval struct {
+	// Lo and Hi are the lower and upper bounds.
+	Lo, Hi *Big
+
+	// Context determines the precision of the bounds and records
+	// exceptional conditions. Its RoundingMode is ignored.
+	Context Context
+}
+
+// NewInterval returns a new Interval [lo, hi]. The bounds are
+// copied.
+func NewInterval(lo, hi *Big) *Interval {
+	return &Interval{Lo: new(Big).Copy(lo), Hi: new(Big).Copy(hi)}
+}
+
+// Set sets z to x, rounding its bounds outward, and returns z.
+func (z *Interval) Set(x *Interval) *Interval {
+	if z.invalidContext() || z.checkNaNs(x, nil) {
+		return z
+	}
+	down, up := z.bounds()
+	var lo, hi Big
+	down.Set(&lo, x.Lo)
+	up.Set(&hi, x.Hi)
+	return z.set(&lo, &hi)
+}
+
+// SetBig sets z to the smallest interval that contains x and
+// returns z.
+//
+// If x can be represented exactly both bounds are equal to x.
+func (z *Interval) SetBig(x *Big) *Interval {
+	return z.Set(&Interval{Lo: x, Hi: x})
+}
+
+// Add sets z to x + y and returns z.
+func (z *Interval) Add(x, y *Interval) *Interval {
+	if z.invalidContext() || z.checkNaNs(x, y) {
+		return z
+	}
+	down, up := z.bounds()
+	var lo, hi Big
+	down.Add(&lo, x.Lo, y.Lo)
+	up.Add(&hi, x.Hi, y.Hi)
+	return z.set(&lo, &hi)
+}
+
+// Sub sets z to x - y and returns z.
+func (z *Interval) Sub(x, y *Interval) *Interval {
+	if z.invalidContext() || z.checkNaNs(x, y) {
+		return z
+	}
+	down, up := z.bounds()
+	var lo, hi Big
+	down.Sub(&lo, x.Lo, y.Hi)
+	up.Sub(&hi, x.Hi, y.Lo)
+	return z.set(&lo, &hi)
+}
+
+// Mul sets z to x * y and returns z.
+//
+// Zero times an infinite bound is taken to be zero.
+func (z *Interval) Mul(x, y *Interval) *Interval {
+	if z.invalidContext() || z.checkNaNs(x, y) {
+		return z
+	}
+	down, up := z.bounds()
+	var h hull
+	for _, a := range [...]*Big{x.Lo, x.Hi} {
+		for _, b := range [...]*Big{y.Lo, y.Hi} {
+			var lo, hi Big
+			if a.Sign() == 0 || b.Sign() == 0 {
+				lo.SetUint64(0)
+				hi.SetUint64(0)
+			} else {
+				down.Mul(&lo, a, b)
+				up.Mul(&hi, a, b)
+			}
+			h.add(&lo, &hi)
+		}
+	}
+	return z.set(&h.lo, &h.hi)
+}
+
+// Quo sets z to x / y and returns z.
+//
+// If y contains zero, z is set to [-Inf, +Inf] and DivisionByZero
+// is raised. If y is [0, 0], z is set to NaN and InvalidOperation
+// is raised. The quotient of two infinite bounds may be any number
+// with their combined sign, so, for example, [Inf, Inf] / [Inf, Inf]
+// is [0, +Inf].
+func (z *Interval) Quo(x, y *Interval) *Interval {
+	if z.invalidContext() || z.checkNaNs(x, y) {
+		return z
+	}
+
+	if y.Lo.Sign() <= 0 && y.Hi.Sign() >= 0 {
+		if y.Lo.Sign() == 0 && y.Hi.Sign() == 0 {
+			return z.setNaN(intervaldomain)
+		}
+		z.Context.Conditions |= DivisionByZero
+		var lo, hi Big
+		return z.set(lo.SetInf(true), hi.SetInf(false))
+	}
+
+	down, up := z.bounds()
+	var h hull
+	for _, a := range [...]*Big{x.Lo, x.Hi} {
+		for _, b := range [...]*Big{y.Lo, y.Hi} {
+			var lo, hi Big
+			if a.IsInf(0) && b.IsInf(0) {
+				// ±Inf / ±Inf may be any number with the
+				// quotient's sign, including zero.
+				neg := a.Signbit() != b.Signbit()
+				lo.SetInf(true)
+				hi.SetInf(false)
+				if neg {
+					hi.SetUint64(0)
+				} else {
+					lo.SetUint64(0)
+				}
+				h.add(&lo, &hi)
+				continue
+			}
+			down.Quo(&lo, a, b)
+			up.Quo(&hi, a, b)
+			h.add(&lo, &hi)
+		}
+	}
+	return z.set(&h.lo, &h.hi)
+}
+
+// Sqrt sets z to the square root of x and returns z.
+//
+// The negative part of x, if any, is ignored. If x is entirely
+// negative, z is set to NaN and InvalidOperation is raised.
+func (z *Interval) Sqrt(x *Interval) *Interval {
+	if z.invalidContext() || z.checkNaNs(x, nil) {
+		return z
+	}
+	if x.Hi.Sign() < 0 {
+		return z.setNaN(intervaldomain)
+	}
+
+	down, up := z.bounds()
+	var lo, hi Big
+	if x.Lo.Sign() <= 0 {
+		lo.SetUint64(0)
+	} else {
+		down.sqrtBound(&lo, x.Lo)
+	}
+	up.sqrtBound(&hi, x.Hi)
+	return z.set(&lo, &hi)
+}
+
+// Exp sets z to e**x and returns z.
+func (z *Interval) Exp(x *Interval) *Interval {
+	if z.invalidContext() || z.checkNaNs(x, nil) {
+		return z
+	}
+	var lo, hi Big
+	z.increasing(&lo, &hi, x, Context.Exp, func(x *Big) bool {
+		// e**x is only exact for x = 0 and x = ±Inf.
+		return x.Sign() == 0 || x.IsInf(0)
+	})
+	return z.set(&lo, &hi)
+}
+
+// Log sets z to the natural logarithm of x and returns z.
+//
+// The negative part of x, if any, is ignored. If x contains zero,
+// the lower bound is -Inf. If x is entirely negative, z is set to
+// NaN and InvalidOperation is raised.
+func (z *Interval) Log(x *Interval) *Interval {
+	if z.invalidContext() || z.checkNaNs(x, nil) {
+		return z
+	}
+	if x.Hi.Sign() < 0 {
+		return z.setNaN(intervaldomain)
+	}
+
+	var lo, hi Big
+	if x.Hi.Sign() == 0 {
+		lo.SetInf(true)
+		hi.SetInf(true)
+		return z.set(&lo, &hi)
+	}
+	xlo := x.Lo
+	if xlo.Sign() <= 0 {
+		// Any positive number will do; its bound is replaced below.
+		xlo = x.Hi
+	}
+	z.increasing(&lo, &hi, &Interval{Lo: xlo, Hi: x.Hi}, Context.Log, func(x *Big) bool {
+		// ln x is only exact for x = 1 and x = +Inf.
+		return x.Cmp(one.get()) == 0 || x.IsInf(0)
+	})
+	if x.Lo.Sign() <= 0 {
+		lo.SetInf(true)
+	}
+	return z.set(&lo, &hi)
+}
+
+// Pow sets z to x**y and returns z.
+//
+// If y is a single integer, x may contain negative numbers.
+// Otherwise, x**y is only defined for non-negative x, so the
+// negative part of x is ignored and z is set to NaN if x is
+// entirely negative. Where x**y is discontinuous, as with 0**0, z
+// contains every limit of x**y.
+func (z *Interval) Pow(x, y *Interval) *Interval {
+	if z.invalidContext() || z.checkNaNs(x, y) {
+		return z
+	}
+
+	if y.Lo.Cmp(y.Hi) == 0 && y.Lo.IsInt() {
+		return z.powInt(x, y.Lo)
+	}
+
+	xlo := x.Lo
+	if xlo.Sign() < 0 {
+		if x.Hi.Sign() < 0 {
+			return z.setNaN(intervaldomain)
+		}
+		xlo = new(Big)
+	}
+
+	// For x >= 0, x**y is monotonic in x and in y, so its extrema
+	// lie on the corners.
+	var h hull
+	for _, a := range [...]*Big{xlo, x.Hi} {
+		for _, b := range [...]*Big{y.Lo, y.Hi} {
+			var lo, hi Big
+			switch {
+			case a.Sign() == 0:
+				switch b.Sign() {
+				case +1:
+					lo.SetUint64(0)
+				case -1:
+					lo.SetInf(false)
+				default:
+					// Both 0 and 1 are limits of x**y at (0, 0).
+					h.add(lo.SetUint64(0), hi.SetUint64(0))
+					lo.SetUint64(1)
+				}
+				hi.Copy(&lo)
+			case a.IsFinite() && a.Sign() != 0 && b.IsInf(0):
+				switch a.Cmp(one.get()) {
+				case 0:
+					lo.SetUint64(1)
+				case b.Sign():
+					lo.SetInf(false)
+				default:
+					lo.SetUint64(0)
+				}
+				hi.Copy(&lo)
+			default:
+				z.powBound(&lo, &hi, a, b)
+			}
+			h.add(&lo, &hi)
+		}
+	}
+	return z.set(&h.lo, &h.hi)
+}
+
+// powInt sets z to x**n for the integer n and returns z.
+func (z *Interval) powInt(x *Interval, n *Big) *Interval {
+	if n.Sign() == 0 {
+		var r Big
+		r.SetUint64(1)
+		return z.set(&r, &r)
+	}
+
+	odd := n.Int(nil).Bit(0) == 1
+	var h hull
+	add := func(inf, signbit bool) {
+		var r Big
+		if inf {
+			r.SetInf(signbit)
+		} else {
+			r.SetUint64(0)
+		}
+		h.add(&r, &r)
+	}
+	for i, a := range [...]*Big{x.Lo, x.Hi} {
+		if a.IsInf(0) {
+			add(!n.Signbit(), a.Signbit() && odd)
+			continue
+		}
+		if a.Sign() != 0 {
+			var lo, hi Big
+			z.powBound(&lo, &hi, a, n)
+			h.add(&lo, &hi)
+			continue
+		}
+		// Use the limit from inside the interval since the sign
+		// of a zero bound is meaningless.
+		add(n.Signbit(), i == 1 && odd)
+	}
+	if x.Lo.Sign() < 0 && x.Hi.Sign() > 0 {
+		switch {
+		case !n.Signbit():
+			if !odd {
+				add(false, false)
+			}
+		case odd:
+			add(true, true)
+			add(true, false)
+		default:
+			add(true, false)
+		}
+	}
+	return z.set(&h.lo, &h.hi)
+}
+
+// powBound sets lo and hi to a lower and upper bound of x**y,
+// where x**y is defined.
+func (z *Interval) powBound(lo, hi, x, y *Big) {
+	if y.Cmp(ptFive.get()) == 0 && x.Sign() > 0 {
+		down, up := z.bounds()
+		down.sqrtBound(lo, x)
+		up.sqrtBound(hi, x)
+		return
+	}
+	// Only integer powers are computed with arithmetic that reports
+	// exact results reliably.
+	z.enclose(lo, hi, y.IsInt(), func(ctx Context, t *Big) *Big {
+		return ctx.Pow(t, x, y)
+	})
+}
+
+// increasing sets lo to a lower bound of f(x.Lo) and hi to an upper
+// bound of f(x.Hi), where f is an increasing function. exact reports
+// whether f's result is exact for a particular argument, provided
+// f doesn't raise Inexact.
+func (z *Interval) increasing(lo, hi *Big, x *Interval, f func(Context, *Big, *Big) *Big, exact func(*Big) bool) {
+	var t Big // unused bound
+	z.enclose(lo, &t, exact(x.Lo), func(ctx Context, t *Big) *Big {
+		return f(ctx, t, x.Lo)
+	})
+	z.enclose(&t, hi, exact(x.Hi), func(ctx Context, t *Big) *Big {
+		return f(ctx, t, x.Hi)
+	})
+}
+
+// enclose sets lo and hi to a lower and upper bound of the result
+// of f, which computes its result in t using ctx.
+//
+// f must be accurate to within a few units in the last place of
+// ctx. If exact is true, f's result is also trusted to be exact
+// unless it raises Inexact.
+func (z *Interval) enclose(lo, hi *Big, exact bool, f func(ctx Context, t *Big) *Big) {
+	down, up := z.bounds()
+	prec := z.Context.precision()
+	ctx := z.Context
+	ctx.Precision = prec + 5
+	ctx.RoundingMode = ToNearestEven
+	ctx.MaxScale = 0
+	ctx.MinScale = 0
+
+	var t Big
+	f(ctx, &t)
+	lo.Context.Conditions |= t.Context.Conditions
+	hi.Context.Conditions |= t.Context.Conditions
+	if exact && t.Context.Conditions&Inexact == 0 {
+		down.Set(lo, &t)
+		up.Set(hi, &t)
+		return
+	}
+
+	// Widening t by one unit in its (prec+2)th digit is more than
+	// enough to cover f's error.
+	switch {
+	case t.IsInf(+1):
+		down.NextMinus(lo, &t)
+		hi.SetInf(false)
+	case t.IsInf(-1):
+		lo.SetInf(true)
+		up.NextPlus(hi, &t)
+	case t.Sign() == 0:
+		down.NextMinus(lo, &t)
+		up.NextPlus(hi, &t)
+	default:
+		var ulp Big
+		ulp.SetMantScale(1, prec+1-t.adjusted())
+		down.Sub(lo, &t, &ulp)
+		up.Add(hi, &t, &ulp)
+	}
+	lo.Context.Conditions |= Inexact | Rounded
+	hi.Context.Conditions |= Inexact | Rounded
+}
+
+// sqrtBound sets z to the square root of x, which must be positive,
+// rounded in the direction of c.RoundingMode, and returns z.
+//
+// Sqrt computes its result at a higher precision and then rounds
+// it, so it might be off by one unit in the last place in the
+// wrong direction. sqrtBound corrects the result by squaring it.
+func (c Context) sqrtBound(z, x *Big) *Big {
+	c.Sqrt(z, x)
+	if !z.IsFinite() {
+		return z
+	}
+
+	up := c.RoundingMode == ToPositiveInf
+	ex := c.exact()
+	cond := z.Context.Conditions
+	for {
+		var sq Big
+		r := ex.Mul(&sq, z, z).Cmp(x)
+		if r == 0 || (r > 0) == up {
+			break
+		}
+		if up {
+			c.NextPlus(z, z)
+		} else {
+			c.NextMinus(z, z)
+		}
+		cond |= Inexact | Rounded
+	}
+	z.Context.Conditions = cond
+	return z
+}
+
+// Contains reports whether y lies inside x.
+//
+// Contains returns false if x is invalid or y is NaN.
+func (x *Interval) Contains(y *Big) bool {
+	return x.valid() && !y.IsNaN(0) &&
+		x.Lo.Cmp(y) <= 0 && y.Cmp(x.Hi) <= 0
+}
+
+// Encloses reports whether y lies entirely inside x.
+//
+// Encloses returns false if x or y is invalid.
+func (x *Interval) Encloses(y *Interval) bool {
+	return x.valid() && y.valid() &&
+		x.Lo.Cmp(y.Lo) <= 0 && y.Hi.Cmp(x.Hi) <= 0
+}
+
+// Intersect sets z to the intersection of x and y, rounding its
+// bounds outward, and returns z.
+//
+// If x and y are disjoint or either is invalid, z is left unchanged
+// and Intersect returns false.
+func (z *Interval) Intersect(x, y *Interval) (*Interval, bool) {
+	if !x.valid() || !y.valid() {
+		return z, false
+	}
+	lo, hi := x.Lo, x.Hi
+	if y.Lo.Cmp(lo) > 0 {
+		lo = y.Lo
+	}
+	if y.Hi.Cmp(hi) < 0 {
+		hi = y.Hi
+	}
+	if lo.Cmp(hi) > 0 {
+		return z, false
+	}
+	return z.Set(&Interval{Lo: lo, Hi: hi}), true
+}
+
+// Width sets z to x.Hi - x.Lo, rounded toward positive infinity
+// using x's Context, and returns z.
+//
+// If x is invalid, z is set to NaN.
+func (x *Interval) Width(z *Big) *Big {
+	if !x.valid() {
+		if !z.checkNaNs(x.Lo, x.Hi, interval) {
+			z.setNaN(InvalidOperation, qnan, intervalorder)
+		}
+		return z
+	}
+	c := x.Context
+	c.RoundingMode = ToPositiveInf
+	return c.Sub(z, x.Hi, x.Lo)
+}
+
+// String returns x as "[lo, hi]".
+func (x *Interval) String() string {
+	return "[" + x.Lo.String() + ", " + x.Hi.String() + "]"
+}
+
+// valid reports whether x's bounds are numbers and x.Lo <= x.Hi.
+func (x *Interval) valid() bool {
+	return !x.Lo.IsNaN(0) && !x.Hi.IsNaN(0) && x.Lo.Cmp(x.Hi) <= 0
+}
+
+// bounds returns the Contexts used to compute the lower and upper
+// bounds of z.
+func (z *Interval) bounds() (down, up Context) {
+	down, up = z.Context, z.Context
+	down.RoundingMode = ToNegativeInf
+	up.RoundingMode = ToPositiveInf
+	return down, up
+}
+
+// invalidContext reports whether z's Context is invalid, in which
+// case it sets z to NaN.
+func (z *Interval) invalidContext() bool {
+	var t Big
+	if !t.invalidContext(z.Context) {
+		return false
+	}
+	z.set(&t, &t)
+	return true
+}
+
+// checkNaNs reports whether x or y, which may be nil, is invalid, in
+// which case it sets z to NaN.
+func (z *Interval) checkNaNs(x, y *Interval) bool {
+	for _, v := range [...]*Interval{x, y} {
+		if v == nil {
+			continue
+		}
+		var t Big
+		if t.checkNaNs(v.Lo, v.Hi, interval) {
+			z.set(&t, &t)
+			return true
+		}
+		if v.Lo.Cmp(v.Hi) > 0 {
+			z.setNaN(intervalorder)
+			return true
+		}
+	}
+	return false
+}
+
+// setNaN sets z's bounds to quiet NaN with the payload p, raises
+// InvalidOperation, and returns z.
+func (z *Interval) setNaN(p Payload) *Interval {
+	var t Big
+	t.setNaN(InvalidOperation, qnan, p)
+	return z.set(&t, &t)
+}
+
+// set sets z to [lo, hi], records their conditions, and returns z.
+//
+// If either bound is NaN, both of z's bounds are set to NaN.
+func (z *Interval) set(lo, hi *Big) *Interval {
+	z.Context.Conditions |= lo.Context.Conditions | hi.Context.Conditions
+	if hi.IsNaN(0) {
+		lo = hi
+	} else if lo.IsNaN(0) {
+		hi = lo
+	}
+	if z.Lo == nil {
+		z.Lo = new(Big)
+	}
+	if z.Hi == nil {
+		z.Hi = new(Big)
+	}
+	z.Lo.Copy(lo)
+	z.Hi.Copy(hi)
+	if lo.IsNaN(0) && z.Context.OperatingMode == Go {
+		panic(ErrNaN{Msg: z.Context.Conditions.String()})
+	}
+	return z
+}
+
+// hull is the smallest interval that contains a set of bounds.
+type hull struct {
+	lo, hi Big
+	ok     bool
+}
+
+// add widens h to contain [lo, hi].
+func (h *hull) add(lo, hi *Big) {
+	h.lo.Context.Conditions |= lo.Context.Conditions
+	h.hi.Context.Conditions |= hi.Context.Conditions
+	if !h.ok || lo.Cmp(&h.lo) < 0 {
+		h.lo.Copy(lo)
+	}
+	if !h.ok || hi.Cmp(&h.hi) > 0 {
+		h.hi.Copy(hi)
+	}
+	h.ok = true
+}
@@ -0,0 +1,177 @@
+package decimal
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func newInterval(lo, hi string) *Interval {
+	x := decs(lo, hi)
+	return NewInterval(x[0], x[1])
+}
+
+func TestInterval(t *testing.T) {
+	fns := map[string]func(z, x, y *Interval) *Interval{
+		"Add":  (*Interval).Add,
+		"Sub":  (*Interval).Sub,
+		"Mul":  (*Interval).Mul,
+		"Quo":  (*Interval).Quo,
+		"Pow":  (*Interval).Pow,
+		"Sqrt": func(z, x, _ *Interval) *Interval { return z.Sqrt(x) },
+		"Exp":  func(z, x, _ *Interval) *Interval { return z.Exp(x) },
+		"Log":  func(z, x, _ *Interval) *Interval { return z.Log(x) },
+	}
+	for i, tc := range []struct {
+		fn             string
+		x0, x1, y0, y1 string
+		r0, r1         string
+		cond           Condition
+	}{
+		{"Add", "1", "2", "0.1", "0.3", "1.1", "2.3", 0},
+		{"Add", "1", "1", "1E-20", "1E-20", "1.000000000000000", "1.000000000000001", Inexact | Rounded},
+		{"Sub", "1", "2", "0.1", "0.3", "0.7", "1.9", 0},
+		{"Sub", "0", "0", "1E-20", "1E-20", "-1E-20", "-1E-20", 0},
+		{"Mul", "-1", "2", "-3", "0.5", "-6", "3", 0},
+		{"Mul", "0", "2", "-3", "Inf", "-6", "Infinity", 0},
+		{"Mul", "-1", "2", "-3", "Inf", "-Infinity", "Infinity", 0},
+		{"Quo", "1", "1", "3", "3", "0.3333333333333333", "0.3333333333333334", Inexact | Rounded},
+		{"Quo", "-1", "2", "-3", "-0.5", "-4", "2", Inexact | Rounded},
+		{"Quo", "1", "Inf", "2", "4", "0.25", "Infinity", 0},
+		{"Quo", "1", "2", "-1", "1", "-Infinity", "Infinity", DivisionByZero},
+		{"Quo", "1", "2", "0", "0", "NaN", "NaN", InvalidOperation},
+		{"Quo", "Inf", "Inf", "Inf", "Inf", "0", "Infinity", 0},
+		{"Quo", "-Inf", "-Inf", "Inf", "Inf", "-Infinity", "0", 0},
+		{"Sqrt", "2", "4", "", "", "1.414213562373095", "2", Inexact | Rounded},
+		{"Sqrt", "-2", "0.01", "", "", "0", "0.1", 0},
+		{"Sqrt", "-2", "-1", "", "", "NaN", "NaN", InvalidOperation},
+		{"Exp", "1", "1", "", "", "2.718281828459045", "2.718281828459046", Inexact | Rounded},
+		{"Exp", "-Inf", "Inf", "", "", "0", "Infinity", 0},
+		{"Log", "10", "10", "", "", "2.302585092994045", "2.302585092994046", Inexact | Rounded},
+		{"Log", "-1", "1", "", "", "-Infinity", "0", 0},
+		{"Log", "-1", "-0.5", "", "", "NaN", "NaN", InvalidOperation},
+		{"Pow", "2", "3", "0.5", "0.5", "1.414213562373095", "1.732050807568878", Inexact | Rounded},
+		{"Pow", "2", "3", "1.5", "2.5", "2.828427124746190", "15.58845726811990", Inexact | Rounded},
+		{"Pow", "4", "4", "1.5", "1.5", "7.999999999999999", "8.000000000000001", Inexact | Rounded},
+		{"Pow", "-2", "3", "2", "2", "0", "9", 0},
+		{"Pow", "-2", "3", "3", "3", "-8", "27", 0},
+		{"Pow", "-2", "0", "-1", "-1", "-Infinity", "-0.5", 0},
+		{"Pow", "-Inf", "-1", "3", "3", "-Infinity", "-1", 0},
+		{"Pow", "-2", "3", "0", "0", "1", "1", 0},
+		{"Pow", "0", "2", "-1", "1", "0", "Infinity", 0},
+		{"Pow", "0", "2", "0", "1", "0", "2", 0},
+		{"Pow", "0.5", "2", "1", "Inf", "0", "Infinity", 0},
+		{"Pow", "-3", "-2", "0.5", "1.5", "NaN", "NaN", InvalidOperation},
+		{"Add", "2", "1", "0", "0", "NaN", "NaN", InvalidOperation},
+		{"Add", "NaN", "1", "0", "0", "NaN", "NaN", 0},
+	} {
+		x := newInterval(tc.x0, tc.x1)
+		var y *Interval
+		if tc.y0 != "" {
+			y = newInterval(tc.y0, tc.y1)
+		}
+		z := &Interval{Context: Context{Precision: 16}}
+		fns[tc.fn](z, x, y)
+		if !strEq(z.Lo, tc.r0) || !strEq(z.Hi, tc.r1) || z.Context.Conditions != tc.cond {
+			t.Fatalf("#%d: %s(%s, %v): wanted ([%s, %s], %s), got (%s, %s)",
+				i, tc.fn, x, y, tc.r0, tc.r1, tc.cond, z, z.Context.Conditions)
+		}
+	}
+}
+
+// TestInterval_Enclosure checks that intervals computed with low
+// precision contain results computed with high precision.
+func TestInterval_Enclosure(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	rnd := func() *Big {
+		return New(r.Int63n(2e9)-1e9, r.Intn(12))
+	}
+
+	exact := Context{Precision: 50}
+	for i := 0; i < 500; i++ {
+		a, b, c, d := rnd(), rnd(), rnd(), rnd()
+		if a.Cmp(b) > 0 {
+			a, b = b, a
+		}
+		if c.Cmp(d) > 0 {
+			c, d = d, c
+		}
+		x := NewInterval(a, b)
+		y := NewInterval(c, d)
+
+		// Check each operation at the endpoints and at a point
+		// inside the operands.
+		xs := []*Big{a, b, exact.Quo(new(Big), exact.Add(new(Big), a, b), two.get())}
+		ys := []*Big{c, d, exact.Quo(new(Big), exact.Add(new(Big), c, d), two.get())}
+		for _, tc := range []struct {
+			name string
+			iv   func(z *Interval) *Interval
+			fn   func(z, x, y *Big) *Big
+		}{
+			{"Add", func(z *Interval) *Interval { return z.Add(x, y) }, exact.Add},
+			{"Sub", func(z *Interval) *Interval { return z.Sub(x, y) }, exact.Sub},
+			{"Mul", func(z *Interval) *Interval { return z.Mul(x, y) }, exact.Mul},
+			{"Quo", func(z *Interval) *Interval { return z.Quo(x, y) }, exact.Quo},
+			{"Sqrt", func(z *Interval) *Interval { return z.Sqrt(x) },
+				func(z, x, _ *Big) *Big { return exact.Sqrt(z, x) }},
+			{"Exp", func(z *Interval) *Interval {
+				return z.Exp(new(Interval).Quo(x, newInterval("1E+8", "1E+8")))
+			}, func(z, x, _ *Big) *Big {
+				return exact.Exp(z, exact.Quo(z, x, New(1, -8)))
+			}},
+			{"Log", func(z *Interval) *Interval { return z.Log(x) },
+				func(z, x, _ *Big) *Big { return exact.Log(z, x) }},
+			{"Pow", func(z *Interval) *Interval {
+				return z.Pow(x, new(Interval).Quo(y, newInterval("1E+9", "1E+9")))
+			}, func(z, x, y *Big) *Big {
+				return exact.Pow(z, x, exact.Quo(z, y, New(1, -9)))
+			}},
+		} {
+			z := tc.iv(&Interval{Context: Context{Precision: 7}})
+			if z.Lo.IsNaN(0) {
+				continue
+			}
+			for _, xv := range xs {
+				for _, yv := range ys {
+					want := tc.fn(new(Big), xv, yv)
+					if want.IsNaN(0) {
+						continue
+					}
+					if !z.Contains(want) {
+						t.Fatalf("#%d: %s(%s, %s): %s does not contain %s",
+							i, tc.name, x, y, z, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestInterval_Queries(t *testing.T) {
+	x := newInterval("1", "2")
+	if !x.Contains(New(15, 1)) || x.Contains(New(21, 1)) || x.Contains(new(Big).SetNaN(false)) {
+		t.Fatal("Contains: wrong result")
+	}
+	if !x.Encloses(newInterval("1.2", "2")) || x.Encloses(newInterval("1.2", "2.1")) {
+		t.Fatal("Encloses: wrong result")
+	}
+
+	z, ok := new(Interval).Intersect(x, newInterval("1.5", "3"))
+	if !ok || !strEq(z.Lo, "1.5") || !strEq(z.Hi, "2") {
+		t.Fatalf("Intersect: wanted ([1.5, 2], true), got (%s, %t)", z, ok)
+	}
+	if _, ok := z.Intersect(x, newInterval("2.5", "3")); ok {
+		t.Fatal("Intersect: expected disjoint intervals")
+	}
+
+	x = &Interval{Context: Context{Precision: 3}}
+	x.SetBig(New(12345, 4))
+	if !strEq(x.Lo, "1.23") || !strEq(x.Hi, "1.24") {
+		t.Fatalf("SetBig: wanted [1.23, 1.24], got %s", x)
+	}
+	if w := x.Width(new(Big)); !strEq(w, "0.01") {
+		t.Fatalf("Width: wanted 0.01, got %s", w)
+	}
+	if w := newInterval("2", "1").Width(new(Big)); !w.IsNaN(0) {
+		t.Fatalf("Width: wanted NaN, got %s", w)
+	}
+}
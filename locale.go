@@ -0,0 +1,241 @@
+package decimal
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Locale describes how a locale writes numbers. It is used by
+// FormatLocale and SetStringLocale.
+//
+// The zero value writes numbers like %f does: "." as the decimal
+// mark, "-" as the minus sign, and no grouping.
+type Locale struct {
+	// Decimal is the decimal mark. If empty, it defaults to ".".
+	Decimal string
+
+	// Group separates groups of digits in the integral part of
+	// the number. If empty, digits are not grouped.
+	Group string
+
+	// Grouping contains the size of each group of digits, starting
+	// from the decimal mark. The last size is used for the
+	// remaining digits unless it's less than or equal to zero, in
+	// which case the remaining digits are not grouped. For example,
+	// most locales use []int{3} and India uses []int{3, 2}, which
+	// produce "1,234,567" and "12,34,567", respectively.
+	Grouping []int
+
+	// Minus is the prefix for negative numbers. If empty, it
+	// defaults to "-".
+	Minus string
+
+	// Plus is the prefix for positive numbers, and is typically
+	// empty.
+	Plus string
+
+	// Parens causes negative numbers to be enclosed in parentheses
+	// instead of being prefixed with Minus. For example, -1234.5
+	// is written "(1,234.50)". This is common in accounting.
+	Parens bool
+}
+
+func (l *Locale) decimal() string {
+	if l.Decimal == "" {
+		return "."
+	}
+	return l.Decimal
+}
+
+func (l *Locale) minus() string {
+	if l.Minus == "" {
+		return "-"
+	}
+	return l.Minus
+}
+
+// FormatLocale returns x in plain notation with prec digits after
+// the decimal mark, written according to loc.
+//
+// x is rounded using its RoundingMode if needed. If prec < 0, x is
+// written with as many digits after the decimal mark as its scale.
+// The result's sign is the sign of x, so, for example, -0.004
+// formatted with a precision of 2 is written "-0.00".
+//
+// Infinities are written "Infinity" and NaNs are written "NaN",
+// prefixed by the locale's signs.
+func (x *Big) FormatLocale(loc Locale, prec int) string {
+	if x == nil {
+		return "<nil>"
+	}
+	if debug {
+		x.validate()
+	}
+
+	var body []byte
+	if x.IsFinite() {
+		t := getDec(x.Context)
+		t.Copy(x)
+		if prec >= 0 && t.Scale() != prec {
+			ctx := Context{
+				Precision:    UnlimitedPrecision,
+				RoundingMode: x.Context.RoundingMode,
+			}
+			ctx.Quantize(t, prec)
+		}
+		t.Context.OperatingMode = GDA
+		t.form &^= signbit
+
+		f := formatter{prec: t.Precision(), width: noWidth}
+		f.format(t, plain, 0)
+		putDec(t)
+
+		body = loc.group(f.b)
+	} else {
+		body = []byte((x.form &^ signbit).String())
+	}
+
+	var b strings.Builder
+	switch neg := x.Signbit(); {
+	case neg && loc.Parens:
+		b.WriteByte('(')
+		b.Write(body)
+		b.WriteByte(')')
+	case neg:
+		b.WriteString(loc.minus())
+		b.Write(body)
+	default:
+		b.WriteString(loc.Plus)
+		b.Write(body)
+	}
+	return b.String()
+}
+
+// group returns the plain number b, like "1234.56", with its
+// integral digits grouped and its decimal mark replaced.
+func (l *Locale) group(b []byte) []byte {
+	integ, frac := b, []byte(nil)
+	if i := bytes.IndexByte(b, '.'); i >= 0 {
+		integ, frac = b[:i], b[i+1:]
+	}
+
+	// Split integ into groups starting from the right.
+	var groups [][]byte
+	for i, rest := 0, integ; len(rest) > 0; {
+		size := len(rest)
+		if l.Group != "" && len(l.Grouping) > 0 {
+			s := l.Grouping[len(l.Grouping)-1]
+			if i < len(l.Grouping) {
+				s = l.Grouping[i]
+				i++
+			}
+			if s > 0 && s < size {
+				size = s
+			}
+		}
+		groups = append(groups, rest[len(rest)-size:])
+		rest = rest[:len(rest)-size]
+	}
+	var out []byte
+	for i := len(groups) - 1; i >= 0; i-- {
+		out = append(out, groups[i]...)
+		if i > 0 {
+			out = append(out, l.Group...)
+		}
+	}
+
+	if frac != nil {
+		out = append(out, l.decimal()...)
+		out = append(out, frac...)
+	}
+	return out
+}
+
+// SetStringLocale sets z to the value of s, which must be written
+// according to loc, and returns z.
+//
+// s may contain the locale's signs, its decimal mark, and its
+// group separator between any two digits of the integral part. The
+// ASCII signs "+" and "-" and, if loc.Parens is true, negative
+// numbers enclosed in parentheses are accepted as well. s may also
+// be an infinity or NaN; see SetString.
+//
+// Like SetString, if s is not in the correct format, z is set to
+// NaN and the ConversionSyntax condition is set. Unlike SetString,
+// SetStringLocale then returns false.
+func (z *Big) SetStringLocale(s string, loc Locale) (*Big, bool) {
+	neg := false
+	if loc.Parens && len(s) >= 2 && s[0] == '(' && s[len(s)-1] == ')' {
+		neg = true
+		s = s[1 : len(s)-1]
+	} else {
+		switch m := loc.minus(); {
+		case strings.HasPrefix(s, m):
+			neg = true
+			s = s[len(m):]
+		case s != "" && s[0] == '-':
+			neg = true
+			s = s[1:]
+		case loc.Plus != "" && strings.HasPrefix(s, loc.Plus):
+			s = s[len(loc.Plus):]
+		case s != "" && s[0] == '+':
+			s = s[1:]
+		}
+	}
+
+	b := make([]byte, 0, len(s)+1)
+	if neg {
+		b = append(b, '-')
+	}
+	if s != "" && (s[0] < '0' || s[0] > '9') && !strings.HasPrefix(s, loc.decimal()) {
+		// Infinity or NaN. A second sign is a syntax error.
+		if s[0] == '+' || s[0] == '-' {
+			return z.setLocaleSyntax(), false
+		}
+		var x Big
+		if _, ok := x.SetString(s); !ok || x.Context.Conditions&ConversionSyntax != 0 {
+			return z.setLocaleSyntax(), false
+		}
+		return z.SetString(string(append(b, s...)))
+	}
+
+	var (
+		dec    = loc.decimal()
+		digits = false // seen a digit
+		frac   = false // seen the decimal mark
+	)
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] >= '0' && s[i] <= '9':
+			b = append(b, s[i])
+			digits = true
+			i++
+		case !frac && strings.HasPrefix(s[i:], dec):
+			b = append(b, '.')
+			frac = true
+			i += len(dec)
+		case !frac && loc.Group != "" && strings.HasPrefix(s[i:], loc.Group):
+			// Group separators must be between two digits.
+			j := i + len(loc.Group)
+			if i == 0 || s[i-1] < '0' || s[i-1] > '9' ||
+				j >= len(s) || s[j] < '0' || s[j] > '9' {
+				return z.setLocaleSyntax(), false
+			}
+			i = j
+		default:
+			return z.setLocaleSyntax(), false
+		}
+	}
+	if !digits {
+		return z.setLocaleSyntax(), false
+	}
+	return z.SetString(string(b))
+}
+
+// setLocaleSyntax sets z to NaN and sets the ConversionSyntax
+// condition, like scan does for invalid strings.
+func (z *Big) setLocaleSyntax() *Big {
+	z.SetNaN(false)
+	z.Context.Conditions |= ConversionSyntax
+	return z
+}
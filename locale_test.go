@@ -0,0 +1,113 @@
+package decimal
+
+import "testing"
+
+var (
+	localeEN = Locale{Decimal: ".", Group: ",", Grouping: []int{3}}
+	localeDE = Locale{Decimal: ",", Group: ".", Grouping: []int{3}}
+	localeIN = Locale{Decimal: ".", Group: ",", Grouping: []int{3, 2}}
+	localeFR = Locale{Decimal: ",", Group: " ", Grouping: []int{3}, Minus: "−"}
+	localeAC = Locale{Decimal: ".", Group: ",", Grouping: []int{3}, Parens: true}
+)
+
+func TestBig_FormatLocale(t *testing.T) {
+	for i, test := range [...]struct {
+		in   string
+		loc  Locale
+		prec int
+		mode RoundingMode
+		out  string
+	}{
+		{"1234567.891", localeEN, 2, ToNearestEven, "1,234,567.89"},
+		{"1234567.891", localeEN, -1, ToNearestEven, "1,234,567.891"},
+		{"1234567.89", localeDE, 2, ToNearestEven, "1.234.567,89"},
+		{"1234567.8", localeIN, 1, ToNearestEven, "12,34,567.8"},
+		{"123456789", localeIN, 0, ToNearestEven, "12,34,56,789"},
+		{"1234", localeIN, -1, ToNearestEven, "1,234"},
+		{"123", localeIN, -1, ToNearestEven, "123"},
+		{"-9876543.21", localeFR, 2, ToNearestEven, "−9 876 543,21"},
+		{"-1234.5", localeAC, 2, ToNearestEven, "(1,234.50)"},
+		{"1234.5", localeAC, 2, ToNearestEven, "1,234.50"},
+		{"999.995", localeEN, 2, ToNearestEven, "1,000.00"},
+		{"999.995", localeEN, 2, ToZero, "999.99"},
+		{"0.5", localeEN, 0, ToNearestAway, "1"},
+		{"-0.004", Locale{}, 2, ToNearestEven, "-0.00"},
+		{"1E+6", Locale{}, -1, ToNearestEven, "1000000"},
+		{"1E+6", localeEN, 2, ToNearestEven, "1,000,000.00"},
+		{"0.0001", localeEN, -1, ToNearestEven, "0.0001"},
+		{"1234567", Locale{Group: ",", Grouping: []int{3, 0}}, 0, ToNearestEven, "1234,567"},
+		{"1234567", Locale{Grouping: []int{3}}, 0, ToNearestEven, "1234567"},
+		{"42", Locale{Plus: "+"}, 1, ToNearestEven, "+42.0"},
+		{"-Inf", localeAC, 2, ToNearestEven, "(Infinity)"},
+		{"Inf", localeEN, 2, ToNearestEven, "Infinity"},
+		{"NaN", localeEN, 2, ToNearestEven, "NaN"},
+	} {
+		x, _ := new(Big).SetString(test.in)
+		x.Context.RoundingMode = test.mode
+		if got := x.FormatLocale(test.loc, test.prec); got != test.out {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.out, got)
+		}
+		if x.String() != test.in && x.IsFinite() {
+			t.Fatalf("#%d: x was modified: %s", i, x)
+		}
+
+		z, _ := new(Big).SetStringLocale(test.out, test.loc)
+		if z.Context.Conditions&ConversionSyntax != 0 {
+			t.Fatalf("#%d: could not parse %q", i, test.out)
+		}
+		if z.FormatLocale(test.loc, test.prec) != test.out {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.out, z.FormatLocale(test.loc, test.prec))
+		}
+	}
+}
+
+func TestBig_SetStringLocale(t *testing.T) {
+	for i, test := range [...]struct {
+		in  string
+		loc Locale
+		out string // "" means an error
+	}{
+		{"1,234,567.89", localeEN, "1234567.89"},
+		{"1234567.89", localeEN, "1234567.89"},
+		{"-1,234", localeEN, "-1234"},
+		{"+1,234", localeEN, "1234"},
+		{".5", localeEN, "0.5"},
+		{"5.", localeEN, "5"},
+		{"1.234,5", localeDE, "1234.5"},
+		{"−1 234,5", localeFR, "-1234.5"},
+		{"-1234,5", localeFR, "-1234.5"},
+		{"(1,234.50)", localeAC, "-1234.50"},
+		{"-Infinity", localeEN, "-Infinity"},
+		{"(Inf)", localeAC, "-Infinity"},
+		{"NaN", localeEN, "NaN"},
+		{"Infinit", localeEN, ""},
+		{"NaNx", localeEN, ""},
+		{"1,,234", localeEN, ""},
+		{",123", localeEN, ""},
+		{"123,", localeEN, ""},
+		{"1,234.5,6", localeEN, ""},
+		{"1.2.3", localeEN, ""},
+		{"1e5", localeEN, ""},
+		{"", localeEN, ""},
+		{"-", localeEN, ""},
+		{".", localeEN, ""},
+		{"--1", localeEN, ""},
+		{"(1)", localeEN, ""},
+		{"(-1)", localeAC, ""},
+		{"1,234", Locale{}, ""},
+	} {
+		z, ok := new(Big).SetStringLocale(test.in, test.loc)
+		if test.out == "" {
+			if ok || z.Context.Conditions&ConversionSyntax == 0 {
+				t.Fatalf("#%d: %q: expected ConversionSyntax, got %s (%t)", i, test.in, z, ok)
+			}
+			continue
+		}
+		if !ok || z.Context.Conditions&ConversionSyntax != 0 {
+			t.Fatalf("#%d: %q: unexpected ConversionSyntax (%t)", i, test.in, ok)
+		}
+		if got := z.String(); got != test.out {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.out, got)
+		}
+	}
+}
@@ -23,6 +23,21 @@ func Acos(z, x *decimal.Big) *decimal.Big {
 	return z.Context.Acos(z, x)
 }
 
+// Acosh returns the inverse hyperbolic cosine of x.
+//
+// Range:
+//     Input: x >= 1
+//     Output: Acosh(x) >= 0
+//
+// Special cases:
+//     Acosh(NaN)  = NaN
+//     Acosh(+Inf) = +Inf
+//     Acosh(x)    = NaN if x < 1
+//     Acosh(1)    = 0
+func Acosh(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Acosh(z, x)
+}
+
 // Asin returns the arcsine, in radians, of x.
 //
 // Range:
@@ -38,6 +53,20 @@ func Asin(z, x *decimal.Big) *decimal.Big {
 	return z.Context.Asin(z, x)
 }
 
+// Asinh returns the inverse hyperbolic sine of x.
+//
+// Range:
+//     Input: all real numbers
+//     Output: all real numbers
+//
+// Special cases:
+//     Asinh(NaN)  = NaN
+//     Asinh(±Inf) = ±Inf
+//     Asinh(±0)   = ±0
+func Asinh(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Asinh(z, x)
+}
+
 // Atan returns the arctangent, in radians, of x.
 //
 // Range:
@@ -80,6 +109,26 @@ func Atan2(z, y, x *decimal.Big) *decimal.Big {
 	return z.Context.Atan2(z, y, x)
 }
 
+// Atanh returns the inverse hyperbolic tangent of x.
+//
+// Range:
+//     Input: -1 <= x <= 1
+//     Output: all real numbers
+//
+// Special cases:
+//     Atanh(NaN) = NaN
+//     Atanh(x)   = NaN if x < -1 or x > 1
+//     Atanh(±1)  = ±Inf
+//     Atanh(±0)  = ±0
+func Atanh(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Atanh(z, x)
+}
+
+// Beta sets z to the beta function of a and b and returns z.
+func Beta(z, a, b *decimal.Big) *decimal.Big {
+	return z.Context.Beta(z, a, b)
+}
+
 // BinarySplit sets z to the result of the binary splitting formula and returns
 // z. The formula is defined as:
 //
@@ -101,6 +150,17 @@ func BinarySplitDynamic(ctx decimal.Context, A, P, B, Q SplitFunc) *decimal.Big
 	return decimal.BinarySplitDynamic(ctx, A, P, B, Q)
 }
 
+// Binomial sets z to the binomial coefficient of n and k and
+// returns z.
+func Binomial(z, n, k *decimal.Big) *decimal.Big {
+	return z.Context.Binomial(z, n, k)
+}
+
+// Cbrt sets z to the cube root of x and returns z.
+func Cbrt(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Cbrt(z, x)
+}
+
 // Ceil sets z to the least integer value greater than or equal
 // to x and returns z.
 func Ceil(z, x *decimal.Big) *decimal.Big {
@@ -120,27 +180,103 @@ func Cos(z, x *decimal.Big) *decimal.Big {
 	return z.Context.Cos(z, x)
 }
 
+// CosDeg sets z to the cosine of x degrees and returns z.
+func CosDeg(z, x *decimal.Big) *decimal.Big {
+	return z.Context.CosDeg(z, x)
+}
+
+// CosPi sets z to the cosine of pi×x and returns z.
+func CosPi(z, x *decimal.Big) *decimal.Big {
+	return z.Context.CosPi(z, x)
+}
+
+// Cosh returns the hyperbolic cosine of x.
+//
+// Range:
+//     Input: all real numbers
+//     Output: Cosh(x) >= 1
+//
+// Special cases:
+//     Cosh(NaN)  = NaN
+//     Cosh(±Inf) = +Inf
+//     Cosh(±0)   = 1
+func Cosh(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Cosh(z, x)
+}
+
+// Cot sets z to the cotangent, in radians, of x and returns z.
+func Cot(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Cot(z, x)
+}
+
+// Csc sets z to the cosecant, in radians, of x and returns z.
+func Csc(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Csc(z, x)
+}
+
+// Dim sets z to the maximum of x-y and 0 and returns z.
+func Dim(z, x, y *decimal.Big) *decimal.Big {
+	return z.Context.Dim(z, x, y)
+}
+
 // E sets z to the mathematical constant e and returns z.
 func E(z *decimal.Big) *decimal.Big {
 	return z.Context.E(z)
 }
 
+// Erf sets z to the error function of x and returns z.
+func Erf(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Erf(z, x)
+}
+
+// Erfc sets z to the complementary error function of x and
+// returns z.
+func Erfc(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Erfc(z, x)
+}
+
 // Exp sets z to e**x and returns z.
 func Exp(z, x *decimal.Big) *decimal.Big {
 	return z.Context.Exp(z, x)
 }
 
+// Expm1 sets z to e**x - 1 and returns z.
+func Expm1(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Expm1(z, x)
+}
+
+// Factorial sets z to x! and returns z.
+func Factorial(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Factorial(z, x)
+}
+
 // Floor sets z to the greatest integer value less than or equal
 // to x and returns z.
 func Floor(z, x *decimal.Big) *decimal.Big {
 	return z.Context.Floor(z, x)
 }
 
+// Frexp breaks x into a fraction and an integral power of ten,
+// sets z to the fraction, and returns z and the exponent.
+func Frexp(z, x *decimal.Big) (*decimal.Big, int) {
+	return z.Context.Frexp(z, x)
+}
+
+// Gamma sets z to the gamma function of x and returns z.
+func Gamma(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Gamma(z, x)
+}
+
 // Hypot sets z to Sqrt(p*p + q*q) and returns z.
 func Hypot(z, p, q *decimal.Big) *decimal.Big {
 	return z.Context.Hypot(z, p, q)
 }
 
+// Ldexp sets z to frac * 10**exp and returns z.
+func Ldexp(z, frac *decimal.Big, exp int) *decimal.Big {
+	return z.Context.Ldexp(z, frac, exp)
+}
+
 // Lentz sets z to the result of the continued fraction provided
 // by the Generator and returns z.
 //
@@ -188,6 +324,40 @@ func Log10(z, x *decimal.Big) *decimal.Big {
 	return z.Context.Log10(z, x)
 }
 
+// Log1p sets z to the natural logarithm of 1 + x and returns z.
+func Log1p(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Log1p(z, x)
+}
+
+// Log2 sets z to the binary logarithm of x and returns z.
+func Log2(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Log2(z, x)
+}
+
+// LogBase sets z to the base b logarithm of x and returns z.
+func LogBase(z, x, b *decimal.Big) *decimal.Big {
+	return z.Context.LogBase(z, x, b)
+}
+
+// LogGamma sets z to the natural logarithm of the absolute value
+// of the gamma function of x and returns z and the sign of the
+// gamma function of x.
+func LogGamma(z, x *decimal.Big) (*decimal.Big, int) {
+	return z.Context.LogGamma(z, x)
+}
+
+// Mod sets z to the remainder of x / y, where the quotient is
+// truncated toward zero, and returns z.
+func Mod(z, x, y *decimal.Big) *decimal.Big {
+	return z.Context.Mod(z, x, y)
+}
+
+// Modf sets z to the integer part and f to the fractional part of
+// x and returns z and f.
+func Modf(z, x, f *decimal.Big) (*decimal.Big, *decimal.Big) {
+	return z.Context.Modf(z, x, f)
+}
+
 // Pi sets z to the mathematical constant pi and returns z.
 func Pi(z *decimal.Big) *decimal.Big {
 	return z.Context.Pi(z)
@@ -198,6 +368,57 @@ func Pow(z, x, y *decimal.Big) *decimal.Big {
 	return z.Context.Pow(z, x, y)
 }
 
+// Remainder sets z to the IEEE 754 remainder of x / y and
+// returns z.
+//
+// Like math.Remainder, the quotient may have any number of
+// digits; the result is x - n×y, where n is the integer nearest
+// to x/y, and the even one if two are equally near.
+//
+// Special cases:
+//     Remainder(±Inf, y) = NaN
+//     Remainder(NaN, y)  = NaN
+//     Remainder(x, 0)    = NaN
+//     Remainder(x, ±Inf) = x
+//     Remainder(x, NaN)  = NaN
+func Remainder(z, x, y *decimal.Big) *decimal.Big {
+	if !x.IsFinite() || !y.IsFinite() || y.Sign() == 0 {
+		return z.Context.Mod(z, x, y)
+	}
+
+	// Like math.Remainder, reduce |x| modulo 2|y| so that the
+	// quotient is never computed, then subtract |y| at most twice.
+	ctx := z.Context
+	ctx.Precision = decimal.UnlimitedPrecision
+	var r, ax, ay, t decimal.Big
+	ax.CopyAbs(x)
+	ay.CopyAbs(y)
+	if ctx.Mod(&r, &ax, ctx.Add(&t, &ay, &ay)).IsNaN(0) {
+		z.Context.Conditions |= r.Context.Conditions
+		return z.Copy(&r)
+	}
+	half := ctx.Mul(&t, &ay, decimal.New(5, 1))
+	if r.Cmp(half) > 0 {
+		if ctx.Sub(&r, &r, &ay); r.Cmp(half) >= 0 {
+			ctx.Sub(&r, &r, &ay)
+		}
+	}
+	if x.Signbit() {
+		r.CopyNeg(&r)
+	}
+	return z.Context.Set(z, &r)
+}
+
+// Root sets z to the nth root of x and returns z.
+func Root(z, x *decimal.Big, n int) *decimal.Big {
+	return z.Context.Root(z, x, n)
+}
+
+// Sec sets z to the secant, in radians, of x and returns z.
+func Sec(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Sec(z, x)
+}
+
 // Sin returns the sine, in radians, of x.
 //
 // Range:
@@ -211,6 +432,30 @@ func Sin(z, x *decimal.Big) *decimal.Big {
 	return z.Context.Sin(z, x)
 }
 
+// SinDeg sets z to the sine of x degrees and returns z.
+func SinDeg(z, x *decimal.Big) *decimal.Big {
+	return z.Context.SinDeg(z, x)
+}
+
+// SinPi sets z to the sine of pi×x and returns z.
+func SinPi(z, x *decimal.Big) *decimal.Big {
+	return z.Context.SinPi(z, x)
+}
+
+// Sinh returns the hyperbolic sine of x.
+//
+// Range:
+//     Input: all real numbers
+//     Output: all real numbers
+//
+// Special cases:
+//     Sinh(NaN)  = NaN
+//     Sinh(±Inf) = ±Inf
+//     Sinh(±0)   = ±0
+func Sinh(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Sinh(z, x)
+}
+
 // Sqrt sets z to the square root of x and returns z.
 func Sqrt(z, x *decimal.Big) *decimal.Big {
 	return z.Context.Sqrt(z, x)
@@ -219,7 +464,7 @@ func Sqrt(z, x *decimal.Big) *decimal.Big {
 // Tan returns the tangent, in radians, of x.
 //
 // Range:
-//     Input: -pi/2 <= x <= pi/2
+//     Input: all real numbers
 //     Output: all real numbers
 //
 // Special cases:
@@ -229,6 +474,35 @@ func Tan(z, x *decimal.Big) *decimal.Big {
 	return z.Context.Tan(z, x)
 }
 
+// TanDeg sets z to the tangent of x degrees and returns z.
+func TanDeg(z, x *decimal.Big) *decimal.Big {
+	return z.Context.TanDeg(z, x)
+}
+
+// TanPi sets z to the tangent of pi×x and returns z.
+func TanPi(z, x *decimal.Big) *decimal.Big {
+	return z.Context.TanPi(z, x)
+}
+
+// Tanh returns the hyperbolic tangent of x.
+//
+// Range:
+//     Input: all real numbers
+//     Output: -1 <= Tanh(x) <= 1
+//
+// Special cases:
+//     Tanh(NaN)  = NaN
+//     Tanh(±Inf) = ±1
+//     Tanh(±0)   = ±0
+func Tanh(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Tanh(z, x)
+}
+
+// Trunc sets z to the integer part of x and returns z.
+func Trunc(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Trunc(z, x)
+}
+
 // Wallis sets z to the result of the continued fraction provided
 // by the Generator and returns z.
 //
@@ -240,6 +514,11 @@ func Wallis(z *decimal.Big, g Generator) *decimal.Big {
 	return z.Context.Wallis(z, g)
 }
 
+// Zeta sets z to the Riemann zeta function of s and returns z.
+func Zeta(z, s *decimal.Big) *decimal.Big {
+	return z.Context.Zeta(z, s)
+}
+
 type Contexter = decimal.Contexter
 type Generator = decimal.Generator
 type Lentzer = decimal.Lentzer
@@ -14,6 +14,16 @@ const (
 	IsCanonical = decimal.IsCanonical
 )
 
+// And sets z to the digit-wise logical AND of x and y and
+// returns z.
+//
+// Both x and y must be logical operands: finite, non-negative
+// integers with a scale of zero whose digits are all either 0 or
+// 1.
+func And(z, x, y *decimal.Big) *decimal.Big {
+	return z.Context.And(z, x, y)
+}
+
 // Canonical sets z to the canonical form of z.
 //
 // Since Big values are always canonical, it's identical to Copy.
@@ -21,6 +31,15 @@ func Canonical(z, x *decimal.Big) *decimal.Big {
 	return z.Canonical(x)
 }
 
+// CmpSignal sets z to the result of comparing x and y and
+// returns z.
+//
+// Unlike Cmp, if either x or y is a NaN value—quiet or
+// signaling—z is set to NaN and InvalidOperation is raised.
+func CmpSignal(z, x, y *decimal.Big) *decimal.Big {
+	return z.Context.CmpSignal(z, x, y)
+}
+
 // CmpTotal compares x and y in a manner similar to the Big.Cmp,
 // but allows ordering of all abstract representations.
 //
@@ -63,6 +82,17 @@ func CopyNeg(z, x *decimal.Big) *decimal.Big {
 	return z.CopyNeg(x)
 }
 
+// Invert sets z to the digit-wise logical inversion of x and
+// returns z.
+func Invert(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Invert(z, x)
+}
+
+// Logb sets z to the adjusted exponent of x and returns z.
+func Logb(z, x *decimal.Big) *decimal.Big {
+	return z.Context.Logb(z, x)
+}
+
 // Mantissa returns the mantissa of x and reports whether the
 // mantissa fits into a uint64 and x is finite.
 //
@@ -145,13 +175,60 @@ func NextPlus(z, x *decimal.Big) *decimal.Big {
 	return z.Context.NextPlus(z, x)
 }
 
+// NextToward sets z to the representable number closest to x in
+// the direction of y and returns z.
+func NextToward(z, x, y *decimal.Big) *decimal.Big {
+	return z.Context.NextToward(z, x, y)
+}
+
+// Or sets z to the digit-wise logical OR of x and y and returns
+// z.
+func Or(z, x, y *decimal.Big) *decimal.Big {
+	return z.Context.Or(z, x, y)
+}
+
+// RemNear sets z to the remainder x - y*n, where n is the integer
+// nearest to x / y, and returns z.
+func RemNear(z, x, y *decimal.Big) *decimal.Big {
+	return z.Context.RemNear(z, x, y)
+}
+
+// Rotate sets z to x with the digits of its coefficient rotated
+// by y places and returns z.
+func Rotate(z, x, y *decimal.Big) *decimal.Big {
+	return z.Context.Rotate(z, x, y)
+}
+
+// RoundToIntegral is like RoundToInt, but it does not raise the
+// Inexact or Rounded conditions.
+func RoundToIntegral(z *decimal.Big) *decimal.Big {
+	return z.Context.RoundToIntegral(z)
+}
+
 // SameQuantum reports whether x and y have the same exponent
 // (scale).
 func SameQuantum(x, y *decimal.Big) bool {
 	return x.SameQuantum(y)
 }
 
+// Scaleb sets z to x * 10**y and returns z.
+func Scaleb(z, x, y *decimal.Big) *decimal.Big {
+	return z.Context.Scaleb(z, x, y)
+}
+
 // SetSignbit sets z to -z if sign is true, otherwise to +z.
 func SetSignbit(z *decimal.Big, sign bool) *decimal.Big {
 	return z.SetSignbit(sign)
 }
+
+// Shift sets z to x with the digits of its coefficient shifted
+// by y places and returns z.
+func Shift(z, x, y *decimal.Big) *decimal.Big {
+	return z.Context.Shift(z, x, y)
+}
+
+// Xor sets z to the digit-wise logical exclusive OR of x and y
+// and returns z.
+func Xor(z, x, y *decimal.Big) *decimal.Big {
+	return z.Context.Xor(z, x, y)
+}
@@ -0,0 +1,310 @@
+// Package money provides amounts of money in ISO 4217 currencies.
+//
+// An Amount pairs a decimal with a Currency and is always quantized
+// to the currency's minor unit; for example, 1.5 USD is stored as
+// 1.50 USD and 100.5 JPY is rounded to 100 JPY or 101 JPY. Operations
+// that combine amounts return a *MismatchError instead of mixing
+// currencies.
+//
+// Dividing an amount cannot, in general, be done exactly. Instead,
+// Split and Allocate distribute an amount into parts whose sum is
+// exactly the original amount.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// MismatchError is returned when an operation is given amounts in
+// different currencies.
+type MismatchError struct {
+	X, Y Currency
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("money: mismatched currencies: %s and %s", e.X, e.Y)
+}
+
+// Amount is an amount of money in a particular currency. Amounts
+// are immutable.
+type Amount struct {
+	v decimal.Big
+	c Currency
+}
+
+// New returns v in the currency c.
+//
+// v is rounded to c's minor unit with Context.Quantize using v's
+// RoundingMode. The resulting amount uses the same RoundingMode for
+// its own operations. v must be finite.
+func New(v *decimal.Big, c Currency) (*Amount, error) {
+	if !v.IsFinite() {
+		return nil, fmt.Errorf("money: amount must be finite: %s", v)
+	}
+	if c.MinorUnits < 0 {
+		return nil, fmt.Errorf("money: invalid minor units: %d", c.MinorUnits)
+	}
+	a := &Amount{c: c}
+	a.v.Context = decimal.Context{
+		Precision:    decimal.UnlimitedPrecision,
+		RoundingMode: v.Context.RoundingMode,
+	}
+	if err := a.set(a.v.Context.Set(&a.v, v)); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Parse returns the amount s in the currency c. See New and
+// Big.SetString for more information.
+func Parse(s string, c Currency) (*Amount, error) {
+	var v decimal.Big
+	if _, ok := v.SetString(s); !ok || v.Context.Conditions&decimal.ConversionSyntax != 0 {
+		return nil, fmt.Errorf("money: invalid amount: %q", s)
+	}
+	return New(&v, c)
+}
+
+// NewMinor returns units × 10**-c.MinorUnits in the currency c.
+// For example, NewMinor(150, USD) is 1.50 USD.
+func NewMinor(units int64, c Currency) *Amount {
+	a, err := New(decimal.New(units, c.MinorUnits), c)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// set quantizes v, which must be &a.v, to a's minor unit.
+func (a *Amount) set(v *decimal.Big) error {
+	a.v.Context.Quantize(v, a.c.MinorUnits)
+	if !v.IsFinite() {
+		return errors.New("money: amount cannot be quantized")
+	}
+	return nil
+}
+
+// derive returns a new amount with the same currency and context as
+// a whose value is the result of op.
+func (a *Amount) derive(op func(ctx decimal.Context, z *decimal.Big) *decimal.Big) (*Amount, error) {
+	z := &Amount{c: a.c}
+	z.v.Context = a.v.Context
+	if err := z.set(op(z.v.Context, &z.v)); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// check returns a *MismatchError if a and b have different
+// currencies.
+func (a *Amount) check(b *Amount) error {
+	if a.c != b.c {
+		return &MismatchError{X: a.c, Y: b.c}
+	}
+	return nil
+}
+
+// Currency returns a's currency.
+func (a *Amount) Currency() Currency {
+	return a.c
+}
+
+// Decimal returns a copy of a's value.
+func (a *Amount) Decimal() *decimal.Big {
+	return new(decimal.Big).Copy(&a.v)
+}
+
+// MinorUnits returns a in minor units. For example, 1.50 USD is 150.
+func (a *Amount) MinorUnits() *big.Int {
+	var t decimal.Big
+	return t.Copy(&a.v).SetScale(0).Int(nil)
+}
+
+// Abs returns |a|.
+func (a *Amount) Abs() *Amount {
+	z, _ := a.derive(func(ctx decimal.Context, z *decimal.Big) *decimal.Big {
+		return z.CopyAbs(&a.v)
+	})
+	return z
+}
+
+// Add returns a + b.
+func (a *Amount) Add(b *Amount) (*Amount, error) {
+	if err := a.check(b); err != nil {
+		return nil, err
+	}
+	return a.derive(func(ctx decimal.Context, z *decimal.Big) *decimal.Big {
+		return ctx.Add(z, &a.v, &b.v)
+	})
+}
+
+// Cmp compares a and b and returns:
+//
+//   -1 if a <  b
+//    0 if a == b
+//   +1 if a >  b
+//
+func (a *Amount) Cmp(b *Amount) (int, error) {
+	if err := a.check(b); err != nil {
+		return 0, err
+	}
+	return a.v.Cmp(&b.v), nil
+}
+
+// Mul returns a × x rounded to a's minor unit. For example, it can
+// be used to apply a tax rate or an exchange rate to an amount.
+func (a *Amount) Mul(x *decimal.Big) (*Amount, error) {
+	return a.derive(func(ctx decimal.Context, z *decimal.Big) *decimal.Big {
+		return ctx.Mul(z, &a.v, x)
+	})
+}
+
+// Neg returns -a.
+func (a *Amount) Neg() *Amount {
+	z, _ := a.derive(func(ctx decimal.Context, z *decimal.Big) *decimal.Big {
+		return z.CopyNeg(&a.v)
+	})
+	return z
+}
+
+// Sign returns:
+//
+//   -1 if a <  0
+//    0 if a == 0
+//   +1 if a >  0
+//
+func (a *Amount) Sign() int {
+	return a.v.Sign()
+}
+
+// String returns a's currency code followed by its value in plain
+// notation, like "USD 1234.50".
+func (a *Amount) String() string {
+	return fmt.Sprintf("%s %f", a.c, &a.v)
+}
+
+// Sub returns a - b.
+func (a *Amount) Sub(b *Amount) (*Amount, error) {
+	if err := a.check(b); err != nil {
+		return nil, err
+	}
+	return a.derive(func(ctx decimal.Context, z *decimal.Big) *decimal.Big {
+		return ctx.Sub(z, &a.v, &b.v)
+	})
+}
+
+// Split divides a into n parts as equal as possible. The sum of the
+// parts is exactly a.
+//
+// Minor units that cannot be divided evenly are added, one each, to
+// the first parts. For example, 100.00 USD split three ways is
+// 33.34, 33.33, and 33.33 USD.
+func (a *Amount) Split(n int) ([]*Amount, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("money: invalid number of parts: %d", n)
+	}
+	w := make([]*big.Int, n)
+	for i := range w {
+		w[i] = big.NewInt(1)
+	}
+	return a.allocate(w), nil
+}
+
+// Allocate divides a into parts in proportion to ratios using the
+// largest remainder method. The sum of the parts is exactly a.
+//
+// Each part is first given its share of a, rounded toward zero to
+// a minor unit. The remaining minor units are then added, one each,
+// to the parts with the largest remainders, earliest first in the
+// case of ties. For example, 100.00 USD allocated 70:30 is 70.00 and
+// 30.00 USD, and 0.05 USD allocated 1:2 is 0.02 and 0.03 USD.
+//
+// The ratios must be finite, non-negative, and not all zero.
+func (a *Amount) Allocate(ratios ...*decimal.Big) ([]*Amount, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("money: no ratios")
+	}
+
+	// Scale the ratios to integers with a common exponent.
+	exp := 0
+	for i, r := range ratios {
+		if !r.IsFinite() || r.Signbit() && r.Sign() != 0 {
+			return nil, fmt.Errorf("money: invalid ratio: %s", r)
+		}
+		if s := r.Scale(); i == 0 || s > exp {
+			exp = s
+		}
+	}
+	w := make([]*big.Int, len(ratios))
+	sum := new(big.Int)
+	for i, r := range ratios {
+		var t decimal.Big
+		t.Context = decimal.Context{Precision: decimal.UnlimitedPrecision}
+		t.Copy(r)
+		w[i] = t.Quantize(exp).SetScale(0).Int(nil)
+		sum.Add(sum, w[i])
+	}
+	if sum.Sign() == 0 {
+		return nil, errors.New("money: ratios sum to zero")
+	}
+	return a.allocate(w), nil
+}
+
+// allocate implements Allocate for the integer weights w, which are
+// non-negative and have a positive sum.
+func (a *Amount) allocate(w []*big.Int) []*Amount {
+	units := a.MinorUnits()
+	neg := units.Sign() < 0
+	units.Abs(units)
+
+	sum := new(big.Int)
+	for _, x := range w {
+		sum.Add(sum, x)
+	}
+
+	type part struct {
+		i   int
+		q   big.Int // whole minor units
+		rem big.Int // remainder of units × w[i] / sum
+	}
+	parts := make([]part, len(w))
+	left := new(big.Int).Set(units)
+	for i := range parts {
+		p := &parts[i]
+		p.i = i
+		p.q.Mul(units, w[i])
+		p.q.QuoRem(&p.q, sum, &p.rem)
+		left.Sub(left, &p.q)
+	}
+
+	// left < len(parts), so each part receives at most one more
+	// minor unit.
+	order := make([]*part, len(parts))
+	for i := range parts {
+		order[i] = &parts[i]
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].rem.Cmp(&order[j].rem) > 0
+	})
+	for i, n := 0, int(left.Int64()); i < n; i++ {
+		order[i].q.Add(&order[i].q, big.NewInt(1))
+	}
+
+	z := make([]*Amount, len(parts))
+	for i := range parts {
+		q := &parts[i].q
+		if neg {
+			q.Neg(q)
+		}
+		x := &Amount{c: a.c}
+		x.v.Context = a.v.Context
+		x.v.SetBigMantScale(q, a.c.MinorUnits)
+		z[i] = x
+	}
+	return z
+}
@@ -0,0 +1,226 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/ericlagergren/decimal"
+)
+
+var (
+	usd = MustLookup("USD")
+	eur = MustLookup("EUR")
+	jpy = MustLookup("JPY")
+	kwd = MustLookup("KWD")
+)
+
+func dec(s string) *decimal.Big {
+	x, _ := new(decimal.Big).SetString(s)
+	return x
+}
+
+func TestLookup(t *testing.T) {
+	for i, test := range [...]Currency{
+		{"USD", 840, 2}, {"JPY", 392, 0}, {"KWD", 414, 3}, {"CLF", 990, 4},
+	} {
+		c, ok := Lookup(test.Code)
+		if !ok || c != test {
+			t.Fatalf("#%d: wanted %v, got (%v, %t)", i, test, c, ok)
+		}
+	}
+	if _, ok := Lookup("usd"); ok {
+		t.Fatal("lowercase codes should not exist")
+	}
+	seen := make(map[int]bool)
+	for _, c := range iso4217 {
+		if seen[c.Number] {
+			t.Fatalf("duplicate number: %d", c.Number)
+		}
+		seen[c.Number] = true
+	}
+}
+
+func TestNew(t *testing.T) {
+	for i, test := range [...]struct {
+		in   string
+		c    Currency
+		mode decimal.RoundingMode
+		out  string
+	}{
+		{"1.5", usd, decimal.ToNearestEven, "USD 1.50"},
+		{"1.005", usd, decimal.ToNearestEven, "USD 1.00"},
+		{"1.015", usd, decimal.ToNearestEven, "USD 1.02"},
+		{"1.005", usd, decimal.ToNearestAway, "USD 1.01"},
+		{"-1.009", usd, decimal.ToZero, "USD -1.00"},
+		{"100.5", jpy, decimal.ToNearestEven, "JPY 100"},
+		{"1E+3", jpy, decimal.ToNearestEven, "JPY 1000"},
+		{"0.0005", kwd, decimal.ToNearestAway, "KWD 0.001"},
+		{"123456789012345678901234567890.125", eur, decimal.ToNearestEven, "EUR 123456789012345678901234567890.12"},
+	} {
+		x := dec(test.in)
+		x.Context.RoundingMode = test.mode
+		a, err := New(x, test.c)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if got := a.String(); got != test.out {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.out, got)
+		}
+		if x.String() != test.in {
+			t.Fatalf("#%d: input was modified: %s", i, x)
+		}
+	}
+
+	for i, s := range [...]string{"NaN", "-Inf", "abc"} {
+		if _, err := Parse(s, usd); err == nil {
+			t.Fatalf("#%d: expected an error", i)
+		}
+	}
+	if _, err := New(dec("1"), Currency{Code: "XXX", MinorUnits: -1}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestAmount_Arith(t *testing.T) {
+	a, _ := Parse("10.25", usd)
+	b, _ := Parse("0.80", usd)
+
+	for i, test := range [...]struct {
+		fn  func() (*Amount, error)
+		out string
+	}{
+		{func() (*Amount, error) { return a.Add(b) }, "USD 11.05"},
+		{func() (*Amount, error) { return a.Sub(b) }, "USD 9.45"},
+		{func() (*Amount, error) { return b.Sub(a) }, "USD -9.45"},
+		{func() (*Amount, error) { return a.Mul(dec("0.0825")) }, "USD 0.85"},
+		{func() (*Amount, error) { return a.Mul(dec("3")) }, "USD 30.75"},
+		{func() (*Amount, error) { return a.Neg(), nil }, "USD -10.25"},
+		{func() (*Amount, error) { return a.Neg().Abs(), nil }, "USD 10.25"},
+	} {
+		z, err := test.fn()
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if got := z.String(); got != test.out {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.out, got)
+		}
+	}
+	if a.String() != "USD 10.25" || b.String() != "USD 0.80" {
+		t.Fatalf("operands were modified: %s, %s", a, b)
+	}
+
+	if r, err := a.Cmp(b); err != nil || r != 1 {
+		t.Fatalf("wanted (1, nil), got (%d, %v)", r, err)
+	}
+	if a.Sign() != 1 || a.Neg().Sign() != -1 {
+		t.Fatal("bad sign")
+	}
+	if u := a.MinorUnits(); u.Int64() != 1025 {
+		t.Fatalf("wanted 1025, got %s", u)
+	}
+	if m := NewMinor(-150, usd); m.String() != "USD -1.50" {
+		t.Fatalf("wanted USD -1.50, got %s", m)
+	}
+}
+
+func TestAmount_Mismatch(t *testing.T) {
+	a := NewMinor(100, usd)
+	b := NewMinor(100, eur)
+	_, err := a.Add(b)
+	if e, ok := err.(*MismatchError); !ok || e.X != usd || e.Y != eur {
+		t.Fatalf("wanted a *MismatchError, got %v", err)
+	}
+	if _, err := a.Sub(b); err == nil {
+		t.Fatal("Sub: expected an error")
+	}
+	if _, err := a.Cmp(b); err == nil {
+		t.Fatal("Cmp: expected an error")
+	}
+}
+
+func checkParts(t *testing.T, i int, a *Amount, parts []*Amount, want []string) {
+	t.Helper()
+	if len(parts) != len(want) {
+		t.Fatalf("#%d: wanted %d parts, got %d", i, len(want), len(parts))
+	}
+	sum := NewMinor(0, a.Currency())
+	for j, p := range parts {
+		if got := p.String(); got != want[j] {
+			t.Fatalf("#%d: part %d: wanted %q, got %q", i, j, want[j], got)
+		}
+		sum, _ = sum.Add(p)
+	}
+	if r, _ := sum.Cmp(a); r != 0 {
+		t.Fatalf("#%d: parts sum to %s, not %s", i, sum, a)
+	}
+}
+
+func TestAmount_Split(t *testing.T) {
+	for i, test := range [...]struct {
+		in  string
+		c   Currency
+		n   int
+		out []string
+	}{
+		{"100", usd, 3, []string{"USD 33.34", "USD 33.33", "USD 33.33"}},
+		{"-100", usd, 3, []string{"USD -33.34", "USD -33.33", "USD -33.33"}},
+		{"0.05", usd, 3, []string{"USD 0.02", "USD 0.02", "USD 0.01"}},
+		{"0.01", usd, 2, []string{"USD 0.01", "USD 0.00"}},
+		{"1000", jpy, 7, []string{"JPY 143", "JPY 143", "JPY 143", "JPY 143", "JPY 143", "JPY 143", "JPY 142"}},
+		{"5", usd, 1, []string{"USD 5.00"}},
+	} {
+		a, _ := Parse(test.in, test.c)
+		parts, err := a.Split(test.n)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		checkParts(t, i, a, parts, test.out)
+	}
+	if _, err := NewMinor(1, usd).Split(0); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestAmount_Allocate(t *testing.T) {
+	for i, test := range [...]struct {
+		in     string
+		ratios []string
+		out    []string
+	}{
+		{"100", []string{"70", "30"}, []string{"USD 70.00", "USD 30.00"}},
+		{"100", []string{"0.7", "0.3"}, []string{"USD 70.00", "USD 30.00"}},
+		{"0.05", []string{"1", "2"}, []string{"USD 0.02", "USD 0.03"}},
+		{"0.05", []string{"30", "70"}, []string{"USD 0.02", "USD 0.03"}},
+		{"10", []string{"1", "1", "1"}, []string{"USD 3.34", "USD 3.33", "USD 3.33"}},
+		// 10 × (0.2, 0.25, 0.55) / 1 = 2.00, 2.50, 5.50
+		{"10", []string{"0.2", "0.25", "0.55"}, []string{"USD 2.00", "USD 2.50", "USD 5.50"}},
+		// 1 × (1, 1, 1, 97) / 100: remainders 1, 1, 1, 97.
+		{"0.01", []string{"1", "1", "1", "97"}, []string{"USD 0.00", "USD 0.00", "USD 0.00", "USD 0.01"}},
+		{"-1", []string{"1", "2"}, []string{"USD -0.33", "USD -0.67"}},
+		{"1", []string{"0", "1E+2"}, []string{"USD 0.00", "USD 1.00"}},
+		{"100", []string{"1", "-0"}, []string{"USD 100.00", "USD 0.00"}},
+	} {
+		a, _ := Parse(test.in, usd)
+		ratios := make([]*decimal.Big, len(test.ratios))
+		for j, s := range test.ratios {
+			ratios[j] = dec(s)
+		}
+		parts, err := a.Allocate(ratios...)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		checkParts(t, i, a, parts, test.out)
+	}
+
+	a := NewMinor(100, usd)
+	for i, ratios := range [...][]*decimal.Big{
+		nil,
+		{dec("0"), dec("0")},
+		{dec("1"), dec("-1")},
+		{dec("1"), dec("NaN")},
+		{dec("Inf")},
+	} {
+		if _, err := a.Allocate(ratios...); err == nil {
+			t.Fatalf("#%d: expected an error", i)
+		}
+	}
+}
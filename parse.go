@@ -0,0 +1,390 @@
+package decimal
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+
+	"github.com/ericlagergren/decimal/internal/arith"
+)
+
+// SyntaxError records a numeric string that could not be parsed.
+type SyntaxError struct {
+	Num    string    // the input
+	Offset int       // offset of the offending byte in Num
+	Err    Condition // ConversionSyntax or InsufficientStorage
+}
+
+func (e *SyntaxError) Error() string {
+	return "decimal: parsing " + strconv.Quote(e.Num) + ": " +
+		e.Err.Error() + " at offset " + strconv.Itoa(e.Offset)
+}
+
+// Unwrap returns e.Err.
+func (e *SyntaxError) Unwrap() error { return e.Err }
+
+// Parse sets z to the value of the numeric string b and returns
+// nil.
+//
+// Parse accepts the same formats as SetString, except that the
+// coefficient and the exponent, if any, must each have at least
+// one digit. If b is not a valid numeric string, Parse sets z to
+// a quiet NaN, raises the ConversionSyntax condition, and returns
+// a *SyntaxError with the offset of the first invalid byte. If
+// the coefficient has more digits than z's Context allows, the
+// InsufficientStorage condition is used instead.
+//
+// Unlike SetString, Parse does not allocate unless z's
+// coefficient has more than 38 digits.
+//
+// To configure the accepted syntax, use a Parser.
+func (z *Big) Parse(b []byte) error {
+	return Parser{}.Parse(z, b)
+}
+
+// Parser parses numeric strings with a configurable syntax. Its
+// zero value accepts the same syntax as (*Big).Parse.
+//
+// For example, a Parser for untrusted input might allow neither
+// infinities nor NaN values and limit the size of the result:
+//
+// 	p := Parser{Strict: true, NoInf: true, NoNaN: true, MaxDigits: 34, MaxExponent: 6144}
+//
+// while a Parser for hand-edited files might be more forgiving:
+//
+// 	p := Parser{Separator: '_', AllowSpace: true}
+type Parser struct {
+	// Strict, if true, accepts only the numeric strings defined
+	// by the General Decimal Arithmetic specification. See
+	// http://speleotrove.com/decimal/daconvs.html#refnumsyn. For
+	// example, "qNaN" is rejected. Separator and AllowSpace are
+	// ignored.
+	Strict bool
+
+	// Separator, if non-zero, is a digit separator that may
+	// appear between two digits of the coefficient or exponent,
+	// like the underscores in the Go literal 1_000.50. It must not
+	// be a digit, '.', 'e', 'E', '+', or '-'; otherwise, Parse
+	// sets z to a quiet NaN and returns an error.
+	Separator byte
+
+	// AllowSpace, if true, allows leading and trailing ASCII
+	// whitespace.
+	AllowSpace bool
+
+	// NoPlus, if true, rejects a leading '+' sign.
+	NoPlus bool
+
+	// NoInf, if true, rejects infinities.
+	NoInf bool
+
+	// NoNaN, if true, rejects NaN values.
+	NoNaN bool
+
+	// MaxDigits, if positive, limits the number of significant
+	// digits in the coefficient. Longer coefficients are rejected
+	// with ConversionSyntax as soon as the limit is reached, so
+	// adversarial input cannot cause large allocations.
+	MaxDigits int
+
+	// MaxExponent, if positive, limits the magnitude of the
+	// adjusted exponent; that is, the exponent of the number in
+	// scientific notation with one digit before the radix. For
+	// example, the adjusted exponent of 123E+5 is 7. Numbers with
+	// larger adjusted exponents are rejected with
+	// ConversionSyntax.
+	MaxExponent int
+}
+
+// Parse sets z to the value of the numeric string b and returns
+// nil. Errors are reported in the same manner as (*Big).Parse.
+func (p Parser) Parse(z *Big, b []byte) error {
+	if debug {
+		defer func() { z.validate() }()
+	}
+
+	if !p.Strict && !validSeparator(p.Separator) {
+		z.compact = 0
+		z.form = qnan
+		return fmt.Errorf("decimal: invalid Parser.Separator %q", p.Separator)
+	}
+
+	off, err := z.parse(b, &p)
+	if err != 0 {
+		z.compact = 0
+		z.form = qnan
+		z.Context.Conditions |= err
+		return &SyntaxError{Num: string(b), Offset: off, Err: err}
+	}
+	return nil
+}
+
+// isSpace reports whether ch is ASCII whitespace.
+func isSpace(ch byte) bool {
+	switch ch {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
+
+// isDigit reports whether ch is an ASCII digit.
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+// validSeparator reports whether sep can be used as a Parser's
+// Separator.
+func validSeparator(sep byte) bool {
+	switch sep {
+	case '.', 'e', 'E', '+', '-':
+		return false
+	}
+	return !isDigit(sep)
+}
+
+// isSeparator reports whether b[i] is a digit separator allowed by
+// p; that is, whether it is p.Separator and between two digits.
+func (p *Parser) isSeparator(b []byte, i int) bool {
+	return p.Separator != 0 && !p.Strict && b[i] == p.Separator &&
+		i > 0 && isDigit(b[i-1]) && i+1 < len(b) && isDigit(b[i+1])
+}
+
+// parse sets z to the value of the numeric string b using the
+// syntax allowed by p. If b is invalid, it returns the offset of
+// the first invalid byte and the condition to raise.
+//
+// See scan for the grammar.
+func (z *Big) parse(b []byte, p *Parser) (int, Condition) {
+	z.SetUint64(0)
+
+	i := 0
+	if p.AllowSpace && !p.Strict {
+		for i < len(b) && isSpace(b[i]) {
+			i++
+		}
+		for len(b) > i && isSpace(b[len(b)-1]) {
+			b = b[:len(b)-1]
+		}
+	}
+
+	var neg bool
+	if i < len(b) && (b[i] == '+' && !p.NoPlus || b[i] == '-') {
+		neg = b[i] == '-'
+		i++
+	}
+	if i == len(b) {
+		return i, ConversionSyntax
+	}
+	if ch := b[i]; ch != '.' && !isDigit(ch) {
+		if i, err := z.parseSpecial(b, i, p); err != 0 {
+			return i, err
+		}
+		if neg {
+			z.form |= signbit
+		}
+		return 0, 0
+	}
+
+	// Coefficient. Accumulate the first compact128Digits
+	// significant digits into two words, which is all that most
+	// coefficients need.
+	var (
+		start  = i
+		hi, lo uint64
+		nd     int // number of significant digits
+		digits int // number of digits, including leading zeros
+		frac   int // number of digits after the radix
+		dot    = false
+	)
+Loop:
+	for ; i < len(b); i++ {
+		ch := b[i]
+		switch {
+		case isDigit(ch):
+			digits++
+			if dot {
+				frac++
+			}
+			if nd == 0 && ch == '0' {
+				continue
+			}
+			if nd++; p.MaxDigits > 0 && nd > p.MaxDigits {
+				return i, ConversionSyntax
+			}
+			if nd <= compact128Digits {
+				var h, c uint64
+				h, lo = bits.Mul64(lo, 10)
+				lo, c = bits.Add64(lo, uint64(ch-'0'), 0)
+				hi = hi*10 + h + c
+			}
+		case ch == '.':
+			if dot {
+				// Found two dots.
+				return i, ConversionSyntax
+			}
+			dot = true
+		case ch == 'e' || ch == 'E':
+			break Loop
+		case p.isSeparator(b, i):
+			// OK
+		default:
+			return i, ConversionSyntax
+		}
+	}
+	if digits == 0 {
+		return i, ConversionSyntax
+	}
+	end := i
+
+	// Exponent.
+	const maxInt = uint64(arith.MaxInt)
+	var (
+		exp  uint64
+		eneg bool
+	)
+	if i < len(b) {
+		i++ // 'e' or 'E'
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			eneg = b[i] == '-'
+			i++
+		}
+		if i == len(b) {
+			return i, ConversionSyntax
+		}
+		for ; i < len(b); i++ {
+			if p.isSeparator(b, i) {
+				continue
+			}
+			d := b[i] - '0'
+			if d > 9 {
+				return i, ConversionSyntax
+			}
+			// Saturate exp just above maxInt so that it cannot
+			// overflow.
+			if exp > maxInt/10 {
+				exp = maxInt + 1
+			} else {
+				exp = exp*10 + uint64(d)
+			}
+		}
+	}
+
+	if z.Context.tooLarge(int64(nd)) {
+		return start, InsufficientStorage
+	}
+	if nd <= compact128Digits {
+		if hi == 0 {
+			z.SetUint64(lo)
+		} else {
+			w := [2]uint64{lo, hi}
+			z.setWords(w[:])
+		}
+	} else {
+		buf := make([]byte, 0, digits)
+		for _, ch := range b[start:end] {
+			if isDigit(ch) {
+				buf = append(buf, ch)
+			}
+		}
+		z.unscaled.SetString(string(buf), 10)
+		z.norm()
+	}
+
+	// Keep the conditions from xflow only if z is accepted.
+	conds := z.Context.Conditions
+	xflowed := false
+	switch {
+	case eneg && exp+uint64(frac) > maxInt:
+		z.xflow(MinScale, false, neg)
+		xflowed = true
+	case eneg:
+		z.exp = -int(exp + uint64(frac))
+	case exp > maxInt:
+		z.xflow(MinScale, true, neg)
+		xflowed = true
+	default:
+		z.exp = int(exp) - frac
+	}
+	if m := p.MaxExponent; m > 0 {
+		// The adjusted exponent is z.exp + adj. Compare it to m
+		// without overflowing.
+		adj := 0
+		if nd > 0 {
+			adj = nd - 1
+		}
+		if xflowed || z.exp > 0 && z.exp > m-adj || z.exp <= 0 && z.exp+adj < -m {
+			z.Context.Conditions = conds
+			if end < len(b) {
+				return end, ConversionSyntax
+			}
+			return start, ConversionSyntax
+		}
+	}
+	if neg && !xflowed {
+		z.form |= signbit
+	}
+	return 0, 0
+}
+
+// parseSpecial sets z to the infinity or NaN at b[i:] and returns
+// the offset of the first invalid byte and the condition to raise
+// if b[i:] is not one or is not allowed by p.
+func (z *Big) parseSpecial(b []byte, i int, p *Parser) (int, Condition) {
+	switch b[i] | 0x20 {
+	case 'i':
+		if p.NoInf {
+			return i, ConversionSyntax
+		}
+		n := matchFold(b[i:], "infinity")
+		if i += n; i < len(b) || n != len("inf") && n != len("infinity") {
+			return i, ConversionSyntax
+		}
+		z.form = inf
+		return 0, 0
+	case 's':
+		z.form = snan
+		i++
+	case 'q':
+		if p.Strict {
+			return i, ConversionSyntax
+		}
+		z.form = qnan
+		i++
+	default:
+		z.form = qnan
+	}
+	if p.NoNaN {
+		return i, ConversionSyntax
+	}
+	n := matchFold(b[i:], "nan")
+	if i += n; n != len("nan") {
+		return i, ConversionSyntax
+	}
+
+	// Payload.
+	for ; i < len(b); i++ {
+		d := b[i] - '0'
+		if d > 9 {
+			return i, ConversionSyntax
+		}
+		hi, lo := bits.Mul64(z.compact, 10)
+		lo, c := bits.Add64(lo, uint64(d), 0)
+		if hi != 0 || c != 0 {
+			return i, ConversionSyntax
+		}
+		z.compact = lo
+	}
+	return 0, 0
+}
+
+// matchFold returns the length of the longest prefix of b that
+// matches a prefix of s, which must be lowercase ASCII letters,
+// ignoring case.
+func matchFold(b []byte, s string) int {
+	n := 0
+	for n < len(b) && n < len(s) && b[n]|0x20 == s[n] {
+		n++
+	}
+	return n
+}
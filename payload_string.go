@@ -48,11 +48,78 @@ func _() {
 	_ = x[sin-38]
 	_ = x[subinfinf-39]
 	_ = x[subtraction-40]
+	_ = x[logb-41]
+	_ = x[logical-42]
+	_ = x[nexttoward-43]
+	_ = x[rotation-44]
+	_ = x[rotinvalid-45]
+	_ = x[roundtoint-46]
+	_ = x[scaleb-47]
+	_ = x[scalebinvalid-48]
+	_ = x[shifting-49]
+	_ = x[shiftinvalid-50]
+	_ = x[dotlen-51]
+	_ = x[dotproduct-52]
+	_ = x[mean-53]
+	_ = x[meanempty-54]
+	_ = x[summation-55]
+	_ = x[variance-56]
+	_ = x[varinf-57]
+	_ = x[varsize-58]
+	_ = x[acosh-59]
+	_ = x[acoshlt1-60]
+	_ = x[asinh-61]
+	_ = x[atanh-62]
+	_ = x[atanhgt1-63]
+	_ = x[cosh-64]
+	_ = x[sinh-65]
+	_ = x[tanh-66]
+	_ = x[cbrt-67]
+	_ = x[log2-68]
+	_ = x[logbase-69]
+	_ = x[logbaseinvalid-70]
+	_ = x[root-71]
+	_ = x[rootinvalid-72]
+	_ = x[rootneg-73]
+	_ = x[maxNaN-74]
+	_ = x[minNaN-75]
+	_ = x[canceled-76]
+	_ = x[storage-77]
+	_ = x[beta-78]
+	_ = x[betainvalid-79]
+	_ = x[binomial-80]
+	_ = x[binominvalid-81]
+	_ = x[erf-82]
+	_ = x[erfc-83]
+	_ = x[factinvalid-84]
+	_ = x[factorial-85]
+	_ = x[gamma-86]
+	_ = x[gammainvalid-87]
+	_ = x[lgamma-88]
+	_ = x[zeta-89]
+	_ = x[zetaneginf-90]
+	_ = x[dim-91]
+	_ = x[expm1-92]
+	_ = x[frexp-93]
+	_ = x[ldexp-94]
+	_ = x[log1p-95]
+	_ = x[log1pinvalid-96]
+	_ = x[modf-97]
+	_ = x[modfinf-98]
+	_ = x[cot-99]
+	_ = x[csc-100]
+	_ = x[sec-101]
+	_ = x[tan-102]
+	_ = x[triginf-103]
+	_ = x[interval-104]
+	_ = x[intervaldomain-105]
+	_ = x[intervalorder-106]
+	_ = x[logneg-107]
 }
 
-const _Payload_name = "absolute value of NaNacos with NaN as an operandaddition of infinities with opposing signsaddition with NaN as an operandasin with NaN as an operandatan with NaN as an operandatan2 with NaN as an operandcomparison with NaN as an operandcos with NaN as an operanddivision with NaN as an operandexp with NaN as an operandoperation with an invalid OperatingModeoperation with a precision greater than MaxPrecisionoperation with a precision less than zerooperation with an invalid RoundingModeoperation with a scale greater than MaxScaleoperation with a scale lesser than MinScalelog with NaN as an operandlog10 with NaN as an operandmultiplication of zero with infinitymultiplication with NaN as an operandnegation with NaN as an operandnext-minus with NaN as an operandnext-plus with NaN as an operandquantization of an infinityquantization with NaN as an operandquantization exceeds minimum or maximum scalequantization exceeds working precisiondivision of zero by zerodivision of infinity by infinityresult of integer division was larger than the desired precisioninteger division or remainder has too many digitsdivision with unlimited precision has a non-terminating decimal expansionreduction with NaN as an operandremainder of infinityresult of remainder operation was larger than the desired precisionremainder by zerosin with NaN as an operandsubtraction of infinities with opposing signssubtraction with NaN as an operand"
+const _Payload_name = "absolute value of NaNacos with NaN as an operandaddition of infinities with opposing signsaddition with NaN as an operandasin with NaN as an operandatan with NaN as an operandatan2 with NaN as an operandcomparison with NaN as an operandcos with NaN as an operanddivision with NaN as an operandexp with NaN as an operandoperation with an invalid OperatingModeoperation with a precision greater than MaxPrecisionoperation with a precision less than zerooperation with an invalid RoundingModeoperation with a scale greater than MaxScaleoperation with a scale lesser than MinScalelog with NaN as an operandlog10 with NaN as an operandmultiplication of zero with infinitymultiplication with NaN as an operandnegation with NaN as an operandnext-minus with NaN as an operandnext-plus with NaN as an operandquantization of an infinityquantization with NaN as an operandquantization exceeds minimum or maximum scalequantization exceeds working precisiondivision of zero by zerodivision of infinity by infinityresult of integer division was larger than the desired precisioninteger division or remainder has too many digitsdivision with unlimited precision has a non-terminating decimal expansionreduction with NaN as an operandremainder of infinityresult of remainder operation was larger than the desired precisionremainder by zerosin with NaN as an operandsubtraction of infinities with opposing signssubtraction with NaN as an operandlogb with NaN as an operandlogical operation with a non-logical operandnext-toward with NaN as an operandrotation with NaN as an operandrotation by an invalid number of digitsround-to-integral with NaN as an operandscaleb with NaN as an operandscaleb with an invalid scaleshift with NaN as an operandshift by an invalid number of digitsdot product of vectors with different lengthsdot product with NaN as an operandmean with NaN as an operandmean of an empty setsummation with NaN as an operandvariance with NaN as an operandvariance of an infinityvariance of too few valuesacosh with NaN as an operandacosh of a value less than oneasinh with NaN as an operandatanh with NaN as an operandatanh of a value with a magnitude greater than onecosh with NaN as an operandsinh with NaN as an operandtanh with NaN as an operandcbrt with NaN as an operandlog2 with NaN as an operandlogarithm with NaN as an operandlogarithm with an invalid baseroot with NaN as an operandroot with a non-positive degreeeven root of a negative numbermax with NaN as an operandmin with NaN as an operandoperation was canceled by its context.Contextresult or intermediate value exceeds MaxDigits or MaxMemorybeta with NaN as an operandbeta of a non-positive integer or negative infinitybinomial coefficient with NaN as an operandbinomial coefficient of a non-integererf with NaN as an operanderfc with NaN as an operandfactorial of a negative number or non-integerfactorial with NaN as an operandgamma with NaN as an operandgamma of a negative integer or negative infinitylog gamma with NaN as an operandzeta with NaN as an operandzeta of negative infinitydim with NaN as an operandexpm1 with NaN as an operandfrexp with NaN as an operandldexp with NaN as an operandlog1p with NaN as an operandlog1p of a value less than negative onemodf with NaN as an operandfractional part of an infinitycot with NaN as an operandcsc with NaN as an operandsec with NaN as an operandtan with NaN as an operandtrigonometric function of an infinityinterval arithmetic with NaN as an operandinterval entirely outside of a function's domaininterval with a lower bound greater than its upper boundlogarithm of a negative number"
 
-var _Payload_index = [...]uint16{0, 21, 48, 90, 121, 148, 175, 203, 236, 262, 293, 319, 358, 410, 451, 489, 533, 576, 602, 630, 666, 703, 734, 767, 799, 826, 861, 906, 944, 968, 1000, 1064, 1113, 1186, 1218, 1239, 1306, 1323, 1349, 1394, 1428}
+var _Payload_index = [...]uint16{0, 21, 48, 90, 121, 148, 175, 203, 236, 262, 293, 319, 358, 410, 451, 489, 533, 576, 602, 630, 666, 703, 734, 767, 799, 826, 861, 906, 944, 968, 1000, 1064, 1113, 1186, 1218, 1239, 1306, 1323, 1349, 1394, 1428, 1455, 1499, 1533, 1564, 1603, 1643, 1672, 1700, 1728, 1764, 1809, 1843, 1870, 1890, 1922, 1953, 1976, 2002, 2030, 2060, 2088, 2116, 2166, 2193, 2220, 2247, 2274, 2301, 2333, 2363, 2390, 2421, 2451, 2477, 2503, 2548, 2607, 2634, 2685, 2728, 2765, 2791, 2818, 2863, 2895, 2923, 2971, 3003, 3030, 3055, 3081, 3109, 3137, 3165, 3193, 3232, 3259, 3289, 3315, 3341, 3367, 3393, 3430, 3472, 3520, 3576, 3606}
 
 func (i Payload) String() string {
 	i -= 1
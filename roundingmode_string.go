@@ -15,12 +15,15 @@ func _() {
 	_ = x[ToNegativeInf-4]
 	_ = x[ToPositiveInf-5]
 	_ = x[ToNearestTowardZero-6]
-	_ = x[unnecessary-7]
+	_ = x[ZeroFiveUp-7]
+	_ = x[ToOdd-8]
+	_ = x[Stochastic-9]
+	_ = x[unnecessary-10]
 }
 
-const _RoundingMode_name = "ToNearestEvenToNearestAwayToZeroAwayFromZeroToNegativeInfToPositiveInfToNearestTowardZerounnecessary"
+const _RoundingMode_name = "ToNearestEvenToNearestAwayToZeroAwayFromZeroToNegativeInfToPositiveInfToNearestTowardZeroZeroFiveUpToOddStochasticunnecessary"
 
-var _RoundingMode_index = [...]uint8{0, 13, 26, 32, 44, 57, 70, 89, 100}
+var _RoundingMode_index = [...]uint8{0, 13, 26, 32, 44, 57, 70, 89, 99, 104, 114, 125}
 
 func (i RoundingMode) String() string {
 	if i >= RoundingMode(len(_RoundingMode_index)-1) {
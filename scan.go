@@ -45,6 +45,7 @@ func (z *Big) scan(r io.ByteScanner) error {
 		switch err {
 		case ConversionSyntax:
 			z.form = qnan
+			z.compact = 0
 			z.Context.Conditions |= ConversionSyntax
 		default:
 			return err
@@ -65,9 +66,10 @@ func (z *Big) scan(r io.ByteScanner) error {
 		case io.EOF:
 			z.form = qnan
 			return io.ErrUnexpectedEOF
-		case ConversionSyntax:
+		case ConversionSyntax, InsufficientStorage:
 			z.form = qnan
-			z.Context.Conditions |= ConversionSyntax
+			z.compact = 0
+			z.Context.Conditions |= err.(Condition)
 		default:
 			return err
 		}
@@ -83,6 +85,7 @@ func (z *Big) scan(r io.ByteScanner) error {
 			z.xflow(MinScale, true, neg)
 		case ConversionSyntax:
 			z.form = qnan
+			z.compact = 0
 			z.Context.Conditions |= ConversionSyntax
 		default:
 			return err
@@ -268,7 +271,15 @@ Loop:
 		}
 	}
 
+	if len(buf) == 0 {
+		// No digits, like ".".
+		return ConversionSyntax
+	}
+
 	if big || z.compact == c.Inflated {
+		if z.Context.tooLarge(int64(len(buf))) {
+			return InsufficientStorage
+		}
 		z.unscaled.SetString(string(buf), 10)
 		z.compact = c.Inflated
 		z.precision = arith.BigLength(&z.unscaled)
@@ -300,6 +311,10 @@ func (z *Big) scanExponent(r io.ByteScanner) error {
 
 	ch, err = r.ReadByte()
 	if err != nil {
+		if err == io.EOF {
+			// No digits, like "12e".
+			return ConversionSyntax
+		}
 		return err
 	}
 	var neg bool
@@ -317,7 +332,10 @@ func (z *Big) scanExponent(r io.ByteScanner) error {
 		max++ // -math.MinInt
 	}
 
-	var exp uint64
+	var (
+		exp    uint64
+		digits bool
+	)
 	for {
 		ch, err := r.ReadByte()
 		if err != nil {
@@ -326,6 +344,7 @@ func (z *Big) scanExponent(r io.ByteScanner) error {
 			}
 			return err
 		}
+		digits = true
 		if ch < '0' || ch > '9' {
 			return ConversionSyntax
 		}
@@ -348,6 +367,10 @@ func (z *Big) scanExponent(r io.ByteScanner) error {
 		}
 		exp = v
 	}
+	if !digits {
+		// No digits, like "1e-".
+		return ConversionSyntax
+	}
 
 	if neg {
 		z.exp -= int(exp)
@@ -74,3 +74,192 @@ func TestSetStringTwice(t *testing.T) {
 		t.Errorf("wanted: %q, got %q", "2", x.String())
 	}
 }
+
+func TestBig_Parse(t *testing.T) {
+	inputs := []string{
+		"0", "-0", "+0.000", "1", "-1.5", ".5", "5.", "1e5", "1E+5",
+		"-1.25e-10", "123456789012345678901234567890123456789",
+		"18446744073709551615", "18446744073709551616",
+		"0.00000000000000000000000000000000000000000000000001",
+		"00000000000000000000000000000000000000000000000001",
+		"1E+9223372036854775807", "1E-9223372036854775807",
+		"1E+99999999999999999999", "-1E-99999999999999999999",
+		"inf", "-Infinity", "+INF", "NaN", "-nan123", "qNaN", "sNaN42",
+	}
+	for i := 0; i < 1000; i++ {
+		inputs = append(inputs, randString())
+	}
+	for i, s := range inputs {
+		var z, want Big
+		want.SetString(s)
+		err := z.Parse([]byte(s))
+		if err != nil || z.String() != want.String() ||
+			z.Context.Conditions != want.Context.Conditions {
+			t.Fatalf("#%d: Parse(%q): wanted (%s, %s), got (%s, %s, %v)",
+				i, s, &want, want.Context.Conditions, &z, z.Context.Conditions, err)
+		}
+	}
+
+	for i, test := range []struct {
+		s   string
+		off int
+		err Condition
+	}{
+		{"", 0, ConversionSyntax},
+		{"-", 1, ConversionSyntax},
+		{".", 1, ConversionSyntax},
+		{"1.2.3", 3, ConversionSyntax},
+		{"12x", 2, ConversionSyntax},
+		{" 1", 0, ConversionSyntax},
+		{"1e", 2, ConversionSyntax},
+		{"1e+", 3, ConversionSyntax},
+		{"1e5.", 3, ConversionSyntax},
+		{".e5", 1, ConversionSyntax},
+		{"infin", 5, ConversionSyntax},
+		{"infinityx", 8, ConversionSyntax},
+		{"-nam", 3, ConversionSyntax},
+		{"sNaNx", 4, ConversionSyntax},
+		{"NaN99999999999999999999", 22, ConversionSyntax},
+		{"x", 0, ConversionSyntax},
+	} {
+		var z Big
+		err := z.Parse([]byte(test.s))
+		e, ok := err.(*SyntaxError)
+		if !ok || e.Num != test.s || e.Offset != test.off || e.Err != test.err {
+			t.Fatalf("#%d: Parse(%q): wanted offset %d and %s, got %v",
+				i, test.s, test.off, test.err, err)
+		}
+		if !z.IsNaN(0) || z.Context.Conditions&test.err == 0 {
+			t.Fatalf("#%d: Parse(%q): wanted NaN with %s, got %s with %s",
+				i, test.s, test.err, &z, z.Context.Conditions)
+		}
+	}
+
+	z := Big{Context: Context{Options: &ContextOptions{MaxDigits: 5}}}
+	if err := z.Parse([]byte("-123456")); err == nil || err.(*SyntaxError).Err != InsufficientStorage {
+		t.Fatalf("wanted InsufficientStorage, got %v", err)
+	}
+}
+
+func TestBig_ParseAllocs(t *testing.T) {
+	inputs := [][]byte{
+		[]byte("-1234567.891"),
+		[]byte("12345678901234567890123456789.01234567E-5"),
+		[]byte("Infinity"),
+	}
+	var z Big
+	z.SetString("1E+100") // preallocate unscaled
+	n := testing.AllocsPerRun(100, func() {
+		for _, b := range inputs {
+			z.Parse(b)
+		}
+	})
+	if n != 0 {
+		t.Fatalf("wanted 0 allocations, got %f", n)
+	}
+}
+
+func BenchmarkBig_Parse(b *testing.B) {
+	var err error
+	for i := 0; i < b.N; i++ {
+		m := &benchInput[i%len(benchInput)]
+		err = m.b.Parse([]byte(m.s))
+	}
+	globOk = err == nil
+}
+
+func TestParser(t *testing.T) {
+	var (
+		strict = Parser{Strict: true, Separator: '_', AllowSpace: true}
+		api    = Parser{Strict: true, NoPlus: true, NoInf: true, NoNaN: true, MaxDigits: 5, MaxExponent: 10}
+		csv    = Parser{Separator: ',', AllowSpace: true}
+		gosep  = Parser{Separator: '_'}
+	)
+	for i, test := range []struct {
+		p    Parser
+		s    string
+		want string
+		off  int // offset of the error if want == ""
+	}{
+		{strict, "-1.5E+3", "-1.5E+3", 0},
+		{strict, "Inf", "Infinity", 0},
+		{strict, "sNaN12", "sNaN12", 0},
+		{strict, "qNaN", "", 0},
+		{strict, "1_000", "", 1},
+		{strict, " 1", "", 0},
+		{api, "12345", "12345", 0},
+		{api, "0.0012345", "0.0012345", 0},
+		{api, "123456", "", 5},
+		{api, "+1", "", 0},
+		{api, "-1", "-1", 0},
+		{api, "Infinity", "", 0},
+		{api, "-NaN", "", 1},
+		{api, "1E+10", "1E+10", 0},
+		{api, "1E+11", "", 1},
+		{api, "12E+10", "", 2},
+		{api, "1E-10", "1E-10", 0},
+		{api, "0.00000000001", "", 0},
+		{api, "1E+99999999999999999999", "", 1},
+		{api, "1E-99999999999999999999", "", 1},
+		{csv, " 1,234,567.50\t", "1234567.50", 0},
+		{csv, "1,,234", "", 1},
+		{csv, ",1", "", 0},
+		{csv, "1,", "", 1},
+		{csv, "1,.5", "", 1},
+		{csv, "  ", "", 2},
+		{csv, " NaN ", "NaN", 0},
+		{gosep, "1_000.50", "1000.50", 0},
+		{gosep, "1_2_3e1_0", "1.23E+12", 0},
+		{gosep, "1e_5", "", 2},
+		{gosep, "1__0", "", 1},
+		{gosep, "_1", "", 0},
+		{gosep, "1_000_000_000_000_000_000_000_000_000_000_000_000_000", "1000000000000000000000000000000000000000", 0},
+	} {
+		var z Big
+		err := test.p.Parse(&z, []byte(test.s))
+		if test.want == "" {
+			e, ok := err.(*SyntaxError)
+			if !ok || e.Offset != test.off || e.Err != ConversionSyntax {
+				t.Fatalf("#%d: Parse(%q): wanted error at offset %d, got (%s, %v)",
+					i, test.s, test.off, &z, err)
+			}
+			continue
+		}
+		if err != nil || z.String() != test.want {
+			t.Fatalf("#%d: Parse(%q): wanted %s, got (%s, %v)", i, test.s, test.want, &z, err)
+		}
+	}
+}
+
+func TestParser_InvalidSeparator(t *testing.T) {
+	for _, sep := range []byte("05.eE+-") {
+		var z Big
+		if err := (Parser{Separator: sep}).Parse(&z, []byte("1")); err == nil || !z.IsNaN(0) {
+			t.Fatalf("Separator %q: wanted an error and NaN, got (%s, %v)", sep, &z, err)
+		}
+	}
+}
+
+func TestParser_MaxExponentConditions(t *testing.T) {
+	p := Parser{MaxExponent: 10}
+	for _, s := range []string{"1E+99999999999999999999", "1E-99999999999999999999"} {
+		var z Big
+		p.Parse(&z, []byte(s))
+		if c := z.Context.Conditions; c != ConversionSyntax {
+			t.Fatalf("Parse(%q): wanted %s, got %s", s, ConversionSyntax, c)
+		}
+	}
+}
+
+func TestParser_Allocs(t *testing.T) {
+	p := Parser{Separator: '_', AllowSpace: true, MaxDigits: 34, MaxExponent: 6144}
+	b := []byte(" -1_234_567.891_012_345_678_901_234_567E-1_0 ")
+	var z Big
+	z.SetString("1E+100") // preallocate unscaled
+	n := testing.AllocsPerRun(100, func() {
+		p.Parse(&z, b)
+	})
+	if n != 0 {
+		t.Fatalf("wanted 0 allocations, got %f", n)
+	}
+}
@@ -0,0 +1,83 @@
+package decimal
+
+import "unicode/utf8"
+
+// siPrefixes maps exponents to SI prefixes.
+var siPrefixes = map[int]string{
+	30:  "Q",
+	27:  "R",
+	24:  "Y",
+	21:  "Z",
+	18:  "E",
+	15:  "P",
+	12:  "T",
+	9:   "G",
+	6:   "M",
+	3:   "k",
+	0:   "",
+	-3:  "m",
+	-6:  "µ",
+	-9:  "n",
+	-12: "p",
+	-15: "f",
+	-18: "a",
+	-21: "z",
+	-24: "y",
+	-27: "r",
+	-30: "q",
+}
+
+// siExponents maps SI prefixes to exponents. In addition to the
+// prefixes in siPrefixes, it accepts 'u' and the Greek letter mu
+// for micro.
+var siExponents = func() map[rune]int {
+	m := make(map[rune]int, len(siPrefixes)+2)
+	for e, p := range siPrefixes {
+		if p != "" {
+			r, _ := utf8.DecodeRuneInString(p)
+			m[r] = e
+		}
+	}
+	m['u'] = -6
+	m['μ'] = -6
+	return m
+}()
+
+// SIString returns x in engineering notation with the exponent
+// written as an SI prefix. For example, 1.5E+3 is "1.5k", 0.00025
+// is "250µ", and 100 is "100". Like EngString, it is exact, although
+// up to two trailing zeros may be added. To limit the number of
+// digits, round x first.
+//
+// The prefixes range from quecto (q, 1E-30) to quetta (Q, 1E+30).
+// Exponents outside of that range are written as in EngString.
+// Special values are written as in String.
+//
+// The result can be parsed with SetSIString.
+func (x *Big) SIString() string {
+	return x.toString(si)
+}
+
+// SetSIString sets z to the value of s, which may end with an SI
+// prefix, and returns z. For example, "1.5k" is 1.5E+3 and "250µ" is
+// 2.50E-4. Both 'u' and the Greek letter mu are also accepted for
+// micro.
+//
+// Other than the optional SI prefix, s has the same format and
+// result as in SetString.
+func (z *Big) SetSIString(s string) (*Big, bool) {
+	r, n := utf8.DecodeLastRuneInString(s)
+	if e, ok := siExponents[r]; ok && n < len(s) {
+		conds := z.Context.Conditions
+		z.Context.Conditions &^= ConversionSyntax
+		_, ok := z.SetString(s[:len(s)-n])
+		if ok && z.Context.Conditions&ConversionSyntax == 0 && z.IsFinite() {
+			z.Context.Conditions |= conds
+			z.exp += e
+			return z, true
+		}
+		// Perhaps the prefix is part of s, like the 'f' in "Inf".
+		z.Context.Conditions = conds
+	}
+	return z.SetString(s)
+}
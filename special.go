@@ -0,0 +1,1044 @@
+package decimal
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"sync"
+
+	"github.com/ericlagergren/decimal/internal/arith"
+)
+
+// Gamma sets z to the gamma function of x and returns z.
+//
+// Range:
+//     Input: all real numbers except 0 and the negative integers
+//     Output: all real numbers except 0
+//
+// Special cases:
+//     Gamma(NaN)  = NaN
+//     Gamma(+Inf) = +Inf
+//     Gamma(-Inf) = NaN
+//     Gamma(±0)   = ±Inf
+//     Gamma(x)    = NaN if x is a negative integer
+//
+// Gamma(±0) also raises DivisionByZero. If x is a positive
+// integer, Gamma(x) = (x-1)! is exact if it can be represented in
+// the Context's precision. Otherwise, the result is correctly
+// rounded.
+func (c Context) Gamma(z, x *Big) *Big {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, x, gamma) {
+		return z
+	}
+
+	if x.IsInf(0) {
+		if x.IsInf(+1) {
+			return z.SetInf(false)
+		}
+		return z.setNaN(InvalidOperation, qnan, gammainvalid)
+	}
+	if x.isZero() {
+		z.Context.Conditions |= DivisionByZero
+		return z.SetInf(x.Signbit())
+	}
+	if x.IsInt() {
+		if x.Signbit() {
+			return z.setNaN(InvalidOperation, qnan, gammainvalid)
+		}
+		// Gamma(n) = (n-1)!
+		if n, ok := x.Uint64(); ok {
+			return c.factorial(z, n-1)
+		}
+		return c.overflow(z, false)
+	}
+
+	// For |x| >= 10**20, |ln Gamma(x)| > 10**21, so the result is
+	// outside of any Context's range.
+	if x.adjusted() >= 20 {
+		if !x.Signbit() {
+			return c.overflow(z, false)
+		}
+		var t Big
+		return c.underflow(z, c.dup().sinPi(&t, x).Signbit())
+	}
+
+	return c.correctlyRound(z, 0, func(ctx Context, t *Big) *Big {
+		sign := ctx.lgamma(t, x)
+		ctx.Exp(t, t)
+		return t.SetSignbit(sign < 0)
+	})
+}
+
+// LogGamma sets z to the natural logarithm of the absolute value
+// of the gamma function of x and returns z and the sign of
+// Gamma(x), either -1 or +1.
+//
+// Range:
+//     Input: all real numbers
+//     Output: all real numbers
+//
+// Special cases:
+//     LogGamma(NaN)  = NaN
+//     LogGamma(+Inf) = +Inf
+//     LogGamma(-Inf) = NaN
+//     LogGamma(±0)   = +Inf
+//     LogGamma(x)    = +Inf if x is a negative integer
+//     LogGamma(1)    = 0
+//     LogGamma(2)    = 0
+//
+// LogGamma(0) and LogGamma of a negative integer also raise
+// DivisionByZero. Like math.Lgamma, the sign of LogGamma(-0) is -1.
+func (c Context) LogGamma(z, x *Big) (*Big, int) {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z, 1
+	}
+	if z.checkNaNs(x, x, lgamma) {
+		return z, 1
+	}
+
+	if x.IsInf(0) {
+		if x.IsInf(+1) {
+			return z.SetInf(false), 1
+		}
+		return z.setNaN(InvalidOperation, qnan, gammainvalid), 1
+	}
+	if x.isZero() || x.IsInt() && x.Signbit() {
+		z.Context.Conditions |= DivisionByZero
+		if x.isZero() && x.Signbit() {
+			return z.SetInf(false), -1
+		}
+		return z.SetInf(false), 1
+	}
+	if x.IsInt() && (cmpInt(x, 1) == 0 || cmpInt(x, 2) == 0) {
+		// Gamma(1) = Gamma(2) = 1
+		return z.setZero(0, 0), 1
+	}
+
+	sign := 1
+	c.correctlyRound(z, 0, func(ctx Context, t *Big) *Big {
+		sign = ctx.lgamma(t, x)
+		// The error of lgamma is absolute, so a result close to
+		// zero, which happens when |Gamma(x)| is close to one,
+		// needs more digits.
+		if adj := t.adjusted(); adj < 0 && !t.isZero() {
+			ctx.Precision -= adj
+			ctx.lgamma(t, x)
+		}
+		return t
+	})
+	return z, sign
+}
+
+// Factorial sets z to x! and returns z.
+//
+// Range:
+//     Input: the non-negative integers
+//     Output: Factorial(x) >= 1
+//
+// Special cases:
+//     Factorial(NaN)  = NaN
+//     Factorial(+Inf) = +Inf
+//     Factorial(x)    = NaN if x < 0 or x is not an integer
+//
+// The result is exact if it can be represented in the Context's
+// precision. Otherwise, it is correctly rounded.
+func (c Context) Factorial(z, x *Big) *Big {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, x, factorial) {
+		return z
+	}
+
+	if x.IsInf(+1) {
+		return z.SetInf(false)
+	}
+	if !x.IsInt() || x.Signbit() && !x.isZero() {
+		return z.setNaN(InvalidOperation, qnan, factinvalid)
+	}
+	n, ok := x.Uint64()
+	if !ok {
+		return c.overflow(z, false)
+	}
+	return c.factorial(z, n)
+}
+
+// factorial sets z to n! and returns z.
+func (c Context) factorial(z *Big, n uint64) *Big {
+	// n! has about ln Gamma(n+1) / ln 10 digits. Each factor of 5
+	// adds a trailing zero.
+	lg, _ := math.Lgamma(float64(n) + 1)
+	digits := lg/math.Ln10 + 1
+	if digits > float64(c.emax())+2 {
+		return c.overflow(z, false)
+	}
+	var zeros uint64
+	for p := n / 5; p > 0; p /= 5 {
+		zeros += p
+	}
+
+	prec := c.precision()
+	if prec != UnlimitedPrecision && digits-float64(zeros) > float64(prec+3) {
+		var t Big
+		t.SetUint64(n)
+		Context{Precision: UnlimitedPrecision}.Add(&t, &t, one.get())
+		return c.correctlyRound(z, 0, func(ctx Context, u *Big) *Big {
+			ctx.lgamma(u, &t)
+			return ctx.Exp(u, u)
+		})
+	}
+
+	if z.checkStorage(c, int64(digits)) {
+		return z
+	}
+	var m big.Int
+	if n > 1 {
+		m.MulRange(1, int64(n))
+	} else {
+		m.SetUint64(1)
+	}
+	return c.finish(z.setCoeff(&m, 0, 0))
+}
+
+// Binomial sets z to the binomial coefficient of n and k, the
+// number of ways to choose k elements from a set of n elements,
+// and returns z.
+//
+// Range:
+//     Input: all integers
+//     Output: all integers
+//
+// Special cases:
+//     Binomial(NaN, k)  = NaN
+//     Binomial(n, NaN)  = NaN
+//     Binomial(n, k)    = NaN if n or k is not an integer or is infinite
+//     Binomial(n, k)    = 0 if k < 0 or 0 <= n < k
+//     Binomial(n, k)    = (-1)**k × Binomial(k-n-1, k) if n < 0
+//
+// The result is exact if it can be represented in the Context's
+// precision. Otherwise, it is correctly rounded.
+func (c Context) Binomial(z, n, k *Big) *Big {
+	if debug {
+		n.validate()
+		k.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(n, k, binomial) {
+		return z
+	}
+	if !n.IsInt() || !k.IsInt() {
+		return z.setNaN(InvalidOperation, qnan, binominvalid)
+	}
+
+	if k.Signbit() && !k.isZero() {
+		return z.setZero(0, 0)
+	}
+
+	exact := Context{Precision: UnlimitedPrecision}
+	var nn, kk Big
+	nn.Copy(n)
+	kk.Copy(k)
+	var sign form
+	if nn.Signbit() && !nn.isZero() {
+		// C(n, k) = (-1)**k C(k-n-1, k)
+		exact.Sub(&nn, &kk, &nn)
+		exact.Sub(&nn, &nn, one.get())
+		if odd(&kk) {
+			sign = signbit
+		}
+	}
+	if kk.Cmp(&nn) > 0 {
+		return z.setZero(0, 0)
+	}
+	// C(n, k) = C(n, n-k)
+	var t Big
+	if exact.Sub(&t, &nn, &kk).Cmp(&kk) < 0 {
+		kk.Copy(&t)
+	}
+	if kk.isZero() {
+		return z.SetMantScale(1, 0).SetSignbit(sign != 0)
+	}
+
+	// With k <= n/2, (n/k)**k <= C(n, k) <= (en/k)**k, and C(n, k)
+	// has at most log5(n) trailing zeros.
+	ln, lk := approxLog10(&nn), approxLog10(&kk)
+	kf, _ := kk.Float64()
+	lower := kf * (ln - lk)
+	if lower > float64(c.emax())+1 {
+		return c.overflow(z, sign != 0)
+	}
+	prec := c.precision()
+	if prec != UnlimitedPrecision && lower-ln/math.Log10(5) > float64(prec+1) {
+		var n1, k1, nk1 Big
+		exact.Add(&n1, &nn, one.get())
+		exact.Add(&k1, &kk, one.get())
+		exact.Sub(&nk1, &n1, &kk)
+		return c.correctlyRound(z, 0, func(ctx Context, t *Big) *Big {
+			// C(n, k) = exp(ln Gamma(n+1) - ln Gamma(k+1) - ln Gamma(n-k+1))
+			var u Big
+			ctx.lgamma(t, &n1)
+			ctx.lgamma(&u, &k1)
+			exact.Sub(t, t, &u)
+			ctx.lgamma(&u, &nk1)
+			exact.Sub(t, t, &u)
+			ctx.Exp(t, t)
+			return t.SetSignbit(sign != 0)
+		})
+	}
+
+	if z.checkStorage(c, int64(lower+kf*math.Log10E+2)) {
+		return z
+	}
+	ki, _ := kk.Int64()
+	var num, den big.Int
+	nb := nn.Int(nil)
+	if nb.IsInt64() {
+		num.MulRange(nb.Int64()-ki+1, nb.Int64())
+	} else {
+		num.SetUint64(1)
+		for i := int64(0); i < ki; i++ {
+			num.Mul(&num, nb)
+			nb.Sub(nb, big.NewInt(1))
+		}
+	}
+	den.MulRange(1, ki)
+	num.Quo(&num, &den)
+	return c.finish(z.setCoeff(&num, sign, 0))
+}
+
+// betaExactDigits is the maximum size, in digits, of the product
+// Beta computes exactly when one of its arguments is a positive
+// integer.
+const betaExactDigits = 20000
+
+// Beta sets z to the beta function of a and b,
+//
+//    Gamma(a) × Gamma(b) / Gamma(a + b)
+//
+// and returns z.
+//
+// Range:
+//     Input: all real numbers except 0 and the negative integers
+//     Output: all real numbers
+//
+// Special cases:
+//     Beta(NaN, b)     = NaN
+//     Beta(a, NaN)     = NaN
+//     Beta(a, b)       = NaN if a or b is -Inf, 0, or a negative integer
+//     Beta(+Inf, b)    = 0 if b > 0
+//     Beta(+Inf, b)    = NaN if b < 0
+//     Beta(a, b)       = 0 if a + b is 0 or a negative integer
+//
+// If a or b is a small positive integer, the result is exact if it
+// can be represented in the Context's precision. Otherwise, the
+// result is correctly rounded.
+func (c Context) Beta(z, a, b *Big) *Big {
+	if debug {
+		a.validate()
+		b.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(a, b, beta) {
+		return z
+	}
+
+	pole := func(x *Big) bool {
+		return x.IsInf(-1) || x.IsInt() && (x.isZero() || x.Signbit())
+	}
+	if pole(a) || pole(b) {
+		return z.setNaN(InvalidOperation, qnan, betainvalid)
+	}
+	if a.IsInf(+1) || b.IsInf(+1) {
+		if a.Signbit() || b.Signbit() {
+			return z.setNaN(InvalidOperation, qnan, betainvalid)
+		}
+		return z.setZero(0, 0)
+	}
+
+	// B(m, b) = (m-1)! / (b × (b+1) × ... × (b+m-1))
+	if a.IsInt() && b.IsInt() && a.Cmp(b) > 0 || !a.IsInt() {
+		a, b = b, a
+	}
+	if m, ok := a.Uint64(); ok && a.IsInt() && betaExact(m, b) {
+		exact := Context{Precision: UnlimitedPrecision}
+		var num, den, t Big
+		exact.factorial(&num, m-1)
+		den.Copy(b)
+		t.Copy(b)
+		for i := uint64(1); i < m; i++ {
+			exact.Add(&t, &t, one.get())
+			exact.Mul(&den, &den, &t)
+		}
+		return c.Quo(z, &num, &den)
+	}
+
+	var s Big
+	Context{Precision: UnlimitedPrecision}.Add(&s, a, b)
+	if s.IsInt() && (s.isZero() || s.Signbit()) {
+		return z.setZero(0, 0)
+	}
+
+	return c.correctlyRound(z, 0, func(ctx Context, t *Big) *Big {
+		// B(a, b) = ±exp(ln|Gamma(a)| + ln|Gamma(b)| - ln|Gamma(a+b)|)
+		exact := Context{Precision: UnlimitedPrecision}
+		var u Big
+		sign := ctx.lgamma(t, a)
+		sign *= ctx.lgamma(&u, b)
+		exact.Add(t, t, &u)
+		sign *= ctx.lgamma(&u, &s)
+		exact.Sub(t, t, &u)
+		ctx.Exp(t, t)
+		return t.SetSignbit(sign < 0)
+	})
+}
+
+// betaExact reports whether Beta should compute B(m, b) from the
+// exact product b × (b+1) × ... × (b+m-1).
+func betaExact(m uint64, b *Big) bool {
+	if m == 0 || m > betaExactDigits {
+		return false
+	}
+	// Each factor has at most max(adj(b), len(m)) + 2 integral
+	// digits and -exp(b) fractional digits.
+	n := max(b.adjusted(), arith.Length(m)) + 2
+	if b.exp < 0 {
+		n -= b.exp
+	}
+	return n <= betaExactDigits/int(m)
+}
+
+// Zeta sets z to the Riemann zeta function of s and returns z.
+//
+// Range:
+//     Input: all real numbers except 1
+//     Output: all real numbers
+//
+// Special cases:
+//     Zeta(NaN)  = NaN
+//     Zeta(+Inf) = 1
+//     Zeta(-Inf) = NaN
+//     Zeta(0)    = -0.5
+//     Zeta(1)    = +Inf
+//     Zeta(s)    = 0 if s is a negative even integer
+//
+// Zeta(1) also raises DivisionByZero. If s is a negative odd
+// integer, the result is a rational number that is correctly
+// rounded; otherwise, the result is also correctly rounded.
+func (c Context) Zeta(z, s *Big) *Big {
+	if debug {
+		s.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(s, s, zeta) {
+		return z
+	}
+
+	if s.IsInf(0) {
+		if s.IsInf(+1) {
+			return z.SetMantScale(1, 0)
+		}
+		return z.setNaN(InvalidOperation, qnan, zetaneginf)
+	}
+	if s.isZero() {
+		return c.Set(z, z.SetMantScale(-5, 1))
+	}
+	if s.IsInt() {
+		if cmpInt(s, 1) == 0 {
+			z.Context.Conditions |= DivisionByZero
+			return z.SetInf(false)
+		}
+		if s.Signbit() {
+			if !odd(s) {
+				// The trivial zeros.
+				return z.setZero(0, 0)
+			}
+			// Zeta(-n) = -B(n+1) / (n+1)
+			if n, ok := s.Int64(); ok && n > -200 {
+				k := int(1-n) / 2
+				var r big.Rat
+				r.Quo(bernoulli(k), big.NewRat(-int64(2*k), 1))
+				return c.SetRat(z, &r)
+			}
+		}
+	}
+
+	// Zeta(s) = 1 + 2**-s + 3**-s + ... and 0 < Zeta(s)-1 < 2**(1-s)
+	// for s >= 4.
+	if !s.Signbit() && cmpInt(s, 4) >= 0 {
+		sf := 1e9
+		if s.adjusted() < 9 {
+			sf, _ = s.Float64()
+		}
+		e := int(math.Floor((1-sf)*math.Log10(2))) + 1
+		if c.perturb(z, one.get(), e, true) {
+			return z
+		}
+	}
+
+	return c.correctlyRound(z, 0, func(ctx Context, t *Big) *Big {
+		return ctx.zeta(t, s)
+	})
+}
+
+// ZetaContext is like Zeta, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) ZetaContext(ctx context.Context, z, s *Big) *Big {
+	return c.withDone(ctx).Zeta(z, s)
+}
+
+// zeta sets z to Zeta(s) for a finite s that is not 1 and returns
+// z.
+func (c Context) zeta(z, s *Big) *Big {
+	if s.Cmp(ptFive.get()) >= 0 {
+		return c.zetaBorwein(z, s)
+	}
+
+	// Zeta(s) = 2**s × pi**(s-1) × sin(pi×s/2) × Gamma(1-s) × Zeta(1-s)
+	//
+	// The logarithms of the first, second, and fourth factors are
+	// summed so that only one of them can overflow.
+	exact := Context{Precision: UnlimitedPrecision}
+	ctx := c
+	ctx.Precision += lgammaExtra(s, c.Precision)
+
+	var y, l, t Big
+	ctx.Sub(&y, one.get(), s)
+	c.lgamma(&l, &y)
+	exact.Add(&l, &l, ctx.Mul(&t, s, ctx.Log(&t, two.get())))
+	exact.Sub(&l, &l, ctx.Mul(&t, &y, ctx.Log(&t, ctx.pi(&t))))
+	c.Exp(z, &l)
+
+	exact.Mul(&t, s, ptFive.get())
+	c.Mul(z, z, c.sinPi(&t, &t))
+	return c.Mul(z, z, c.zetaBorwein(&t, &y))
+}
+
+// zetaBorwein sets z to Zeta(s) for s >= 1/2 and s != 1 and
+// returns z.
+//
+// It uses algorithm 2 from "An Efficient Algorithm for the Riemann
+// Zeta Function" by P. Borwein. 1995.
+// http://www.cecm.sfu.ca/personal/pborwein/PAPERS/P155.pdf
+func (c Context) zetaBorwein(z, s *Big) *Big {
+	ctx := c
+
+	// 1 - 2**(1-s) loses digits when s is close to 1.
+	var u Big
+	Context{Precision: UnlimitedPrecision}.Sub(&u, one.get(), s)
+	if adj := u.adjusted(); adj < 0 {
+		ctx.Precision -= adj
+	}
+	// The n terms of the sum each add a rounding error.
+	ctx.Precision += arith.Length(uint64(ctx.Precision))
+
+	// The error is less than 3 / (3 + √8)**n, and
+	// log10(3 + √8) > 1 / 1.31.
+	n := uint64(ctx.Precision)*131/100 + 1
+
+	// d_k = Σ_{i=0}^{k} e_i, where
+	//
+	//    e_i = n × (n+i-1)! × 4**i / ((n-i)! × (2i)!)
+	//
+	// are integers with e_0 = 1 and
+	//
+	//    e_{i+1} = e_i × 2(n+i)(n-i) / ((i+1)(2i+1))
+	d := make([]big.Int, n+1)
+	var e, t big.Int
+	e.SetUint64(1)
+	for i := uint64(0); i <= n; i++ {
+		if i > 0 {
+			d[i].Add(&d[i-1], &e)
+		} else {
+			d[i].Set(&e)
+		}
+		e.Mul(&e, t.SetUint64(2*(n+i)*(n-i)))
+		e.Quo(&e, t.SetUint64((i+1)*(2*i+1)))
+	}
+
+	// m**s is completely multiplicative, so it only needs to be
+	// computed with Pow for prime m.
+	pw := make([]Big, n+1)
+	spf := make([]uint64, n+1) // smallest prime factors
+	var p Big
+	for m := uint64(2); m <= n; m++ {
+		if z.checkDone(c) {
+			return z
+		}
+		if spf[m] == 0 {
+			for j := m; j <= n; j += m {
+				if spf[j] == 0 {
+					spf[j] = m
+				}
+			}
+			ctx.Pow(&pw[m], p.SetUint64(m), s)
+		} else {
+			ctx.Mul(&pw[m], &pw[spf[m]], &pw[m/spf[m]])
+		}
+	}
+	pw[1].SetUint64(1)
+
+	// Zeta(s) = -1 / (d_n × (1 - 2**(1-s))) × Σ_{k=0}^{n-1} (-1)**k (d_k - d_n) / (k+1)**s
+	var sum, term Big
+	sum.SetUint64(0)
+	for k := uint64(0); k < n; k++ {
+		term.SetBigMantScale(t.Sub(&d[k], &d[n]), 0)
+		ctx.Quo(&term, &term, &pw[k+1])
+		if k%2 == 0 {
+			ctx.Add(&sum, &sum, &term)
+		} else {
+			ctx.Sub(&sum, &sum, &term)
+		}
+	}
+	ctx.Pow(&p, two.get(), &u)
+	ctx.Sub(&p, one.get(), &p)
+	ctx.Mul(&p, &p, term.SetBigMantScale(&d[n], 0))
+	ctx.Quo(z, &sum, &p)
+	return z.CopyNeg(z)
+}
+
+// Erf sets z to the error function of x and returns z.
+//
+// Range:
+//     Input: all real numbers
+//     Output: -1 <= Erf(x) <= 1
+//
+// Special cases:
+//     Erf(NaN)  = NaN
+//     Erf(±Inf) = ±1
+//     Erf(±0)   = ±0
+func (c Context) Erf(z, x *Big) *Big {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, x, erf) {
+		return z
+	}
+
+	neg := x.Signbit()
+	if x.IsInf(0) {
+		return z.SetMantScale(1, 0).SetSignbit(neg)
+	}
+	if x.isZero() {
+		return z.setZero(x.form, 0)
+	}
+
+	// Erf(x) = sign(x) × (1 - Erfc(|x|))
+	if x.adjusted() >= 0 {
+		var t Big
+		t.SetMantScale(1, 0).SetSignbit(neg)
+		if c.perturb(z, &t, erfcExp(x), neg) {
+			return z
+		}
+	}
+
+	c.correctlyRound(z, erfExtra(x), func(ctx Context, t *Big) *Big {
+		return ctx.erf(t, x)
+	})
+	z.checkDone(c)
+	return z
+}
+
+// ErfContext is like Erf, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) ErfContext(ctx context.Context, z, x *Big) *Big {
+	return c.withDone(ctx).Erf(z, x)
+}
+
+// Erfc sets z to the complementary error function of x, 1 - Erf(x),
+// and returns z.
+//
+// Range:
+//     Input: all real numbers
+//     Output: 0 <= Erfc(x) <= 2
+//
+// Special cases:
+//     Erfc(NaN)  = NaN
+//     Erfc(+Inf) = 0
+//     Erfc(-Inf) = 2
+//     Erfc(±0)   = 1
+//
+// If the result is too small for the Context, Erfc raises
+// Underflow.
+func (c Context) Erfc(z, x *Big) *Big {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, x, erfc) {
+		return z
+	}
+
+	if x.IsInf(0) {
+		if x.IsInf(+1) {
+			return z.setZero(0, 0)
+		}
+		return z.SetMantScale(2, 0)
+	}
+	if x.isZero() {
+		return z.SetMantScale(1, 0)
+	}
+
+	if x.Signbit() {
+		// Erfc(x) = 2 - Erfc(|x|)
+		if x.adjusted() >= 0 && c.perturb(z, two.get(), erfcExp(x), false) {
+			return z
+		}
+		c.correctlyRound(z, erfExtra(x), func(ctx Context, t *Big) *Big {
+			ctx.erf(t, x)
+			return ctx.Sub(t, one.get(), t)
+		})
+		z.checkDone(c)
+		return z
+	}
+
+	c.correctlyRound(z, erfExtra(x), func(ctx Context, t *Big) *Big {
+		var x2 Big
+		ctx.Mul(&x2, x, x)
+		if cmpInt(&x2, int64(math.Ceil(float64(ctx.Precision)*math.Ln10/2))) < 0 {
+			// 1 - Erf(x) loses about x**2 / ln(10) digits.
+			v, _ := x2.Float64()
+			ctx.Precision += int(v/math.Ln10) + 2
+			ctx.erf(t, x)
+			return ctx.Sub(t, one.get(), t)
+		}
+
+		// Erfc(x) = 2x × e**(-x**2) / (√pi × f(x))
+		//
+		// Lentz stops once a term changes f(x) by less than eps, so
+		// use a few more digits than eps and round to nearest so
+		// that rounding errors cannot prevent it from stopping.
+		lctx := ctx
+		lctx.Precision += 3
+		lctx.RoundingMode = ToNearestEven
+		g := erfcg{
+			ctx: lctx,
+			b0:  ctx.Add(new(Big), ctx.Mul(new(Big), &x2, two.get()), one.get()),
+			t:   Term{A: new(Big), B: new(Big)},
+		}
+		ctx.Lentz(t, &g)
+
+		var u Big
+		ctx.Mul(t, t, ctx.Sqrt(&u, ctx.pi(&u)))
+		ctx.Quo(t, ctx.Mul(&u, x, two.get()), t)
+		return ctx.Mul(t, t, ctx.Exp(&u, x2.CopyNeg(&x2)))
+	})
+	z.checkDone(c)
+	return z
+}
+
+// ErfcContext is like Erfc, but it stops early if ctx is done. See
+// PiContext.
+func (c Context) ErfcContext(ctx context.Context, z, x *Big) *Big {
+	return c.withDone(ctx).Erfc(z, x)
+}
+
+// erfExtra returns the number of extra digits Erf and Erfc need to
+// account for the error in x**2.
+func erfExtra(x *Big) int {
+	return max(2*(x.adjusted()+1), 0)
+}
+
+// erfcExp returns an e such that Erfc(|x|) < 10**e for |x| >= 1.
+func erfcExp(x *Big) int {
+	var t Big
+	xf, _ := t.CopyAbs(x).Float64()
+	if xf > 1e4 {
+		// Erfc is decreasing, so this is still an upper bound.
+		xf = 1e4
+	}
+	// Erfc(x) < e**(-x**2) / (x × √pi)
+	return int(math.Ceil((-xf*xf-math.Log(xf*math.Sqrt(math.Pi)))/math.Ln10)) + 1
+}
+
+// erf sets z to Erf(x) for a finite, non-zero x and returns z.
+func (c Context) erf(z, x *Big) *Big {
+	// Erf(x) = 2 × e**(-x**2) / √pi × Σ_{n=0}^{∞} x × (2x**2)**n / (1×3×...×(2n+1))
+	xf, _ := x.Float64()
+	n := erfTerms(math.Abs(xf), c.Precision)
+
+	var x2, q Big
+	c.Mul(&x2, x, x)
+	c.Mul(&x2, &x2, two.get())
+	BinarySplit(z, c, 0, n,
+		func(_ uint64) *Big { return one.get() },
+		func(n uint64) *Big {
+			if n == 0 {
+				return x
+			}
+			return &x2
+		},
+		func(_ uint64) *Big { return one.get() },
+		func(n uint64) *Big { return q.SetUint64(2*n + 1) },
+	)
+
+	var t Big
+	c.Mul(&x2, &x2, ptFive.get())
+	c.Exp(&t, x2.CopyNeg(&x2))
+	c.Mul(z, z, &t)
+	c.Mul(z, z, two.get())
+	return c.Quo(z, z, c.Sqrt(&t, c.pi(&t)))
+}
+
+// erfTerms returns the number of terms of the series used by erf
+// to compute Erf(x) to prec digits.
+func erfTerms(x float64, prec int) uint64 {
+	// The terms grow until n ≈ x**2 and, once n >= 2x**2, shrink
+	// faster than a geometric series with a ratio of 1/2. Stop when
+	// the term is prec digits smaller than the largest term.
+	lr := math.Log(2 * x * x)
+	lim := float64(prec)*math.Ln10 + 1
+	var lt, lmax float64
+	n := uint64(1)
+	for ; ; n++ {
+		lt += lr - math.Log(float64(2*n+1))
+		lmax = math.Max(lmax, lt)
+		if float64(n) >= 2*x*x && lt < lmax-lim {
+			break
+		}
+	}
+	return n + 1
+}
+
+// erfcg is a Generator that computes the continued fraction
+//
+//                                 1×2
+//     f(x) = 2x**2 + 1 - ---------------------------
+//                                       3×4
+//                        2x**2 + 5 - ---------------
+//                                    2x**2 + 9 - ...
+//
+// for which Erfc(x) = 2x × e**(-x**2) / (√pi × f(x)). It converges
+// quickly for large x.
+type erfcg struct {
+	ctx Context
+	b0  *Big   // 2x**2 + 1
+	n   uint64 // Term number
+	t   Term   // Term storage. Does not need to be manually set.
+}
+
+var _ Lentzer = (*erfcg)(nil)
+
+func (e *erfcg) Context() Context {
+	return e.ctx
+}
+
+func (e *erfcg) Lentz() (f, Δ, C, D, eps *Big) {
+	f = WithPrecision(e.ctx.Precision)
+	Δ = WithPrecision(e.ctx.Precision)
+	C = WithPrecision(e.ctx.Precision)
+	D = WithPrecision(e.ctx.Precision)
+	eps = New(1, e.ctx.Precision-2)
+	return f, Δ, C, D, eps
+}
+
+func (e *erfcg) Next() bool {
+	return true
+}
+
+func (e *erfcg) Term() Term {
+	// a_n = -(2n-1)(2n), b_n = 2x**2 + 1 + 4n
+	if e.n == 0 {
+		e.t.A.SetUint64(0)
+		e.t.B.Copy(e.b0)
+	} else {
+		e.t.A.SetUint64((2*e.n - 1) * (2 * e.n)).SetSignbit(true)
+		e.ctx.Add(e.t.B, e.b0, e.t.B.SetUint64(4*e.n))
+	}
+	e.n++
+	return e.t
+}
+
+// lgamma sets z to ln|Gamma(x)| for a finite x that is neither zero
+// nor a negative integer and returns the sign of Gamma(x).
+//
+// The error of the result is absolute: it is a few units of
+// 10**-c.Precision, so z is rounded to that many digits after the
+// decimal point.
+func (c Context) lgamma(z, x *Big) int {
+	exact := Context{Precision: UnlimitedPrecision}
+	ctx := c
+	ctx.Precision += lgammaExtra(x, c.Precision)
+
+	sign := 1
+	if x.Cmp(ptFive.get()) < 0 {
+		// ln|Gamma(x)| = ln(pi) - ln|sin(pi×x)| - ln Gamma(1-x)
+		var s, y, t Big
+		ctx.sinPi(&s, x)
+		if s.Signbit() {
+			sign = -1
+		}
+		// ln|sin(pi×x)| has up to len(3×adj) integral digits.
+		lctx := ctx
+		lctx.Precision += arith.Length(uint64(3 * max(-s.adjusted(), 1)))
+		lctx.Log(&s, s.CopyAbs(&s))
+
+		ctx.Sub(&y, one.get(), x)
+		c.lgamma(z, &y)
+		exact.Sub(z, lctx.Log(&t, lctx.pi(&t)), z)
+		exact.Sub(z, z, &s)
+	} else {
+		// ln Gamma(x) = ln Gamma(x+m) - ln(x × (x+1) × ... × (x+m-1))
+		//
+		// Stirling's series converges quickly for x+m >= prec.
+		var y, p Big
+		y.Copy(x)
+		p.SetUint64(1)
+		x0 := int64(ctx.Precision)
+		shifted := false
+		for cmpInt(&y, x0) < 0 {
+			ctx.Mul(&p, &p, &y)
+			exact.Add(&y, &y, one.get())
+			shifted = true
+		}
+		ctx.stirling(z, &y)
+		if shifted {
+			exact.Sub(z, z, ctx.Log(&p, &p))
+		}
+	}
+
+	// Drop the digits beyond the error.
+	Context{Precision: max(z.adjusted()+1+c.Precision, 1)}.Round(z)
+	return sign
+}
+
+// lgammaExtra returns the number of digits lgamma needs in addition
+// to prec. They account for the integral digits of ln Gamma(y),
+// about y ln(y) for y = max(|x|, prec), and for the rounding errors
+// of the O(prec) operations lgamma uses.
+func lgammaExtra(x *Big, prec int) int {
+	n := arith.Length(uint64(prec))
+	adj := max(x.adjusted()+1, n)
+	return adj + arith.Length(uint64(3*adj)) + n + 2
+}
+
+// stirling sets z to ln Gamma(x) for x >= c.Precision using
+// Stirling's series
+//
+//    ln Gamma(x) = (x - 1/2) ln(x) - x + ln(2pi)/2 + Σ_{k=1}^{∞} B_2k / (2k(2k-1) x**(2k-1))
+//
+// and returns z.
+func (c Context) stirling(z, x *Big) *Big {
+	var t, u Big
+	c.Log(&t, x)
+	c.Mul(z, c.Sub(&u, x, ptFive.get()), &t)
+	c.Sub(z, z, x)
+	c.Log(&t, c.Mul(&t, c.pi(&t), two.get()))
+	c.Add(z, z, c.Mul(&t, &t, ptFive.get()))
+
+	var xp, x2 Big
+	xp.Copy(x)
+	c.Mul(&x2, x, x)
+	for k := 1; ; k++ {
+		c.SetRat(&t, bernoulli(k))
+		c.Quo(&t, &t, &xp)
+		c.Quo(&t, &t, u.SetUint64(uint64(2*k*(2*k-1))))
+		if t.adjusted() < z.adjusted()-c.Precision {
+			break
+		}
+		c.Add(z, z, &t)
+		c.Mul(&xp, &xp, &x2)
+	}
+	return z
+}
+
+// odd reports whether the integer x is odd.
+func odd(x *Big) bool {
+	if x.exp > 0 {
+		return false
+	}
+	var b big.Int
+	return x.Int(&b).Bit(0) != 0
+}
+
+// approxLog10 returns an approximation of log10(|x|) for a finite,
+// non-zero x.
+func approxLog10(x *Big) float64 {
+	var t Big
+	t.CopyAbs(x)
+	adj := t.adjusted()
+	t.exp -= adj
+	f, _ := t.Float64()
+	return float64(adj) + math.Log10(f)
+}
+
+// bernoulliCache holds the Bernoulli numbers B_0, B_2, B_4, ...
+var bernoulliCache struct {
+	sync.Mutex
+	b []*big.Rat
+}
+
+// bernoulli returns the Bernoulli number B_2k for k >= 0. The
+// result must not be modified.
+func bernoulli(k int) *big.Rat {
+	bc := &bernoulliCache
+	bc.Lock()
+	defer bc.Unlock()
+	if k >= len(bc.b) {
+		bc.b = bernoulliNumbers(max(k, max(2*len(bc.b), 16)))
+	}
+	return bc.b[k]
+}
+
+// bernoulliNumbers returns B_0, B_2, ..., B_2n.
+//
+// They are computed from the tangent numbers T_k with algorithm
+// TangentNumbers from "Fast computation of Bernoulli, Tangent and
+// Secant numbers" by R. P. Brent and D. Harvey. 2011.
+// https://arxiv.org/abs/1108.0286
+func bernoulliNumbers(n int) []*big.Rat {
+	T := make([]big.Int, n+1)
+	if n >= 1 {
+		T[1].SetUint64(1)
+	}
+	var t big.Int
+	for k := 2; k <= n; k++ {
+		T[k].Mul(&T[k-1], t.SetInt64(int64(k-1)))
+	}
+	for k := 2; k <= n; k++ {
+		for j := k; j <= n; j++ {
+			// T_j = (j-k) × T_{j-1} + (j-k+2) × T_j
+			t.Mul(&T[j-1], big.NewInt(int64(j-k)))
+			T[j].Mul(&T[j], big.NewInt(int64(j-k+2)))
+			T[j].Add(&T[j], &t)
+		}
+	}
+
+	b := make([]*big.Rat, n+1)
+	b[0] = big.NewRat(1, 1)
+	for k := 1; k <= n; k++ {
+		// B_2k = (-1)**(k-1) × 2k × T_k / (2**2k × (2**2k - 1))
+		var num, den big.Int
+		num.Mul(&T[k], big.NewInt(int64(2*k)))
+		if k%2 == 0 {
+			num.Neg(&num)
+		}
+		den.Lsh(big.NewInt(1), uint(2*k))
+		t.Sub(&den, big.NewInt(1))
+		den.Mul(&den, &t)
+		b[k] = new(big.Rat).SetFrac(&num, &den)
+	}
+	return b
+}
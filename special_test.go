@@ -0,0 +1,154 @@
+package decimal
+
+import "testing"
+
+func TestSpecial(t *testing.T) {
+	fns := map[string]func(Context, *Big, *Big) *Big{
+		"Erf":       Context.Erf,
+		"Erfc":      Context.Erfc,
+		"Factorial": Context.Factorial,
+		"Gamma":     Context.Gamma,
+		"LogGamma": func(c Context, z, x *Big) *Big {
+			z, _ = c.LogGamma(z, x)
+			return z
+		},
+		"Zeta": Context.Zeta,
+	}
+	for i, tc := range []struct {
+		fn   string
+		prec int
+		mode RoundingMode
+		x, r string
+		cond Condition
+	}{
+		{"Gamma", 16, ToNearestEven, "0.5", "1.772453850905516", Inexact | Rounded},
+		{"Gamma", 50, ToZero, "0.5", "1.7724538509055160272981674833411451827975494561223", Inexact | Rounded},
+		{"Gamma", 16, ToNearestEven, "-0.5", "-3.544907701811032", Inexact | Rounded},
+		{"Gamma", 34, ToNearestEven, "0.3333333333333333", "2.678938534707747913339892451111603", Inexact | Rounded},
+		{"Gamma", 16, ToNearestEven, "1E-30", "1.000000000000000E+30", Inexact | Rounded},
+		{"Gamma", 16, ToNearestEven, "171.5", "9.483367566824799E+307", Inexact | Rounded},
+		{"Gamma", 16, ToNearestEven, "5", "24", 0},
+		{"Gamma", 5, ToNearestEven, "20", "1.2165E+17", Inexact | Rounded},
+		{"Gamma", 16, ToNearestEven, "-0", "-Infinity", DivisionByZero},
+		{"Gamma", 16, ToNearestEven, "-2", "NaN", InvalidOperation},
+		{"Gamma", 16, ToNearestEven, "-Inf", "NaN", InvalidOperation},
+		{"LogGamma", 16, ToNearestEven, "0.5", "0.5723649429247001", Inexact | Rounded},
+		{"LogGamma", 16, ToNearestEven, "1.0000001", "-5.772155826548335E-8", Inexact | Rounded},
+		{"LogGamma", 16, ToNearestEven, "-0.5", "1.265512123484645", Inexact | Rounded},
+		{"LogGamma", 16, ToNearestEven, "2", "0", 0},
+		{"LogGamma", 16, ToNearestEven, "-3", "Infinity", DivisionByZero},
+		{"Factorial", 50, ToNearestEven, "20", "2432902008176640000", 0},
+		{"Factorial", 16, ToNearestEven, "20", "2.432902008176640E+18", Rounded},
+		{"Factorial", 16, ToNearestEven, "25", "1.551121004333099E+25", Inexact | Rounded},
+		{"Factorial", 16, ToNearestEven, "0", "1", 0},
+		{"Factorial", 16, ToNearestEven, "-1", "NaN", InvalidOperation},
+		{"Factorial", 16, ToNearestEven, "2.5", "NaN", InvalidOperation},
+		{"Erf", 16, ToNearestEven, "1", "0.8427007929497149", Inexact | Rounded},
+		{"Erf", 34, ToPositiveInf, "-2.5", "-0.9995930479825550410604357842600250", Inexact | Rounded},
+		{"Erf", 16, ToZero, "6", "0.9999999999999999", Inexact | Rounded},
+		{"Erf", 16, ToNearestEven, "-0", "-0", 0},
+		{"Erf", 16, ToNearestEven, "-Inf", "-1", 0},
+		{"Erfc", 16, ToNearestEven, "5", "1.537459794428035E-12", Inexact | Rounded},
+		{"Erfc", 34, ToNegativeInf, "12", "1.356261169205904212780306156590417E-64", Inexact | Rounded},
+		{"Erfc", 16, ToNearestEven, "30", "2.564656203756112E-393", Inexact | Rounded},
+		{"Erfc", 16, ToNearestEven, "0", "1", 0},
+		{"Erfc", 16, ToNearestEven, "-Inf", "2", 0},
+		{"Zeta", 16, ToNearestEven, "2", "1.644934066848226", Inexact | Rounded},
+		{"Zeta", 16, ToNearestEven, "3", "1.202056903159594", Inexact | Rounded},
+		{"Zeta", 16, ToNearestEven, "0.5", "-1.460354508809587", Inexact | Rounded},
+		{"Zeta", 16, ToNearestEven, "1.0001", "10000.57722294644", Inexact | Rounded},
+		{"Zeta", 25, ToNearestEven, "-3.5", "0.004441011335479431958534658", Inexact | Rounded},
+		{"Zeta", 16, ToNearestEven, "-3", "0.008333333333333333", Inexact | Rounded},
+		{"Zeta", 16, ToZero, "100", "1.000000000000000", Inexact | Rounded},
+		{"Zeta", 16, ToNearestEven, "0", "-0.5", 0},
+		{"Zeta", 16, ToNearestEven, "-2", "0", 0},
+		{"Zeta", 16, ToNearestEven, "1", "Infinity", DivisionByZero},
+		{"Zeta", 16, ToNearestEven, "-Inf", "NaN", InvalidOperation},
+	} {
+		x, _ := new(Big).SetString(tc.x)
+		ctx := Context{Precision: tc.prec, RoundingMode: tc.mode}
+		z := WithContext(ctx)
+		fns[tc.fn](ctx, z, x)
+		if !strEq(z, tc.r) || z.Context.Conditions != tc.cond {
+			t.Fatalf("#%d: %s(%s): wanted (%s, %s), got (%s, %s)",
+				i, tc.fn, tc.x, tc.r, tc.cond, z, z.Context.Conditions)
+		}
+		if tc.cond&InvalidOperation != 0 && z.Payload() == 0 {
+			t.Fatalf("#%d: %s(%s): missing NaN payload", i, tc.fn, tc.x)
+		}
+	}
+
+	ctx := Context{Precision: 16, MaxScale: 384, MinScale: -383}
+	for i, x := range []string{"300", "300.5"} {
+		z := WithContext(ctx)
+		if ctx.Gamma(z, decs(x)[0]); !z.IsInf(+1) || z.Context.Conditions&Overflow == 0 {
+			t.Fatalf("#%d: expected overflow, got (%s, %s)", i, z, z.Context.Conditions)
+		}
+	}
+	for i, x := range []string{"-300.5", "40"} {
+		fn := Context.Gamma
+		if i == 1 {
+			fn = Context.Erfc
+		}
+		z := WithContext(ctx)
+		if fn(ctx, z, decs(x)[0]); z.Sign() != 0 || z.Context.Conditions&Underflow == 0 {
+			t.Fatalf("#%d: expected underflow, got (%s, %s)", i, z, z.Context.Conditions)
+		}
+	}
+}
+
+func TestLogGamma_Sign(t *testing.T) {
+	for i, tc := range []struct {
+		x    string
+		sign int
+	}{
+		{"0.5", 1},
+		{"-0.5", -1},
+		{"-1.5", 1},
+		{"-2.5", -1},
+		{"3", 1},
+		{"-0", -1},
+		{"+0", 1},
+		{"Inf", 1},
+	} {
+		_, sign := Context{Precision: 16}.LogGamma(new(Big), decs(tc.x)[0])
+		if sign != tc.sign {
+			t.Fatalf("#%d: LogGamma(%s): wanted sign %d, got %d", i, tc.x, tc.sign, sign)
+		}
+	}
+}
+
+func TestBinomialBeta(t *testing.T) {
+	fns := map[string]func(Context, *Big, *Big, *Big) *Big{
+		"Beta":     Context.Beta,
+		"Binomial": Context.Binomial,
+	}
+	for i, tc := range []struct {
+		fn      string
+		prec    int
+		x, y, r string
+		cond    Condition
+	}{
+		{"Binomial", 16, "10", "3", "120", 0},
+		{"Binomial", 16, "-5", "3", "-35", 0},
+		{"Binomial", 16, "60", "30", "1.182645815648614E+17", Inexact | Rounded},
+		{"Binomial", 16, "5", "7", "0", 0},
+		{"Binomial", 16, "5", "-1", "0", 0},
+		{"Binomial", 16, "2.5", "1", "NaN", InvalidOperation},
+		{"Beta", 16, "2", "3", "0.08333333333333333", Inexact | Rounded},
+		{"Beta", 16, "0.5", "0.5", "3.141592653589793", Inexact | Rounded},
+		{"Beta", 16, "3", "0.5", "1.066666666666667", Inexact | Rounded},
+		{"Beta", 16, "1", "4", "0.25", 0},
+		{"Beta", 16, "2.5", "-3.5", "0", 0},
+		{"Beta", 16, "0", "1", "NaN", InvalidOperation},
+	} {
+		ctx := Context{Precision: tc.prec}
+		z := WithContext(ctx)
+		xy := decs(tc.x, tc.y)
+		fns[tc.fn](ctx, z, xy[0], xy[1])
+		if !strEq(z, tc.r) || z.Context.Conditions != tc.cond {
+			t.Fatalf("#%d: %s(%s, %s): wanted (%s, %s), got (%s, %s)",
+				i, tc.fn, tc.x, tc.y, tc.r, tc.cond, z, z.Context.Conditions)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Package bounds implements the DECIMAL(p, s) limit checks shared by the SQL
+// subpackages.
+package bounds
+
+import (
+	"fmt"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// Error is returned from Fit when either the integral or fractional part of a
+// decimal is too long. The SQL subpackages export it as LengthError.
+type Error struct {
+	Part string // "integral" or "fractional"
+	N    int    // length of invalid part
+	Max  int    // max length of the part
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s (%d digits) is too long (%d max)", e.Part, e.N, e.Max)
+}
+
+// Fit returns v with at most scale digits after the decimal point and at most
+// precision-scale digits before it. v must be finite and is not modified.
+//
+// If v has too many fractional digits, Fit rounds v using its RoundingMode if
+// round is true and returns an error otherwise. Too many integral digits,
+// including those created by rounding, are always an error. Zeros always fit.
+func Fit(v *decimal.Big, precision, scale int, round bool) (*decimal.Big, *Error) {
+	if v.Sign() == 0 {
+		switch sl := v.Scale(); {
+		case sl < 0:
+			return new(decimal.Big), nil
+		case sl > scale:
+			return decimal.New(0, scale), nil
+		}
+		return v, nil
+	}
+
+	if sl := v.Scale(); sl > scale {
+		if !round {
+			return nil, &Error{Part: "fractional", N: sl, Max: scale}
+		}
+		ctx := decimal.Context{
+			Precision:    decimal.UnlimitedPrecision,
+			RoundingMode: v.Context.RoundingMode,
+		}
+		v = ctx.Quantize(decimal.WithContext(ctx).Copy(v), scale)
+	}
+	if il := v.Precision() - v.Scale(); il > precision-scale {
+		return nil, &Error{Part: "integral", N: il, Max: precision - scale}
+	}
+	return v, nil
+}
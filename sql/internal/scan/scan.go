@@ -0,0 +1,39 @@
+// Package scan implements the sql.Scanner logic shared by the SQL
+// subpackages.
+package scan
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// Into sets v to val, which must be a string, []byte, int64, or float64. name
+// is the type whose Scan method called Into and is used in errors.
+//
+// Floating-point values are converted using the shortest decimal that
+// represents them, so 0.1 is scanned as 0.1 and not as
+// 0.1000000000000000055511151231257827021181583404541015625.
+func Into(name string, v *decimal.Big, val interface{}) error {
+	switch t := val.(type) {
+	case string:
+		if _, ok := v.SetString(t); !ok {
+			if err := v.Context.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("%s.Scan: invalid syntax: %q", name, t)
+		}
+		return nil
+	case []byte:
+		return v.UnmarshalText(t)
+	case int64:
+		v.SetMantScale(t, 0)
+		return nil
+	case float64:
+		v.SetString(strconv.FormatFloat(t, 'g', -1, 64))
+		return nil
+	default:
+		return fmt.Errorf("%s.Scan: unknown value: %#v", name, val)
+	}
+}
@@ -0,0 +1,153 @@
+// Package mssql provides simple wrappers around a decimal.Big type, allowing
+// it to be used in SQL Server queries. It ensures the decimal fits inside the
+// limits of the DECIMAL and MONEY types.
+package mssql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/ericlagergren/decimal/sql/internal/bounds"
+	"github.com/ericlagergren/decimal/sql/internal/scan"
+)
+
+const (
+	MaxPrecision = 38 // max total digits
+	MoneyScale   = 4  // digits after the decimal point of MONEY and SMALLMONEY
+)
+
+// LengthError is returned from Decimal.Value and Money.Value when either its
+// integral (digits before the decimal point) or fractional (digits after the
+// decimal point) parts are too long for the column.
+type LengthError = bounds.Error
+
+// Decimal is a SQL Server DECIMAL or NUMERIC. Its zero value is valid for use
+// with both Value and Scan.
+//
+// SQL Server does not support NaN or Infinity, so Value rejects them.
+type Decimal struct {
+	V     *decimal.Big
+	Round bool // round if V has more than Scale fractional digits
+	Zero  bool // return "0" if V == nil
+
+	// Precision and Scale are the p and s of a DECIMAL(p, s) column. If
+	// Precision is zero, it defaults to 38 and V is checked against
+	// DECIMAL(38, Scale).
+	Precision, Scale int
+}
+
+// Value implements driver.Valuer.
+//
+// Excess fractional digits are rounded using V's RoundingMode if Round is
+// true. (SQL Server itself rounds half away from zero, i.e. ToNearestAway.)
+// Otherwise, and if V has too many integral digits, Value returns a
+// *LengthError.
+//
+// The result never uses scientific notation, which SQL Server does not accept
+// when converting strings to DECIMAL.
+func (d *Decimal) Value() (driver.Value, error) {
+	if d.V == nil {
+		if d.Zero {
+			return "0", nil
+		}
+		return nil, nil
+	}
+	v := d.V
+	if err := checkSpecial("Decimal", "DECIMAL", v); err != nil {
+		return nil, err
+	}
+
+	p, s := d.Precision, d.Scale
+	if p == 0 {
+		p = MaxPrecision
+	}
+	if p < 1 || p > MaxPrecision || s < 0 || s > p {
+		return nil, fmt.Errorf("Decimal.Value: invalid column: DECIMAL(%d, %d)", p, s)
+	}
+
+	v, e := bounds.Fit(v, p, s, d.Round)
+	if e != nil {
+		return nil, e
+	}
+	return fmt.Sprintf("%f", v), nil
+}
+
+// Scan implements sql.Scanner.
+func (d *Decimal) Scan(val interface{}) error {
+	if d.V == nil {
+		d.V = new(decimal.Big)
+	}
+	return scan.Into("Decimal", d.V, val)
+}
+
+var (
+	moneyMin      = decimal.New(math.MinInt64, MoneyScale)
+	moneyMax      = decimal.New(math.MaxInt64, MoneyScale)
+	smallMoneyMin = decimal.New(math.MinInt32, MoneyScale)
+	smallMoneyMax = decimal.New(math.MaxInt32, MoneyScale)
+)
+
+// Money is a SQL Server MONEY or, if Small is true, SMALLMONEY. Its zero value
+// is valid for use with both Value and Scan.
+//
+// MONEY is a 64-bit integer and SMALLMONEY a 32-bit integer, each scaled by
+// 10**-4. Values outside of their ranges are always an error.
+type Money struct {
+	V     *decimal.Big
+	Round bool // round if V has more than 4 fractional digits
+	Zero  bool // return "0" if V == nil
+	Small bool // SMALLMONEY instead of MONEY
+}
+
+// Value implements driver.Valuer.
+//
+// Excess fractional digits are rounded using V's RoundingMode if Round is
+// true. Otherwise, Value returns a *LengthError.
+func (m *Money) Value() (driver.Value, error) {
+	if m.V == nil {
+		if m.Zero {
+			return "0", nil
+		}
+		return nil, nil
+	}
+	typ, min, max := "MONEY", moneyMin, moneyMax
+	if m.Small {
+		typ, min, max = "SMALLMONEY", smallMoneyMin, smallMoneyMax
+	}
+	v := m.V
+	if err := checkSpecial("Money", typ, v); err != nil {
+		return nil, err
+	}
+
+	// Check the fractional part first so that rounding can be taken into
+	// account by the range check.
+	v, e := bounds.Fit(v, MaxPrecision, MoneyScale, m.Round)
+	if e != nil && e.Part == "fractional" {
+		return nil, e
+	}
+	if e != nil || v.Cmp(min) < 0 || v.Cmp(max) > 0 {
+		return nil, fmt.Errorf("Money.Value: %s overflows %s", m.V, typ)
+	}
+	return fmt.Sprintf("%f", v), nil
+}
+
+// Scan implements sql.Scanner.
+func (m *Money) Scan(val interface{}) error {
+	if m.V == nil {
+		m.V = new(decimal.Big)
+	}
+	return scan.Into("Money", m.V, val)
+}
+
+// checkSpecial returns an error if v is a NaN or infinity.
+func checkSpecial(name, typ string, v *decimal.Big) error {
+	if v.IsNaN(0) {
+		return fmt.Errorf("%s.Value: %s does not accept NaN", name, typ)
+	}
+	if v.IsInf(0) {
+		return fmt.Errorf("%s.Value: %s does not accept Infinities", name, typ)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/ericlagergren/decimal"
+)
+
+func TestDecimal_Value(t *testing.T) {
+	for i, test := range [...]struct {
+		in    string
+		p, s  int
+		round bool
+		mode  decimal.RoundingMode
+		out   string
+		part  string // LengthError.Part, if any
+	}{
+		{in: "123.45", p: 5, s: 2, out: "123.45"},
+		{in: "1.5E+20", out: "150000000000000000000"},
+		{in: "-1.5E-4", s: 3, part: "fractional"},
+		{in: "-1.55E-2", s: 2, round: true, mode: decimal.ToNearestEven, out: "-0.02"},
+		{in: "0E-50", s: 4, out: "0.0000"},
+		{in: "12345", p: 5, out: "12345"},
+		{in: "123456", p: 5, part: "integral"},
+		{in: "99999999999999999999999999999999999999", out: "99999999999999999999999999999999999999"},
+		{in: "1E+38", part: "integral"},
+		{in: "99.5", p: 2, round: true, mode: decimal.ToNearestAway, part: "integral"},
+	} {
+		x, _ := new(decimal.Big).SetString(test.in)
+		x.Context.RoundingMode = test.mode
+		d := Decimal{V: x, Precision: test.p, Scale: test.s, Round: test.round}
+
+		v, err := d.Value()
+		if test.part != "" {
+			e, ok := err.(*LengthError)
+			if !ok || e.Part != test.part {
+				t.Fatalf("#%d: wanted a %s *LengthError, got (%v, %v)", i, test.part, v, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if v != test.out {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.out, v)
+		}
+	}
+
+	for i, d := range [...]Decimal{
+		{V: new(decimal.Big).SetNaN(false)},
+		{V: new(decimal.Big).SetInf(false)},
+		{V: decimal.New(1, 0), Precision: 39},
+		{V: decimal.New(1, 0), Precision: 10, Scale: 11},
+		{V: decimal.New(1, 0), Scale: -1},
+	} {
+		if v, err := d.Value(); err == nil {
+			t.Fatalf("#%d: expected an error, got %v", i, v)
+		}
+	}
+}
+
+func TestMoney_Value(t *testing.T) {
+	for i, test := range [...]struct {
+		in    string
+		small bool
+		round bool
+		out   string // "" means an error
+	}{
+		{in: "922337203685477.5807", out: "922337203685477.5807"},
+		{in: "-922337203685477.5808", out: "-922337203685477.5808"},
+		{in: "922337203685477.5808"},
+		{in: "1E+15"},
+		{in: "1.25", out: "1.25"},
+		{in: "1.23456"},
+		{in: "1.23456", round: true, out: "1.2346"},
+		{in: "922337203685477.58075", round: true},
+		{in: "214748.3647", small: true, out: "214748.3647"},
+		{in: "-214748.3648", small: true, out: "-214748.3648"},
+		{in: "214748.3648", small: true},
+		{in: "NaN"},
+		{in: "-Inf"},
+	} {
+		x, _ := new(decimal.Big).SetString(test.in)
+		m := Money{V: x, Small: test.small, Round: test.round}
+
+		v, err := m.Value()
+		if test.out == "" {
+			if err == nil {
+				t.Fatalf("#%d: expected an error, got %v", i, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if v != test.out {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.out, v)
+		}
+	}
+
+	_, err := (&Money{V: decimal.New(123456, 5)}).Value()
+	if e, ok := err.(*LengthError); !ok || e.Part != "fractional" {
+		t.Fatalf("wanted a fractional *LengthError, got %v", err)
+	}
+}
+
+func TestMoney_Scan(t *testing.T) {
+	for i, test := range [...]struct {
+		in  interface{}
+		out string
+	}{
+		{[]byte("12.3400"), "12.3400"},
+		{"-1", "-1"},
+		{int64(7), "7"},
+		{0.25, "0.25"},
+	} {
+		var m Money
+		if err := m.Scan(test.in); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if got := m.V.String(); got != test.out {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.out, got)
+		}
+	}
+	if err := new(Money).Scan(true); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,84 @@
+// Package mysql provides a simple wrapper around a decimal.Big type, allowing
+// it to be used in MySQL and MariaDB queries. It ensures the decimal fits
+// inside the limits of the DECIMAL type.
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/ericlagergren/decimal/sql/internal/bounds"
+	"github.com/ericlagergren/decimal/sql/internal/scan"
+)
+
+const (
+	MaxPrecision = 65 // max total digits
+	MaxScale     = 30 // max digits after the decimal point
+)
+
+// LengthError is returned from Decimal.Value when either its integral (digits
+// before the decimal point) or fractional (digits after the decimal point)
+// parts are too long for the column.
+type LengthError = bounds.Error
+
+// Decimal is a MySQL or MariaDB DECIMAL. Its zero value is valid for use with
+// both Value and Scan.
+//
+// Neither MySQL nor MariaDB support NaN or Infinity, so Value rejects them.
+type Decimal struct {
+	V     *decimal.Big
+	Round bool // round if V has more than Scale fractional digits
+	Zero  bool // return "0" if V == nil
+
+	// Precision and Scale are the M and D of a DECIMAL(M, D) column. If
+	// Precision is zero, V is checked against DECIMAL(65, 30), the largest
+	// DECIMAL column.
+	Precision, Scale int
+}
+
+// Value implements driver.Valuer.
+//
+// Excess fractional digits are rounded using V's RoundingMode if Round is
+// true. (MySQL itself rounds half away from zero, i.e. ToNearestAway.)
+// Otherwise, and if V has too many integral digits, Value returns a
+// *LengthError.
+func (d *Decimal) Value() (driver.Value, error) {
+	if d.V == nil {
+		if d.Zero {
+			return "0", nil
+		}
+		return nil, nil
+	}
+	v := d.V
+	if v.IsNaN(0) {
+		return nil, errors.New("Decimal.Value: DECIMAL does not accept NaN")
+	}
+	if v.IsInf(0) {
+		return nil, errors.New("Decimal.Value: DECIMAL does not accept Infinities")
+	}
+
+	p, s := d.Precision, d.Scale
+	if p == 0 {
+		p, s = MaxPrecision, MaxScale
+	}
+	if p < 1 || p > MaxPrecision || s < 0 || s > MaxScale || s > p {
+		return nil, fmt.Errorf("Decimal.Value: invalid column: DECIMAL(%d, %d)", p, s)
+	}
+
+	v, e := bounds.Fit(v, p, s, d.Round)
+	if e != nil {
+		return nil, e
+	}
+	return fmt.Sprintf("%f", v), nil
+}
+
+// Scan implements sql.Scanner. Floating-point values are converted using the
+// shortest decimal that represents them.
+func (d *Decimal) Scan(val interface{}) error {
+	if d.V == nil {
+		d.V = new(decimal.Big)
+	}
+	return scan.Into("Decimal", d.V, val)
+}
@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/ericlagergren/decimal"
+)
+
+func TestDecimal_Value(t *testing.T) {
+	for i, test := range [...]struct {
+		in    string
+		p, s  int
+		round bool
+		mode  decimal.RoundingMode
+		out   string
+		part  string // LengthError.Part, if any
+	}{
+		{in: "123.45", p: 5, s: 2, out: "123.45"},
+		{in: "1.5", p: 5, s: 2, out: "1.5"},
+		{in: "1E+2", p: 5, s: 2, out: "100"},
+		{in: "-0.000", p: 5, s: 2, out: "0.00"},
+		{in: "0E+10", p: 5, s: 2, out: "0"},
+		{in: "123.456", p: 5, s: 2, part: "fractional"},
+		{in: "123.455", p: 5, s: 2, round: true, mode: decimal.ToNearestAway, out: "123.46"},
+		{in: "123.455", p: 5, s: 2, round: true, mode: decimal.ToZero, out: "123.45"},
+		{in: "1000", p: 5, s: 2, part: "integral"},
+		{in: "999.995", p: 5, s: 2, round: true, mode: decimal.ToNearestAway, part: "integral"},
+		{in: "1E-30", out: "0.000000000000000000000000000001"},
+		{in: "1E-31", part: "fractional"},
+		{in: "99999999999999999999999999999999999", out: "99999999999999999999999999999999999"},
+		{in: "1E+35", part: "integral"},
+	} {
+		x, _ := new(decimal.Big).SetString(test.in)
+		x.Context.RoundingMode = test.mode
+		d := Decimal{V: x, Precision: test.p, Scale: test.s, Round: test.round}
+
+		v, err := d.Value()
+		if test.part != "" {
+			e, ok := err.(*LengthError)
+			if !ok || e.Part != test.part {
+				t.Fatalf("#%d: wanted a %s *LengthError, got (%v, %v)", i, test.part, v, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if v != test.out {
+			t.Fatalf("#%d: wanted %q, got %q", i, test.out, v)
+		}
+		if x.String() != test.in {
+			t.Fatalf("#%d: V was modified: %s", i, x)
+		}
+	}
+}
+
+func TestDecimal_ValueErrors(t *testing.T) {
+	for i, d := range [...]Decimal{
+		{V: new(decimal.Big).SetNaN(false)},
+		{V: new(decimal.Big).SetInf(true)},
+		{V: decimal.New(1, 0), Precision: 66},
+		{V: decimal.New(1, 0), Precision: 10, Scale: 31},
+		{V: decimal.New(1, 0), Precision: 10, Scale: 11},
+	} {
+		if v, err := d.Value(); err == nil {
+			t.Fatalf("#%d: expected an error, got %v", i, v)
+		}
+	}
+
+	if v, err := (&Decimal{}).Value(); v != nil || err != nil {
+		t.Fatalf("wanted (nil, nil), got (%v, %v)", v, err)
+	}
+	if v, err := (&Decimal{Zero: true}).Value(); v != "0" || err != nil {
+		t.Fatalf(`wanted ("0", nil), got (%v, %v)`, v, err)
+	}
+}
+
+func TestDecimal_Scan(t *testing.T) {
+	for i, test := range [...]struct {
+		in  interface{}
+		out string
+	}{
+		{"123.45", "123.45"},
+		{[]byte("-0.001"), "-0.001"},
+		{int64(-42), "-42"},
+		{0.1, "0.1"},
+		{1e300, "1E+300"},
+	} {
+		var d Decimal
+		if err := d.Scan(test.in); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if got := d.V.String(); got != test.out {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.out, got)
+		}
+	}
+
+	var d Decimal
+	for i, v := range [...]interface{}{true, nil, float32(1)} {
+		if err := d.Scan(v); err == nil {
+			t.Fatalf("#%d: expected an error", i)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ericlagergren/decimal"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Decimal)(nil)
+	_ encoding.BinaryUnmarshaler = (*Decimal)(nil)
+)
+
+// Sign values of the binary NUMERIC format.
+const (
+	numericPos  = 0x0000
+	numericNeg  = 0x4000
+	numericNaN  = 0xC000
+	numericPInf = 0xD000 // PostgreSQL 14 and later
+	numericNInf = 0xF000 // PostgreSQL 14 and later
+)
+
+// nbase is the base of each NUMERIC digit.
+const nbase = 10000
+
+// maxDScale is the largest display scale a NUMERIC can have.
+const maxDScale = 0x3FFF
+
+// MarshalBinary implements encoding.BinaryMarshaler. See AppendBinary for
+// more information.
+func (d *Decimal) MarshalBinary() ([]byte, error) {
+	return d.AppendBinary(nil)
+}
+
+// AppendBinary appends the PostgreSQL binary NUMERIC representation of d to b
+// and returns the extended buffer. It's the format PostgreSQL's numeric_send
+// produces and numeric_recv accepts:
+//
+//    ndigits int16  number of base-10000 digits
+//    weight  int16  weight of the first digit
+//    sign    uint16 0x0000, 0x4000 (negative), or 0xC000 (NaN)
+//    dscale  int16  number of decimal digits after the decimal point
+//    digits  [ndigits]int16
+//
+// d.V is rounded to fit d.Typmod, if it's valid. Otherwise, it's subject to
+// the same limits as Value. Infinities are rejected.
+//
+// If V == nil, AppendBinary encodes zero if Zero is true. Otherwise, it
+// returns nil and a nil error, which drivers conventionally treat as NULL.
+func (d *Decimal) AppendBinary(b []byte) ([]byte, error) {
+	v := d.V
+	if v == nil {
+		if !d.Zero {
+			return nil, nil
+		}
+		v = new(decimal.Big)
+	}
+	if v.IsNaN(0) {
+		return appendHeader(b, 0, 0, numericNaN, 0), nil
+	}
+	if v.IsInf(0) {
+		return nil, errors.New("Decimal.AppendBinary: DECIMAL does not accept Infinities")
+	}
+
+	v, err := d.apply(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dl := v.Precision()
+	sl := v.Scale()
+	if il := dl - sl; il > MaxIntegralDigits {
+		if !d.Round {
+			return nil, &LengthError{Part: "integral", N: il, Max: MaxIntegralDigits}
+		}
+		v = decimal.WithContext(v.Context).Copy(v).Round(MaxIntegralDigits)
+		sl = v.Scale()
+	}
+	if sl > MaxFractionalDigits {
+		if !d.Round {
+			return nil, &LengthError{Part: "fractional", N: sl, Max: MaxFractionalDigits}
+		}
+		ctx := decimal.Context{
+			Precision:    decimal.UnlimitedPrecision,
+			RoundingMode: v.Context.RoundingMode,
+		}
+		v = ctx.Quantize(decimal.WithContext(ctx).Copy(v), MaxFractionalDigits)
+		sl = MaxFractionalDigits
+	}
+
+	dscale := sl
+	if dscale < 0 {
+		dscale = 0
+	}
+	sign := numericPos
+	if v.Signbit() {
+		sign = numericNeg
+	}
+
+	_, _, coeff, exp := v.Decompose(nil)
+	digits := new(big.Int).SetBytes(coeff).String()
+	if digits == "0" {
+		return appendHeader(b, 0, 0, numericPos, dscale), nil
+	}
+
+	// Align the decimal point on a base-10000 digit boundary.
+	e := int(exp)
+	if r := ((e % 4) + 4) % 4; r != 0 {
+		digits += strings.Repeat("0", r)
+		e -= r
+	}
+	if r := len(digits) % 4; r != 0 {
+		digits = strings.Repeat("0", 4-r) + digits
+	}
+
+	// Trailing zero digits are implied by the weight and dscale.
+	for strings.HasSuffix(digits, "0000") {
+		digits = digits[:len(digits)-4]
+		e += 4
+	}
+
+	ndigits := len(digits) / 4
+	weight := e/4 + ndigits - 1
+	b = appendHeader(b, ndigits, weight, sign, dscale)
+	for i := 0; i < len(digits); i += 4 {
+		n, _ := strconv.Atoi(digits[i : i+4])
+		b = appendInt16(b, int16(n))
+	}
+	return b, nil
+}
+
+func appendHeader(b []byte, ndigits, weight, sign, dscale int) []byte {
+	b = appendInt16(b, int16(ndigits))
+	b = appendInt16(b, int16(weight))
+	b = appendInt16(b, int16(uint16(sign)))
+	return appendInt16(b, int16(dscale))
+}
+
+func appendInt16(b []byte, v int16) []byte {
+	return append(b, byte(uint16(v)>>8), byte(v))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It decodes the
+// PostgreSQL binary NUMERIC representation in data into d.V, allocating it if
+// necessary. See AppendBinary for a description of the format.
+//
+// PostgreSQL 14 and later may send infinities, which are decoded as such.
+func (d *Decimal) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("Decimal.UnmarshalBinary: data too short")
+	}
+	var (
+		ndigits = int(int16(binary.BigEndian.Uint16(data[0:2])))
+		weight  = int(int16(binary.BigEndian.Uint16(data[2:4])))
+		sign    = binary.BigEndian.Uint16(data[4:6])
+		dscale  = int(binary.BigEndian.Uint16(data[6:8]))
+	)
+	data = data[8:]
+	if ndigits < 0 || len(data) != 2*ndigits {
+		return fmt.Errorf("Decimal.UnmarshalBinary: invalid number of digits: %d", ndigits)
+	}
+	if dscale > maxDScale {
+		return fmt.Errorf("Decimal.UnmarshalBinary: invalid dscale: %d", dscale)
+	}
+
+	if d.V == nil {
+		d.V = new(decimal.Big)
+	}
+	switch sign {
+	case numericPos, numericNeg:
+	case numericNaN:
+		d.V.SetNaN(false)
+		return nil
+	case numericPInf, numericNInf:
+		d.V.SetInf(sign == numericNInf)
+		return nil
+	default:
+		return fmt.Errorf("Decimal.UnmarshalBinary: invalid sign: %#x", sign)
+	}
+
+	digits := make([]byte, 4*ndigits)
+	for i := 0; i < ndigits; i++ {
+		n := binary.BigEndian.Uint16(data[2*i:])
+		if n >= nbase {
+			return fmt.Errorf("Decimal.UnmarshalBinary: invalid digit: %d", n)
+		}
+		digits[4*i+0] = byte('0' + n/1000)
+		digits[4*i+1] = byte('0' + n/100%10)
+		digits[4*i+2] = byte('0' + n/10%10)
+		digits[4*i+3] = byte('0' + n%10)
+	}
+
+	// The value is digits × 10**exp. Convert it to a scale of dscale.
+	exp := 4 * (weight - ndigits + 1)
+	if n := -dscale - exp; n > 0 {
+		// digits has zero padding beyond dscale.
+		for ; n > 0 && len(digits) > 0 && digits[len(digits)-1] == '0'; n-- {
+			digits = digits[:len(digits)-1]
+			exp++
+		}
+	} else if n < 0 {
+		for ; n < 0; n++ {
+			digits = append(digits, '0')
+		}
+		exp = -dscale
+	}
+
+	var coeff big.Int
+	if len(digits) > 0 {
+		coeff.SetString(string(digits), 10)
+	}
+	if sign == numericNeg {
+		coeff.Neg(&coeff)
+	}
+	d.V.SetBigMantScale(&coeff, -exp)
+	return nil
+}
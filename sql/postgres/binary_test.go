@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/ericlagergren/decimal"
+)
+
+func TestDecimal_AppendBinary(t *testing.T) {
+	for i, test := range [...]struct {
+		in  string
+		hex string
+	}{
+		{"0", "0000000000000000"},
+		{"0.00", "0000000000000002"},
+		{"1", "00010000000000000001"},
+		{"1.50", "0002000000000002" + "00011388"},
+		{"12345.678", "0003000100000003" + "000109291a7c"},
+		{"-0.0001", "0001ffff40000004" + "0001"},
+		{"1E+8", "00010002000000000001"},
+		{"123456789E-20", "0003fffd00000014" + "000109291a85"},
+		{"NaN", "00000000c0000000"},
+	} {
+		x, _ := new(decimal.Big).SetString(test.in)
+		b, err := (&Decimal{V: x}).AppendBinary(nil)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if got := hex.EncodeToString(b); got != test.hex {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.hex, got)
+		}
+
+		raw, _ := hex.DecodeString(test.hex)
+		var d Decimal
+		if err := d.UnmarshalBinary(raw); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		// NUMERIC has no negative scales, so 1E+8 decodes as 100000000.
+		if d.V.Cmp(x) != 0 || (x.Scale() >= 0 && d.V.Scale() != x.Scale()) {
+			t.Fatalf("#%d: wanted %s, got %s", i, x, d.V)
+		}
+	}
+}
+
+func TestDecimal_BinaryRoundTrip(t *testing.T) {
+	n := 1000
+	if testing.Short() {
+		n = 100
+	}
+	for i := 0; i < n; i++ {
+		s := fmt.Sprintf("%s%sE%d", []string{"", "-"}[i%2], randInt(maxFrac)[:1+i%50], r.Intn(200)-100)
+		x, _ := new(decimal.Big).SetString(s)
+
+		b, err := (&Decimal{V: x}).MarshalBinary()
+		if err != nil {
+			t.Fatalf("#%d: %s: %v", i, s, err)
+		}
+		var d Decimal
+		if err := d.UnmarshalBinary(b); err != nil {
+			t.Fatalf("#%d: %s: %v", i, s, err)
+		}
+		if d.V.Cmp(x) != 0 || (x.Scale() >= 0 && d.V.Scale() != x.Scale()) {
+			t.Fatalf("#%d: wanted %s, got %s", i, x, d.V)
+		}
+		b2, _ := d.MarshalBinary()
+		if !bytes.Equal(b, b2) {
+			t.Fatalf("#%d: %s: wanted %x, got %x", i, s, b, b2)
+		}
+	}
+}
+
+func TestDecimal_UnmarshalBinaryErrors(t *testing.T) {
+	for i, s := range [...]string{
+		"",
+		"000000000000",
+		"0001000000000000",
+		"00010000000000002710", // digit 10000
+		"0000000012340000",     // bad sign
+		"000000000000ffff",     // bad dscale
+	} {
+		raw, _ := hex.DecodeString(s)
+		var d Decimal
+		if err := d.UnmarshalBinary(raw); err == nil {
+			t.Fatalf("#%d: expected an error", i)
+		}
+	}
+
+	var d Decimal
+	if err := d.UnmarshalBinary([]byte{0, 0, 0, 0, 0xd0, 0, 0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if !d.V.IsInf(+1) {
+		t.Fatalf("wanted +Inf, got %s", d.V)
+	}
+}
+
+func TestTypmod(t *testing.T) {
+	for i, test := range [...]struct {
+		p, s int
+	}{
+		{1, 0}, {5, 2}, {1000, 1000}, {10, -2}, {3, -1000},
+	} {
+		tm := Typmod(test.p, test.s)
+		p, s, ok := ParseTypmod(tm)
+		if !ok || p != test.p || s != test.s {
+			t.Fatalf("#%d: wanted (%d, %d), got (%d, %d, %t)", i, test.p, test.s, p, s, ok)
+		}
+	}
+	if got := Typmod(5, 2); got != 5<<16|2+4 {
+		t.Fatalf("wanted %d, got %d", 5<<16|2+4, got)
+	}
+	for _, tm := range [...]int32{-1, 0, 3, Typmod(0, 0), Typmod(1001, 0), Typmod(5, 1001)} {
+		if _, _, ok := ParseTypmod(tm); ok {
+			t.Fatalf("%d: expected an invalid typmod", tm)
+		}
+	}
+}
+
+func TestDecimal_Typmod(t *testing.T) {
+	for i, test := range [...]struct {
+		in   string
+		p, s int
+		mode decimal.RoundingMode
+		out  string // "" means an error
+	}{
+		{"123.456", 5, 2, decimal.ToNearestEven, "123.46"},
+		{"123.455", 5, 2, decimal.ToNearestEven, "123.46"},
+		{"123.445", 5, 2, decimal.ToNearestEven, "123.44"},
+		{"123.445", 5, 2, decimal.ToNearestAway, "123.45"},
+		{"123.449", 5, 2, decimal.ToZero, "123.44"},
+		{"1", 5, 2, decimal.ToNearestEven, "1.00"},
+		{"999.994", 5, 2, decimal.ToNearestEven, "999.99"},
+		{"999.995", 5, 2, decimal.ToNearestAway, ""},
+		{"1000", 5, 2, decimal.ToNearestEven, ""},
+		{"12345", 5, -2, decimal.ToNearestEven, "1.23E+4"},
+		{"0.001", 3, 3, decimal.ToNearestEven, "0.001"},
+		{"NaN", 3, 3, decimal.ToNearestEven, "NaN"},
+	} {
+		x, _ := new(decimal.Big).SetString(test.in)
+		x.Context.RoundingMode = test.mode
+		d := Decimal{V: x, Typmod: Typmod(test.p, test.s)}
+
+		v, err := d.Value()
+		if test.out == "" {
+			e, ok := err.(*LengthError)
+			if !ok || e.Part != "integral" || e.Max != test.p-test.s {
+				t.Fatalf("#%d: wanted an integral *LengthError, got (%v, %v)", i, v, err)
+			}
+			if _, err := d.AppendBinary(nil); err == nil {
+				t.Fatalf("#%d: AppendBinary: expected an error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if v != test.out {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.out, v)
+		}
+		if x.String() != test.in {
+			t.Fatalf("#%d: V was modified: %s", i, x)
+		}
+
+		b, err := d.AppendBinary(nil)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		var z Decimal
+		if err := z.UnmarshalBinary(b); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		want, _ := new(decimal.Big).SetString(test.out)
+		if z.V.Cmp(want) != 0 && !want.IsNaN(0) {
+			t.Fatalf("#%d: wanted %s, got %s", i, want, z.V)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"github.com/ericlagergren/decimal"
+	"github.com/ericlagergren/decimal/sql/internal/bounds"
 )
 
 const (
@@ -19,15 +20,7 @@ const (
 // LengthError is returned from Decimal.Value when either its integral (digits
 // before the decimal point) or fractional (digits after the decimal point)
 // parts are too long for PostgresSQL.
-type LengthError struct {
-	Part string // "integral" or "fractional"
-	N    int    // length of invalid part
-	max  int
-}
-
-func (e LengthError) Error() string {
-	return fmt.Sprintf("%s (%d digits) is too long (%d max)", e.Part, e.N, e.max)
-}
+type LengthError = bounds.Error
 
 // Decimal is a PostgreSQL DECIMAL. Its zero value is valid for use with both
 // Value and Scan.
@@ -35,6 +28,63 @@ type Decimal struct {
 	V     *decimal.Big
 	Round bool // round if the decimal exceeds the bounds for DECIMAL
 	Zero  bool // return "0" if V == nil
+
+	// Typmod is the type modifier of a numeric(p, s) column, as reported by
+	// PostgreSQL or created with Typmod. If it is valid, Value and
+	// AppendBinary round V to s fractional digits using V's RoundingMode and
+	// return a *LengthError if the result has more than p-s integral digits.
+	// (PostgreSQL itself rounds half away from zero, i.e. ToNearestAway.)
+	//
+	// Typmods less than 4, like the zero value and the -1 PostgreSQL uses for
+	// an unconstrained numeric, are ignored.
+	Typmod int32
+}
+
+// varhdrsz is the size of PostgreSQL's varlena header, which offsets every
+// numeric typmod.
+const varhdrsz = 4
+
+// Typmod returns the type modifier for numeric(precision, scale).
+//
+// The precision must be in the range [1, 1000] and the scale in the range
+// [-1000, 1000]. (Negative scales require PostgreSQL 15 or later.)
+func Typmod(precision, scale int) int32 {
+	return int32(precision<<16|scale&0x7ff) + varhdrsz
+}
+
+// ParseTypmod returns the precision and scale of the type modifier t and
+// reports whether t is a valid numeric typmod.
+func ParseTypmod(t int32) (precision, scale int, ok bool) {
+	if t < varhdrsz {
+		return 0, 0, false
+	}
+	t -= varhdrsz
+	precision = int(t >> 16 & 0xffff)
+	scale = int((t&0x7ff)^0x400) - 0x400 // sign extend the 11-bit scale
+	return precision, scale, precision >= 1 && precision <= 1000 &&
+		scale >= -1000 && scale <= 1000
+}
+
+// apply returns v rounded to fit d.Typmod. v is not modified.
+func (d *Decimal) apply(v *decimal.Big) (*decimal.Big, error) {
+	p, s, ok := ParseTypmod(d.Typmod)
+	if !ok || !v.IsFinite() {
+		return v, nil
+	}
+	ctx := decimal.Context{
+		Precision:    p,
+		RoundingMode: v.Context.RoundingMode,
+	}
+	z := decimal.WithContext(ctx).Copy(v)
+	if ctx.Quantize(z, s).IsNaN(0) {
+		// The integral part has too many digits, possibly after rounding.
+		il := v.Precision() - v.Scale()
+		if il <= p-s {
+			il = p - s + 1
+		}
+		return nil, &LengthError{Part: "integral", N: il, Max: p - s}
+	}
+	return z, nil
 }
 
 // Value implements driver.Valuer.
@@ -52,13 +102,20 @@ func (d *Decimal) Value() (driver.Value, error) {
 	if v.IsInf(0) {
 		return nil, errors.New("Decimal.Value: DECIMAL does not accept Infinities")
 	}
+	if _, _, ok := ParseTypmod(d.Typmod); ok {
+		v, err := d.apply(v)
+		if err != nil {
+			return nil, err
+		}
+		return v.String(), nil
+	}
 
 	dl := v.Precision()  // length of d
 	sl := int(v.Scale()) // length of fractional part
 
 	if il := dl - sl; il > MaxIntegralDigits {
 		if !d.Round {
-			return nil, &LengthError{Part: "integral", N: il, max: MaxIntegralDigits}
+			return nil, &LengthError{Part: "integral", N: il, Max: MaxIntegralDigits}
 		}
 		// Rounding down the integral part automatically chops off the fractional
 		// part.
@@ -66,7 +123,7 @@ func (d *Decimal) Value() (driver.Value, error) {
 	}
 	if sl > MaxFractionalDigits {
 		if !d.Round {
-			return nil, &LengthError{Part: "fractional", N: sl, max: MaxFractionalDigits}
+			return nil, &LengthError{Part: "fractional", N: sl, Max: MaxFractionalDigits}
 		}
 		v.Round(dl - (sl - MaxFractionalDigits))
 	}
@@ -41,12 +41,12 @@ func TestDecimal_Value(t *testing.T) {
 			}
 			switch e := err.(*LengthError); e.Part {
 			case "integral":
-				if len(ip) != e.N {
-					t.Fatalf("#%d: reported int len of %d, got %d", i, e.N, len(ip))
+				if len(ip) != e.N || e.Max != MaxIntegralDigits {
+					t.Fatalf("#%d: reported int len of %d (%d max), got %d", i, e.N, e.Max, len(ip))
 				}
 			case "fractional":
-				if len(fp) != e.N {
-					t.Fatalf("#%d: reported frac len of %d, got %d", i, e.N, len(fp))
+				if len(fp) != e.N || e.Max != MaxFractionalDigits {
+					t.Fatalf("#%d: reported frac len of %d (%d max), got %d", i, e.N, e.Max, len(fp))
 				}
 			default:
 				t.Fatalf("#%d: bad part: %q", i, e.Part)
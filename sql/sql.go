@@ -1,5 +1,12 @@
 // Package sql provides the ability to use Big decimals with SQL databases.
 //
-// Deprecated: With the addition of the "decimal" interface (see: https://golang.org/issue/30870),
-// none of these subpackages are needed. They may be removed in a future version.
+// Drivers that support the "decimal" interface (see: https://golang.org/issue/30870)
+// can use Big directly. The subpackages wrap a Big and additionally ensure it
+// fits inside the limits of each database's decimal types:
+//
+//    mssql     SQL Server DECIMAL, NUMERIC, MONEY, and SMALLMONEY
+//    mysql     MySQL and MariaDB DECIMAL
+//    postgres  PostgreSQL DECIMAL and NUMERIC
+//    sqlite    SQLite TEXT and REAL
+//
 package sql
@@ -0,0 +1,98 @@
+// Package sqlite provides a simple wrapper around a decimal.Big type, allowing
+// it to be used in SQLite queries.
+//
+// SQLite does not have a DECIMAL type. Decimals are stored either exactly, as
+// strings in a column with TEXT affinity, or approximately, as 64-bit
+// floating-point numbers in a column with REAL affinity. Note that columns
+// with NUMERIC or INTEGER affinity convert strings that look like numbers to
+// REALs, so exact decimals must be stored in TEXT columns. Since TEXT columns
+// compare as strings, ORDER BY and comparisons of the stored decimals are not
+// numeric.
+package sqlite
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/ericlagergren/decimal/sql/internal/scan"
+)
+
+// Affinity is the type affinity of a SQLite column.
+type Affinity uint8
+
+const (
+	Text Affinity = iota // exact: decimals are stored as strings
+	Real                 // approximate: decimals are stored as float64s
+)
+
+// Decimal is a decimal stored in a SQLite column. Its zero value is valid for
+// use with both Value and Scan.
+type Decimal struct {
+	V        *decimal.Big
+	Affinity Affinity // the column's affinity
+	Round    bool     // round if V cannot be stored exactly as a REAL
+	Zero     bool     // return "0" if V == nil
+}
+
+// Value implements driver.Valuer.
+//
+// If Affinity is Text, every value, including NaN and Infinity, is stored as
+// its string representation.
+//
+// If Affinity is Real, V is converted to the nearest float64. The conversion
+// is exact if scanning the float64 produces V again, which is always the case
+// if V has at most 15 significant digits and does not underflow. If Round is
+// false, inexact conversions are an error. Values outside the range of a
+// float64 are always an error. Infinities are stored as such, but NaN is
+// rejected because SQLite stores it as NULL.
+func (d *Decimal) Value() (driver.Value, error) {
+	if d.V == nil {
+		if d.Zero {
+			if d.Affinity == Real {
+				return float64(0), nil
+			}
+			return "0", nil
+		}
+		return nil, nil
+	}
+	v := d.V
+
+	switch d.Affinity {
+	case Text:
+		return v.String(), nil
+	case Real:
+	default:
+		return nil, fmt.Errorf("Decimal.Value: unknown affinity: %d", d.Affinity)
+	}
+
+	if v.IsNaN(0) {
+		return nil, errors.New("Decimal.Value: REAL does not accept NaN")
+	}
+	if v.IsInf(0) {
+		return math.Inf(v.Sign()), nil
+	}
+	f, _ := strconv.ParseFloat(v.String(), 64)
+	if math.IsInf(f, 0) {
+		return nil, fmt.Errorf("Decimal.Value: %s overflows REAL", v)
+	}
+	if !d.Round {
+		var z decimal.Big
+		if _, ok := z.SetString(strconv.FormatFloat(f, 'g', -1, 64)); !ok || z.Cmp(v) != 0 {
+			return nil, fmt.Errorf("Decimal.Value: %s cannot be stored exactly as a REAL", v)
+		}
+	}
+	return f, nil
+}
+
+// Scan implements sql.Scanner. Floating-point values are converted using the
+// shortest decimal that represents them.
+func (d *Decimal) Scan(val interface{}) error {
+	if d.V == nil {
+		d.V = new(decimal.Big)
+	}
+	return scan.Into("Decimal", d.V, val)
+}
@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ericlagergren/decimal"
+)
+
+func TestDecimal_Value(t *testing.T) {
+	for i, test := range [...]struct {
+		in    string
+		aff   Affinity
+		round bool
+		out   interface{} // nil means an error
+	}{
+		{"123.4500", Text, false, "123.4500"},
+		{"1E+1000", Text, false, "1E+1000"},
+		{"-Infinity", Text, false, "-Infinity"},
+		{"NaN", Text, false, "NaN"},
+		{"0.1", Real, false, 0.1},
+		{"-123456789012345", Real, false, -123456789012345.0},
+		{"1.7976931348623157E+308", Real, false, math.MaxFloat64},
+		{"0.12345678901234567890", Real, false, nil},
+		{"0.12345678901234567890", Real, true, 0.12345678901234568},
+		{"1E-400", Real, false, nil},
+		{"1E-400", Real, true, 0.0},
+		{"1E+400", Real, true, nil},
+		{"Inf", Real, false, math.Inf(+1)},
+		{"NaN", Real, true, nil},
+	} {
+		x, _ := new(decimal.Big).SetString(test.in)
+		d := Decimal{V: x, Affinity: test.aff, Round: test.round}
+
+		v, err := d.Value()
+		if test.out == nil {
+			if err == nil {
+				t.Fatalf("#%d: expected an error, got %v", i, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if v != test.out {
+			t.Fatalf("#%d: wanted %v, got %v", i, test.out, v)
+		}
+	}
+
+	if v, err := (&Decimal{Affinity: Real, Zero: true}).Value(); v != 0.0 || err != nil {
+		t.Fatalf("wanted (0, nil), got (%v, %v)", v, err)
+	}
+	if _, err := (&Decimal{V: decimal.New(1, 0), Affinity: 2}).Value(); err == nil {
+		t.Fatal("expected an error for an unknown affinity")
+	}
+}
+
+func TestDecimal_Scan(t *testing.T) {
+	for i, test := range [...]struct {
+		in  interface{}
+		out string
+	}{
+		{"123.4500", "123.4500"},
+		{[]byte("-Infinity"), "-Infinity"},
+		{int64(1) << 62, "4611686018427387904"},
+		{0.1, "0.1"},
+		{math.MaxFloat64, "1.7976931348623157E+308"},
+	} {
+		var d Decimal
+		if err := d.Scan(test.in); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if got := d.V.String(); got != test.out {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.out, got)
+		}
+	}
+	if err := new(Decimal).Scan(nil); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,266 @@
+package decimal
+
+// Sum sets z to the sum of xs and returns z.
+//
+// Unlike repeated calls to Add, which round after each addition,
+// the sum is computed exactly and rounded only once. The result is
+// therefore correctly rounded and independent of the order of xs.
+// The exact intermediate result may be large if the exponents of xs
+// are very far apart.
+//
+// If xs is empty, z is set to zero.
+func (c Context) Sum(z *Big, xs ...*Big) *Big {
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkSum(xs, summation) {
+		return z
+	}
+	t := getDec(c.exact())
+	t.sum(xs)
+	z.Context.Conditions |= t.Context.Conditions
+	c.Set(z, t)
+	putDec(t)
+	return z
+}
+
+// Dot sets z to the dot product of xs and ys, Σ xs[i] × ys[i],
+// and returns z.
+//
+// Like Sum, the dot product is computed exactly and rounded only
+// once, so the result is correctly rounded.
+//
+// If xs and ys have different lengths, z is set to NaN and
+// InvalidOperation is raised. If they are empty, z is set to zero.
+func (c Context) Dot(z *Big, xs, ys []*Big) *Big {
+	if z.invalidContext(c) {
+		return z
+	}
+	if len(xs) != len(ys) {
+		return z.setNaN(InvalidOperation, qnan, dotlen)
+	}
+
+	if i, ok := firstNaN(xs, ys); ok {
+		z.checkNaNs(i, i, dotproduct)
+		return z
+	}
+
+	e := c.exact()
+	t, p := getDec(e), getDec(e)
+	defer putDec(t)
+	defer putDec(p)
+
+	var pos, neg bool // infinite products
+	t.SetUint64(0)
+	for i, x := range xs {
+		y := ys[i]
+		if x.IsFinite() && y.IsFinite() {
+			if !pos && !neg {
+				t.accumulate(e.mul(p, x, y), p.form)
+			}
+			continue
+		}
+		if x.Sign() == 0 || y.Sign() == 0 {
+			// 0 × ±Inf
+			return z.setNaN(InvalidOperation, qnan, mul0inf)
+		}
+		if (x.form^y.form)&signbit != 0 {
+			neg = true
+		} else {
+			pos = true
+		}
+	}
+	if !pos && !neg {
+		z.Context.Conditions |= t.Context.Conditions
+		return c.Set(z, t)
+	}
+	return z.sumInf(pos, neg)
+}
+
+// Mean sets z to the arithmetic mean of xs and returns z.
+//
+// The mean is computed from the exact sum of xs, so the result is
+// correctly rounded.
+//
+// If xs is empty, z is set to NaN and InvalidOperation is raised.
+func (c Context) Mean(z *Big, xs ...*Big) *Big {
+	if z.invalidContext(c) {
+		return z
+	}
+	if len(xs) == 0 {
+		return z.setNaN(InvalidOperation, qnan, meanempty)
+	}
+	if z.checkSum(xs, mean) {
+		return z
+	}
+	t, n := getDec(c.exact()), getDec(c)
+	t.sum(xs)
+	z.Context.Conditions |= t.Context.Conditions
+	c.Quo(z, t, n.SetUint64(uint64(len(xs))))
+	putDec(t)
+	putDec(n)
+	return z
+}
+
+// Variance sets z to the population variance of xs,
+//
+//    Σ (xs[i] - mean)**2 / len(xs)
+//
+// and returns z. See SampleVariance for the sample variance.
+//
+// The variance is computed exactly and rounded only once, so the
+// result is correctly rounded.
+//
+// If xs is empty or contains an infinity, z is set to NaN and
+// InvalidOperation is raised.
+func (c Context) Variance(z *Big, xs ...*Big) *Big {
+	return c.variance(z, xs, 0)
+}
+
+// SampleVariance sets z to the sample variance of xs,
+//
+//    Σ (xs[i] - mean)**2 / (len(xs) - 1)
+//
+// and returns z.
+//
+// Like Variance, the result is correctly rounded. If xs has fewer
+// than two elements or contains an infinity, z is set to NaN and
+// InvalidOperation is raised.
+func (c Context) SampleVariance(z *Big, xs ...*Big) *Big {
+	return c.variance(z, xs, 1)
+}
+
+// variance sets z to the variance of xs with ddof delta degrees of
+// freedom.
+//
+// It uses
+//
+//    (n × Σ x**2 - (Σ x)**2) / (n × (n - ddof))
+//
+// which, since the numerator and denominator are exact, requires
+// only one rounding.
+func (c Context) variance(z *Big, xs []*Big, ddof int) *Big {
+	if z.invalidContext(c) {
+		return z
+	}
+	if i, ok := firstNaN(xs, nil); ok {
+		z.checkNaNs(i, i, variance)
+		return z
+	}
+	n := len(xs)
+	if n <= ddof {
+		return z.setNaN(InvalidOperation, qnan, varsize)
+	}
+	for _, x := range xs {
+		if x.IsInf(0) {
+			return z.setNaN(InvalidOperation, qnan, varinf)
+		}
+	}
+
+	e := c.exact()
+	s1, s2, p := getDec(e), getDec(e), getDec(e)
+	defer putDec(s1)
+	defer putDec(s2)
+	defer putDec(p)
+
+	s1.sum(xs)
+	s2.SetUint64(0)
+	for _, x := range xs {
+		s2.accumulate(e.mul(p, x, x), p.form)
+	}
+
+	// s2 = n × s2 - s1**2
+	e.mul(s2, s2, p.SetUint64(uint64(n)))
+	s2.accumulate(e.mul(p, s1, s1), p.form^signbit)
+	z.Context.Conditions |= s1.Context.Conditions | s2.Context.Conditions
+
+	// s1 = n × (n - ddof)
+	s1.SetUint64(uint64(n))
+	e.mul(s1, s1, p.SetUint64(uint64(n-ddof)))
+	return c.Quo(z, s2, s1)
+}
+
+// exact returns c with unlimited precision. It is used to compute
+// exact intermediate results.
+func (c Context) exact() Context {
+	c.Precision = UnlimitedPrecision
+	return c
+}
+
+// sum sets z to the exact sum of xs, which must be finite, and
+// returns z. z's Context must have unlimited precision.
+func (z *Big) sum(xs []*Big) *Big {
+	z.SetUint64(0)
+	for i, x := range xs {
+		if i == 0 {
+			z.Copy(x)
+			continue
+		}
+		z.accumulate(x, x.form)
+	}
+	return z
+}
+
+// accumulate sets z to z + y, where y has the sign in yform, and
+// returns z. z's Context must have unlimited precision.
+//
+// z and y may only be NaN if an exact intermediate result exceeded
+// the Context's MaxDigits or MaxMemory, in which case z is set to
+// NaN and the conditions are kept in z's Context.
+func (z *Big) accumulate(y *Big, yform form) *Big {
+	if z.IsNaN(0) {
+		return z
+	}
+	if y.IsNaN(0) {
+		z.Context.Conditions |= y.Context.Conditions
+		return z.setNaN(0, qnan, storage)
+	}
+	z.form = z.Context.add(z, z, z.form, y, yform)
+	return z
+}
+
+// checkSum handles NaNs and infinities in xs for a summation op
+// and reports whether z was set.
+func (z *Big) checkSum(xs []*Big, op Payload) bool {
+	if x, ok := firstNaN(xs, nil); ok {
+		return z.checkNaNs(x, x, op)
+	}
+	var pos, neg bool
+	for _, x := range xs {
+		pos = pos || x.IsInf(+1)
+		neg = neg || x.IsInf(-1)
+	}
+	if !pos && !neg {
+		return false
+	}
+	z.sumInf(pos, neg)
+	return true
+}
+
+// sumInf sets z to the sum of positive infinities if pos is true
+// and negative infinities if neg is true and returns z. At least one
+// of pos or neg must be true.
+func (z *Big) sumInf(pos, neg bool) *Big {
+	if pos && neg {
+		return z.setNaN(InvalidOperation, qnan, addinfinf)
+	}
+	return z.SetInf(neg)
+}
+
+// firstNaN returns the first signaling NaN in xs or ys or, if
+// there are none, the first quiet NaN, and reports whether either
+// was found.
+func firstNaN(xs, ys []*Big) (*Big, bool) {
+	var q *Big
+	for _, s := range [2][]*Big{xs, ys} {
+		for _, x := range s {
+			if x.form&snan != 0 {
+				return x, true
+			}
+			if q == nil && x.form&qnan != 0 {
+				q = x
+			}
+		}
+	}
+	return q, q != nil
+}
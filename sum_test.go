@@ -0,0 +1,164 @@
+package decimal
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// strEq reports whether x.String() == s, ignoring NaN payloads.
+func strEq(x *Big, s string) bool {
+	switch s {
+	case "NaN":
+		return x.IsNaN(+1) && !x.Signbit()
+	case "-NaN":
+		return x.IsNaN(+1) && x.Signbit()
+	}
+	return x.String() == s
+}
+
+func decs(s ...string) []*Big {
+	xs := make([]*Big, len(s))
+	for i, v := range s {
+		xs[i], _ = new(Big).SetString(v)
+	}
+	return xs
+}
+
+func TestContext_Sum(t *testing.T) {
+	for i, test := range [...]struct {
+		xs   []string
+		prec int
+		mode RoundingMode
+		out  string
+	}{
+		{nil, 16, ToNearestEven, "0"},
+		{[]string{"-1.5"}, 16, ToNearestEven, "-1.5"},
+		// Repeated addition would produce 1.0000 and 0E+5.
+		{[]string{"1.0000", "0.00004", "0.00004", "0.00004"}, 5, ToNearestEven, "1.0001"},
+		{[]string{"1E+20", "1", "-1E+20"}, 16, ToNearestEven, "1"},
+		{[]string{"0.1", "0.1", "0.1", "0.1", "0.1", "0.1", "0.1", "0.1", "0.1", "0.1"}, 16, ToNearestEven, "1.0"},
+		{[]string{"123.45", "-0.005", "99999.995"}, 7, ToZero, "100123.4"},
+		{[]string{"123.45", "-0.005", "99999.995"}, 7, ToPositiveInf, "100123.5"},
+		{[]string{"1.00", "-1.00"}, 16, ToNegativeInf, "-0.00"},
+		{[]string{"1.00", "-1.00"}, 16, ToNearestEven, "0.00"},
+		{[]string{"9999999999999999", "1"}, 16, ToNearestEven, "1.000000000000000E+16"},
+		{[]string{"1", "Inf", "2"}, 16, ToNearestEven, "Infinity"},
+		{[]string{"-Inf", "-Inf"}, 16, ToNearestEven, "-Infinity"},
+		{[]string{"Inf", "-Inf"}, 16, ToNearestEven, "NaN"},
+		{[]string{"1", "NaN", "sNaN"}, 16, ToNearestEven, "NaN"},
+	} {
+		ctx := Context{Precision: test.prec, RoundingMode: test.mode}
+		z := WithContext(ctx)
+		ctx.Sum(z, decs(test.xs...)...)
+		if !strEq(z, test.out) {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.out, z)
+		}
+	}
+
+	// The operands may alias z.
+	xs := decs("1", "2", "3")
+	Context128.Sum(xs[0], xs...)
+	if xs[0].String() != "6" {
+		t.Fatalf("wanted 6, got %s", xs[0])
+	}
+}
+
+func TestContext_SumRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	xs := make([]*Big, 1000)
+	for i := range xs {
+		xs[i] = New(r.Int63n(1e12)-5e11, r.Intn(6))
+	}
+
+	// The sum is order-independent and matches an exact sum.
+	var want Big
+	ctx := Context{Precision: UnlimitedPrecision}
+	for _, x := range xs {
+		ctx.Add(&want, &want, x)
+	}
+	Context32.Set(&want, &want)
+
+	for i := 0; i < 5; i++ {
+		r.Shuffle(len(xs), func(i, j int) { xs[i], xs[j] = xs[j], xs[i] })
+		z := WithContext(Context32)
+		if Context32.Sum(z, xs...); z.Cmp(&want) != 0 {
+			t.Fatalf("#%d: wanted %s, got %s", i, &want, z)
+		}
+	}
+}
+
+func TestContext_Dot(t *testing.T) {
+	for i, test := range [...]struct {
+		xs, ys []string
+		prec   int
+		out    string
+	}{
+		{nil, nil, 16, "0"},
+		{[]string{"1.5", "2.25", "-3", "1E+10"}, []string{"2", "-4", "0.5", "3E-10"}, 16, "-4.50"},
+		{[]string{"0.1", "0.1", "0.1"}, []string{"0.3", "0.3", "0.3"}, 2, "0.09"},
+		{[]string{"1", "Inf"}, []string{"2", "-2"}, 16, "-Infinity"},
+		{[]string{"Inf", "Inf"}, []string{"2", "-2"}, 16, "NaN"},
+		{[]string{"0", "Inf"}, []string{"Inf", "2"}, 16, "NaN"},
+		{[]string{"1", "2"}, []string{"NaN", "2"}, 16, "NaN"},
+		{[]string{"1", "2"}, []string{"1"}, 16, "NaN"},
+	} {
+		ctx := Context{Precision: test.prec}
+		z := WithContext(ctx)
+		ctx.Dot(z, decs(test.xs...), decs(test.ys...))
+		if !strEq(z, test.out) {
+			t.Fatalf("#%d: wanted %s, got %s", i, test.out, z)
+		}
+	}
+}
+
+func TestContext_MeanVariance(t *testing.T) {
+	for i, test := range [...]struct {
+		xs       []string
+		prec     int
+		mean     string
+		variance string
+		sample   string
+	}{
+		{[]string{"1", "2", "2"}, 16, "1.666666666666667", "0.2222222222222222", "0.3333333333333333"},
+		{[]string{"1", "2", "3", "4"}, 16, "2.5", "1.25", "1.666666666666667"},
+		{[]string{"10", "20", "30", "40"}, 16, "25", "125", "166.6666666666667"},
+		{[]string{"1E+30", "1", "-1E+30"}, 16, "0.3333333333333333", "6.666666666666667E+59", "1.000000000000000E+60"},
+		{[]string{"1.5", "2.5", "2.5", "2.75", "3.25", "4.75"}, 16, "2.875", "0.9739583333333333", "1.16875"},
+		{[]string{"100000000.1", "100000000.2", "100000000.3"}, 16, "100000000.2", "0.006666666666666667", "0.01"},
+		{[]string{"2.5", "3.5"}, 1, "3", "0.2", "0.5"},
+		{[]string{"5"}, 16, "5", "0", "NaN"},
+		{nil, 16, "NaN", "NaN", "NaN"},
+		{[]string{"1", "Inf"}, 16, "Infinity", "NaN", "NaN"},
+		{[]string{"1", "-NaN"}, 16, "-NaN", "-NaN", "-NaN"},
+	} {
+		ctx := Context{Precision: test.prec}
+		xs := decs(test.xs...)
+
+		z := WithContext(ctx)
+		if ctx.Mean(z, xs...); !strEq(z, test.mean) {
+			t.Fatalf("#%d: Mean: wanted %s, got %s", i, test.mean, z)
+		}
+		z = WithContext(ctx)
+		if ctx.Variance(z, xs...); !strEq(z, test.variance) {
+			t.Fatalf("#%d: Variance: wanted %s, got %s", i, test.variance, z)
+		}
+		z = WithContext(ctx)
+		if ctx.SampleVariance(z, xs...); !strEq(z, test.sample) {
+			t.Fatalf("#%d: SampleVariance: wanted %s, got %s", i, test.sample, z)
+		}
+	}
+}
+
+func BenchmarkContext_Sum(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	xs := make([]*Big, 1e4)
+	for i := range xs {
+		xs[i] = New(r.Int63n(1e12), 2)
+	}
+	z := WithContext(Context128)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Context128.Sum(z, xs...)
+	}
+}
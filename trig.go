@@ -0,0 +1,563 @@
+package decimal
+
+import (
+	"math"
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ericlagergren/decimal/internal/arith"
+	cst "github.com/ericlagergren/decimal/internal/c"
+)
+
+// trigFunc is a trigonometric function computed by Context.trig.
+type trigFunc uint8
+
+const (
+	trigSin trigFunc = iota
+	trigCos
+	trigTan
+	trigSec
+	trigCsc
+	trigCot
+)
+
+// odd reports whether f(-x) = -f(x).
+func (f trigFunc) odd() bool {
+	return f == trigSin || f == trigTan || f == trigCsc || f == trigCot
+}
+
+// trigUnit is the unit of the argument of a trigonometric function.
+// Its value is the length of a full turn in that unit, except for
+// radians, whose turn is not a decimal.
+type trigUnit int64
+
+const (
+	radians   trigUnit = 0
+	halfTurns trigUnit = 2   // multiples of pi
+	degrees   trigUnit = 360 // degrees
+)
+
+// SinPi sets z to the sine of pi×x and returns z.
+//
+// The result is exact if x is a multiple of 1/2. In particular,
+// SinPi(n) is zero with the sign of n for any integer n. Otherwise,
+// it is correctly rounded, no matter how large x is.
+//
+// Special cases:
+//     SinPi(NaN)  = NaN
+//     SinPi(±Inf) = NaN
+//     SinPi(±0)   = ±0
+func (c Context) SinPi(z, x *Big) *Big {
+	return c.trig(z, x, trigSin, halfTurns, sin)
+}
+
+// CosPi sets z to the cosine of pi×x and returns z.
+//
+// The result is exact if x is a multiple of 1/2. In particular,
+// CosPi(n + 1/2) is +0 for any integer n. Otherwise, it is
+// correctly rounded, no matter how large x is.
+//
+// Special cases:
+//     CosPi(NaN)  = NaN
+//     CosPi(±Inf) = NaN
+//     CosPi(±0)   = 1
+func (c Context) CosPi(z, x *Big) *Big {
+	return c.trig(z, x, trigCos, halfTurns, cos)
+}
+
+// TanPi sets z to the tangent of pi×x and returns z.
+//
+// The result is exact if x is a multiple of 1/4. At the poles,
+// TanPi(n + 1/2) is +Inf for even n and -Inf for odd n, and
+// DivisionByZero is raised. Otherwise, the result is correctly
+// rounded, no matter how large x is.
+//
+// Special cases:
+//     TanPi(NaN)  = NaN
+//     TanPi(±Inf) = NaN
+//     TanPi(±0)   = ±0
+func (c Context) TanPi(z, x *Big) *Big {
+	return c.trig(z, x, trigTan, halfTurns, tan)
+}
+
+// SinDeg sets z to the sine of x degrees and returns z.
+//
+// The result is exact if x is a multiple of 30. For example,
+// SinDeg(180) = 0 and SinDeg(210) = -0.5. Otherwise, it is
+// correctly rounded, no matter how large x is.
+//
+// Special cases:
+//     SinDeg(NaN)  = NaN
+//     SinDeg(±Inf) = NaN
+//     SinDeg(±0)   = ±0
+func (c Context) SinDeg(z, x *Big) *Big {
+	return c.trig(z, x, trigSin, degrees, sin)
+}
+
+// CosDeg sets z to the cosine of x degrees and returns z.
+//
+// The result is exact if x is a multiple of 30. For example,
+// CosDeg(90) = 0 and CosDeg(120) = -0.5. Otherwise, it is correctly
+// rounded, no matter how large x is.
+//
+// Special cases:
+//     CosDeg(NaN)  = NaN
+//     CosDeg(±Inf) = NaN
+//     CosDeg(±0)   = 1
+func (c Context) CosDeg(z, x *Big) *Big {
+	return c.trig(z, x, trigCos, degrees, cos)
+}
+
+// TanDeg sets z to the tangent of x degrees and returns z.
+//
+// The result is exact if x is a multiple of 45. At the poles,
+// TanDeg(90 + 180n) is +Inf for even n and -Inf for odd n, and
+// DivisionByZero is raised. Otherwise, the result is correctly
+// rounded, no matter how large x is.
+//
+// Special cases:
+//     TanDeg(NaN)  = NaN
+//     TanDeg(±Inf) = NaN
+//     TanDeg(±0)   = ±0
+func (c Context) TanDeg(z, x *Big) *Big {
+	return c.trig(z, x, trigTan, degrees, tan)
+}
+
+// Sec sets z to the secant, in radians, of x and returns z.
+//
+// Special cases:
+//     Sec(NaN)  = NaN
+//     Sec(±Inf) = NaN
+//     Sec(±0)   = 1
+func (c Context) Sec(z, x *Big) *Big {
+	return c.trig(z, x, trigSec, radians, sec)
+}
+
+// Csc sets z to the cosecant, in radians, of x and returns z.
+//
+// Special cases:
+//     Csc(NaN)  = NaN
+//     Csc(±Inf) = NaN
+//     Csc(±0)   = ±Inf
+func (c Context) Csc(z, x *Big) *Big {
+	return c.trig(z, x, trigCsc, radians, csc)
+}
+
+// Cot sets z to the cotangent, in radians, of x and returns z.
+//
+// Special cases:
+//     Cot(NaN)  = NaN
+//     Cot(±Inf) = NaN
+//     Cot(±0)   = ±Inf
+func (c Context) Cot(z, x *Big) *Big {
+	return c.trig(z, x, trigCot, radians, cot)
+}
+
+// trig sets z to fn(x), where x is measured in unit, and returns z.
+// op is the payload for NaN operands.
+func (c Context) trig(z, x *Big, fn trigFunc, unit trigUnit, op Payload) *Big {
+	if debug {
+		x.validate()
+	}
+	if z.invalidContext(c) {
+		return z
+	}
+	if z.checkNaNs(x, x, op) {
+		return z
+	}
+
+	if x.IsInf(0) {
+		return z.setNaN(InvalidOperation, qnan, triginf)
+	}
+	if x.isZero() {
+		switch fn {
+		case trigCos, trigSec:
+			return z.SetMantScale(1, 0)
+		case trigCsc, trigCot:
+			z.Context.Conditions |= DivisionByZero
+			return z.SetInf(x.Signbit())
+		default:
+			return z.setZero(x.form&signbit, 0)
+		}
+	}
+
+	if unit != radians {
+		var r Big
+		q := reduceExact(&r, x, int64(unit))
+		if trigExact(z, fn, q, &r, x, unit) {
+			return c.finish(z)
+		}
+		c.correctlyRound(z, 0, func(ctx Context, t *Big) *Big {
+			// Convert r to radians.
+			ctx.Mul(t, &r, ctx.pi(t))
+			if unit == degrees {
+				ctx.Quo(t, t, New(180, 0))
+			}
+			return ctx.trigQuadrant(t, t, q, fn)
+		})
+		z.checkDone(c)
+		return z
+	}
+
+	// Small arguments only need the sign of the remaining terms of
+	// the Taylor series.
+	adj := x.adjusted()
+	switch fn {
+	case trigSin:
+		// Sin(x) = x - x**3/6 + ...
+		if c.perturb(z, x, 3*(adj+1), x.Signbit()) {
+			return z
+		}
+	case trigTan:
+		// Tan(x) = x + x**3/3 + ...
+		if c.perturb(z, x, 3*(adj+1), !x.Signbit()) {
+			return z
+		}
+	case trigCos:
+		// Cos(x) = 1 - x**2/2 + ...
+		if c.perturb(z, one.get(), 2*(adj+1), false) {
+			return z
+		}
+	case trigSec:
+		// Sec(x) = 1 + x**2/2 + ...
+		if c.perturb(z, one.get(), 2*(adj+1), true) {
+			return z
+		}
+	}
+
+	// The reduction needs as many digits of 2/pi as the integer
+	// part of x has, plus the precision.
+	if x.adjusted() >= 0 && c.reduceTooLarge(int64(x.exp)+int64(x.Precision())+int64(c.precision())) {
+		return z.setNaN(InsufficientStorage, qnan, storage)
+	}
+
+	var ax Big
+	ax.CopyAbs(x)
+	neg := x.Signbit() && fn.odd()
+	c.correctlyRound(z, 0, func(ctx Context, t *Big) *Big {
+		q := ctx.reduce(t, &ax)
+		if ctx.trigQuadrant(t, t, q, fn); neg {
+			t.CopyNeg(t)
+		}
+		return t
+	})
+	z.checkDone(c)
+	return z
+}
+
+// trigQuadrant sets z to fn(q×pi/2 + r), where r is in radians and
+// |r| <= pi/4, and returns z.
+func (c Context) trigQuadrant(z, r *Big, q int, fn trigFunc) *Big {
+	// With |r| <= pi/4, cos(r) = sqrt(1 - sin(r)**2) >= 1/sqrt(2)
+	// does not lose any digits to cancellation.
+	var s, co Big
+	c.sinSeries(&s, r)
+	c.Mul(&co, &s, &s)
+	c.Sub(&co, one.get(), &co)
+	c.Sqrt(&co, &co)
+
+	// sin(q×pi/2 + r) is s, co, -s, -co for q = 0, 1, 2, 3 and
+	// cos(q×pi/2 + r) = sin((q+1)×pi/2 + r).
+	sinq := func(q int) *Big {
+		v := new(Big)
+		if q%2 == 0 {
+			v.Copy(&s)
+		} else {
+			v.Copy(&co)
+		}
+		if q%4 >= 2 {
+			v.CopyNeg(v)
+		}
+		return v
+	}
+	switch fn {
+	case trigSin:
+		return z.Copy(sinq(q))
+	case trigCos:
+		return z.Copy(sinq(q + 1))
+	case trigTan:
+		return c.Quo(z, sinq(q), sinq(q+1))
+	case trigSec:
+		return c.Quo(z, one.get(), sinq(q+1))
+	case trigCsc:
+		return c.Quo(z, one.get(), sinq(q))
+	default:
+		return c.Quo(z, sinq(q+1), sinq(q))
+	}
+}
+
+// trigExact sets z to fn(x) if it is 0, ±1/2, ±1, or a pole, and
+// reports whether it did so. x is measured in unit, which is not
+// radians, and q and r are the results of reduceExact.
+func trigExact(z *Big, fn trigFunc, q int, r, x *Big, unit trigUnit) bool {
+	// Only sine, cosine, and tangent are computed in units other
+	// than radians.
+	var eighth, thirty Big
+	eighth.SetMantScale(int64(unit)*125, 3)
+	thirty.SetMantScale(30, 0)
+
+	// sinq sets z to sin(q×pi/2 + r) if it is exact.
+	sinq := func(q int) bool {
+		switch {
+		case r.isZero() && q%2 == 0:
+			// The sign of a zero follows IEEE 754's sinPi and cosPi.
+			z.setZero(0, 0)
+			if fn == trigSin {
+				z.SetSignbit(x.Signbit())
+			}
+		case r.isZero():
+			z.SetMantScale(1, 0)
+		case unit == degrees && q%2 == 0 && r.CmpAbs(&thirty) == 0:
+			// sin(±30°) = ±1/2
+			z.SetMantScale(5, 1).SetSignbit(r.Signbit())
+		default:
+			return false
+		}
+		if q%4 >= 2 && !z.isZero() {
+			z.CopyNeg(z)
+		}
+		return true
+	}
+	switch fn {
+	case trigSin:
+		return sinq(q)
+	case trigCos:
+		return sinq(q + 1)
+	}
+
+	switch {
+	case r.isZero() && q%2 == 0:
+		// tan(n×pi) = ±0, where the sign of the zero is the sign of
+		// x if n is even and the opposite sign if n is odd.
+		z.setZero(0, 0)
+		z.SetSignbit(x.Signbit() != (q == 2))
+	case r.isZero():
+		z.Context.Conditions |= DivisionByZero
+		z.SetInf(q == 3)
+	case r.CmpAbs(&eighth) == 0:
+		// tan(±pi/4) = ±1 and tan(pi/2 ± pi/4) = ∓1.
+		z.SetMantScale(1, 0).SetSignbit(r.Signbit() != (q%2 != 0))
+	default:
+		return false
+	}
+	return true
+}
+
+// reduceExact sets r to x - q×turn/4 and returns q mod 4, where q
+// is the integer nearest to x/(turn/4). The result is exact and
+// |r| <= turn/8.
+func reduceExact(r, x *Big, turn int64) int {
+	exact := Context{Precision: UnlimitedPrecision}
+	if x.exp >= 0 {
+		// x = m × 10**e, so x mod turn = m × (10**e mod turn) mod
+		// turn, which avoids computing all the digits of x.
+		m := x.coeff(x.Precision())
+		n := big.NewInt(turn)
+		e := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(x.exp)), n)
+		m.Mul(m, e).Mod(m, n)
+		r.setCoeff(m, x.form&signbit, 0)
+	} else {
+		exact.Rem(r, x, New(turn, 0))
+	}
+
+	var quarter, eighth, t Big
+	quarter.SetMantScale(turn*25, 2)
+	eighth.SetMantScale(turn*125, 3)
+
+	// |r| < turn, so q is in [-4, 4].
+	rf, _ := r.Float64()
+	q := int(math.Round(rf * 4 / float64(turn)))
+	exact.Sub(r, r, exact.Mul(&t, &quarter, New(int64(q), 0)))
+	for r.Cmp(&eighth) > 0 {
+		exact.Sub(r, r, &quarter)
+		q++
+	}
+	for r.CmpAbs(&eighth) > 0 {
+		exact.Add(r, r, &quarter)
+		q--
+	}
+	return (q%4 + 4) % 4
+}
+
+// reduce sets z to x - k×pi/2, where k is the integer nearest to
+// x×2/pi, and returns k mod 4. x must be positive and finite.
+//
+// The reduction is in the style of Payne and Hanek: only the digits
+// of 2/pi that affect x×2/pi mod 4 are multiplied by x, so the
+// result is accurate relative to its own magnitude, however large x
+// is and however close it is to a multiple of pi/2.
+func (c Context) reduce(z, x *Big) int {
+	if xf, _ := x.Float64(); xf < 0.78 {
+		// x < pi/4
+		z.Copy(x)
+		return 0
+	}
+
+	prec := c.precision()
+	m := x.coeff(x.Precision())
+	d := x.Precision()
+	e := x.exp
+
+	var t, k, f big.Int
+	for extra := 10 + arith.Length(uint64(prec)); ; {
+		// x×2/pi = m × 10**e × Σ t_i × 10**-i, where t_i is the ith
+		// digit of 2/pi. The terms with i <= e-2 are multiples of 100,
+		// and so of 4, and the terms with i > hi are smaller than
+		// 10**-(prec+extra).
+		hi := e + d + prec + extra
+		twoOverPi(&t, hi)
+		if lo := e - 1; lo > 1 {
+			t.Mod(&t, arith.BigPow10(uint64(hi-lo+1)))
+		}
+		t.Mul(&t, m)
+
+		// x×2/pi = k + f/10**s with |f/10**s| <= 1/2.
+		s := hi - e
+		pow := arith.BigPow10(uint64(s))
+		k.QuoRem(&t, pow, &f)
+		if f.Lsh(&f, 1).Cmp(pow) >= 0 {
+			k.Add(&k, cst.OneInt)
+			f.Sub(&f, pow)
+			f.Sub(&f, pow)
+		}
+		f.Rsh(&f, 1)
+
+		// The error in f/10**s is less than 10**-(prec+extra), so if f
+		// has too few digits it must be recomputed with more digits of
+		// 2/pi.
+		if f.Sign() == 0 {
+			extra *= 2
+			continue
+		}
+		if lost := s - arith.BigLength(&f); lost > extra-3 {
+			extra = lost + 10
+			continue
+		}
+
+		var pi2 Big
+		Context{Precision: prec + 3}.pi2(&pi2)
+		z.SetBigMantScale(&f, s)
+		c.Mul(z, z, &pi2)
+		return int(k.Bit(1)<<1 | k.Bit(0))
+	}
+}
+
+// maxTwoOverPi is the largest number of digits of 2/pi that the
+// argument reduction computes unless the Context's MaxDigits or
+// MaxMemory allow more. Computing it takes a few seconds.
+const maxTwoOverPi = 100000
+
+// reduceTooLarge reports whether n digits of 2/pi are more than
+// the argument reduction may use under c.
+func (c Context) reduceTooLarge(n int64) bool {
+	if c.maxDigits() > 0 {
+		return c.tooLarge(n)
+	}
+	return n > maxTwoOverPi
+}
+
+// twoOverPiDigits holds the leading digits of 2/pi.
+type twoOverPiDigits struct {
+	n int     // number of digits
+	d big.Int // floor(2/pi × 10**n)
+}
+
+var (
+	twoOverPiMu    sync.Mutex   // protects writes to twoOverPiCache
+	twoOverPiCache atomic.Value // *twoOverPiDigits
+)
+
+// twoOverPi sets z to floor(2/pi × 10**n) and returns z.
+func twoOverPi(z *big.Int, n int) *big.Int {
+	tc, _ := twoOverPiCache.Load().(*twoOverPiDigits)
+	if tc == nil || n > tc.n {
+		// Grow geometrically so that larger and larger arguments
+		// do not recompute pi each time, but not past the default
+		// limit unless n itself is larger.
+		m := constPrec
+		if tc != nil {
+			m = max(m, 2*tc.n)
+		}
+		m = max(min(m, maxTwoOverPi), n)
+
+		// Compute the digits without holding the lock so that a
+		// huge argument doesn't block every other reduction, then
+		// publish them unless another goroutine published more
+		// digits in the meantime.
+		ctx := Context{Precision: m + 10}
+		var t Big
+		ctx.Quo(&t, two.get(), ctx.pi(&t))
+		t.exp += m
+		tc = &twoOverPiDigits{n: m}
+		t.Int(&tc.d)
+
+		twoOverPiMu.Lock()
+		if old, _ := twoOverPiCache.Load().(*twoOverPiDigits); old == nil || old.n < m {
+			twoOverPiCache.Store(tc)
+		}
+		twoOverPiMu.Unlock()
+	}
+	return z.Quo(&tc.d, arith.BigPow10(uint64(tc.n-n)))
+}
+
+// sinPi sets z to sin(pi×x) for a finite x and returns z. Unlike
+// Sin, the result is accurate relative to its magnitude even if x
+// is close to an integer.
+func (c Context) sinPi(z, x *Big) *Big {
+	var r Big
+	q := reduceExact(&r, x, int64(halfTurns))
+	if r.isZero() && q%2 == 0 {
+		return z.setZero(0, 0)
+	}
+	c.Mul(&r, &r, c.pi(z))
+	return c.trigQuadrant(z, &r, q, trigSin)
+}
+
+// sinSeries sets z to sin(y) for |y| <= pi/2 and returns z.
+func (c Context) sinSeries(z, y *Big) *Big {
+	if y.isZero() {
+		return z.setZero(y.form&signbit, 0)
+	}
+
+	// sin(y) = Σ_{k=0}^{∞} (-1)**k × y**(2k+1) / (2k+1)!
+	var y0, y2, q Big
+	y0.Copy(y)
+	c.Mul(&y2, y, y)
+	y2.CopyNeg(&y2)
+	yf, _ := y.Float64()
+	return BinarySplit(z, c, 0, sinTerms(yf, c.Precision),
+		func(_ uint64) *Big { return one.get() },
+		func(n uint64) *Big {
+			if n == 0 {
+				return &y0
+			}
+			return &y2
+		},
+		func(_ uint64) *Big { return one.get() },
+		func(n uint64) *Big {
+			if n == 0 {
+				return one.get()
+			}
+			return q.SetUint64(2 * n * (2*n + 1))
+		},
+	)
+}
+
+// sinTerms returns the number of terms of the Taylor series of
+// sin(y), |y| <= pi/2, needed to compute it to prec digits.
+func sinTerms(y float64, prec int) uint64 {
+	// The series alternates, so its error is less than the first
+	// omitted term.
+	lr := math.Log(y * y)
+	lim := float64(prec)*math.Ln10 + 1
+	var lt float64
+	n := uint64(1)
+	for ; ; n++ {
+		lt += lr - math.Log(float64(2*n*(2*n+1)))
+		if lt < -lim {
+			break
+		}
+	}
+	return n + 1
+}
@@ -0,0 +1,98 @@
+package decimal
+
+import "testing"
+
+func TestTrig(t *testing.T) {
+	fns := map[string]func(Context, *Big, *Big) *Big{
+		"Cos":    Context.Cos,
+		"CosDeg": Context.CosDeg,
+		"CosPi":  Context.CosPi,
+		"Cot":    Context.Cot,
+		"Csc":    Context.Csc,
+		"Sec":    Context.Sec,
+		"Sin":    Context.Sin,
+		"SinDeg": Context.SinDeg,
+		"SinPi":  Context.SinPi,
+		"Tan":    Context.Tan,
+		"TanDeg": Context.TanDeg,
+		"TanPi":  Context.TanPi,
+	}
+	for i, tc := range []struct {
+		fn   string
+		prec int
+		mode RoundingMode
+		x, r string
+		cond Condition
+	}{
+		{"Sin", 34, ToNearestEven, "1E+22", "-0.8522008497671888017727058937530294", Inexact | Rounded},
+		{"Sin", 16, ToNearestEven, "1E+100", "-0.3723761236612767", Inexact | Rounded},
+		{"Sin", 16, ToNearestEven, "-1E+10000", "0.5207937456157552", Inexact | Rounded},
+		{"Sin", 16, ToNearestEven, "355", "-0.00003014435335948845", Inexact | Rounded},
+		{"Sin", 10, ToZero, "3.141592653589793238462643383279502884197", "1.693993751E-40", Inexact | Rounded},
+		{"Sin", 10, ToPositiveInf, "3.141592653589793238462643383279502884197", "1.693993752E-40", Inexact | Rounded},
+		{"Sin", 16, ToNearestEven, "-0", "-0", 0},
+		{"Cos", 34, ToNearestEven, "1E+22", "0.5232147853951389454975944733847095", Inexact | Rounded},
+		{"Cos", 16, ToNearestEven, "1.5707963267948966", "1.923132169163975E-17", Inexact | Rounded},
+		{"Cos", 10, ToPositiveInf, "0.001", "0.9999995001", Inexact | Rounded},
+		{"Cos", 16, ToNearestEven, "Inf", "NaN", InvalidOperation},
+		{"Tan", 34, ToNearestEven, "1E+22", "-1.628778225606898878549375936939549", Inexact | Rounded},
+		{"Tan", 10, ToZero, "1.5707963267948966192313216916397514", "2.375376766E+34", Inexact | Rounded},
+		{"Tan", 16, ToNearestEven, "-1E-20", "-1.000000000000000E-20", Inexact | Rounded},
+		{"Sec", 16, ToNearestEven, "1", "1.850815717680926", Inexact | Rounded},
+		{"Sec", 16, ToNearestEven, "-0", "1", 0},
+		{"Csc", 16, ToNearestEven, "-1", "-1.188395105778121", Inexact | Rounded},
+		{"Csc", 16, ToNearestEven, "1E-10", "10000000000.00000", Inexact | Rounded},
+		{"Csc", 16, ToNearestEven, "-0", "-Infinity", DivisionByZero},
+		{"Cot", 16, ToNearestEven, "1", "0.6420926159343307", Inexact | Rounded},
+		{"Cot", 16, ToNearestEven, "1E+50", "0.7769405840252753", Inexact | Rounded},
+		{"Cot", 16, ToNearestEven, "0", "Infinity", DivisionByZero},
+		{"SinDeg", 16, ToNearestEven, "180", "0", 0},
+		{"SinDeg", 16, ToNearestEven, "-180", "-0", 0},
+		{"SinDeg", 16, ToNearestEven, "210", "-0.5", 0},
+		{"SinDeg", 16, ToNearestEven, "-270", "1", 0},
+		{"SinDeg", 16, ToNearestEven, "45", "0.7071067811865475", Inexact | Rounded},
+		{"SinDeg", 16, ToNearestEven, "1E+30000", "-0.9848077530122081", Inexact | Rounded},
+		{"SinDeg", 16, ToNearestEven, "1E-10", "1.745329251994330E-12", Inexact | Rounded},
+		{"CosDeg", 16, ToNearestEven, "90", "0", 0},
+		{"CosDeg", 16, ToNearestEven, "120", "-0.5", 0},
+		{"CosDeg", 16, ToNearestEven, "-360", "1", 0},
+		{"CosDeg", 16, ToNearestEven, "12.345", "0.9768779594329365", Inexact | Rounded},
+		{"TanDeg", 16, ToNearestEven, "135", "-1", 0},
+		{"TanDeg", 16, ToNearestEven, "180", "-0", 0},
+		{"TanDeg", 16, ToNearestEven, "90", "Infinity", DivisionByZero},
+		{"TanDeg", 16, ToNearestEven, "-90", "-Infinity", DivisionByZero},
+		{"TanDeg", 16, ToNearestEven, "60", "1.732050807568877", Inexact | Rounded},
+		{"SinPi", 16, ToNearestEven, "-2", "-0", 0},
+		{"SinPi", 16, ToNearestEven, "1.5", "-1", 0},
+		{"SinPi", 16, ToNearestEven, "0.1", "0.3090169943749474", Inexact | Rounded},
+		{"SinPi", 16, ToNearestEven, "1E+30", "0", 0},
+		{"CosPi", 16, ToNearestEven, "-0.5", "0", 0},
+		{"CosPi", 16, ToNearestEven, "1", "-1", 0},
+		{"CosPi", 16, ToNearestEven, "-0.75", "-0.7071067811865475", Inexact | Rounded},
+		{"TanPi", 16, ToNearestEven, "-0.75", "1", 0},
+		{"TanPi", 16, ToNearestEven, "1.5", "-Infinity", DivisionByZero},
+		{"TanPi", 16, ToNearestEven, "-1", "0", 0},
+		{"TanPi", 16, ToNearestEven, "-Inf", "NaN", InvalidOperation},
+	} {
+		x, _ := new(Big).SetString(tc.x)
+		ctx := Context{Precision: tc.prec, RoundingMode: tc.mode}
+		z := WithContext(ctx)
+		fns[tc.fn](ctx, z, x)
+		if !strEq(z, tc.r) || z.Context.Conditions != tc.cond {
+			t.Fatalf("#%d: %s(%s): wanted (%s, %s), got (%s, %s)",
+				i, tc.fn, tc.x, tc.r, tc.cond, z, z.Context.Conditions)
+		}
+		if tc.cond&InvalidOperation != 0 && z.Payload() == 0 {
+			t.Fatalf("#%d: %s(%s): missing NaN payload", i, tc.fn, tc.x)
+		}
+	}
+
+	ctx := Context{Precision: 16, Options: &ContextOptions{MaxDigits: 1000}}
+	if z := ctx.Sin(WithContext(ctx), New(1, -5000)); z.Context.Conditions&InsufficientStorage == 0 {
+		t.Fatalf("expected InsufficientStorage, got (%s, %s)", z, z.Context.Conditions)
+	}
+	// Without MaxDigits, the reduction is still bounded.
+	if z := Context64.Sin(new(Big), New(1, -1e9)); z.Context.Conditions&InsufficientStorage == 0 {
+		t.Fatalf("expected InsufficientStorage, got (%s, %s)", z, z.Context.Conditions)
+	}
+}
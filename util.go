@@ -24,31 +24,42 @@ func (z *Big) norm() *Big {
 	return z
 }
 
+// lastDigit returns the least significant decimal digit of |x|.
+func lastDigit(x *big.Int) uint64 {
+	// Each word past the first is a multiple of 2**32 or 2**64, both
+	// of which end in 6, as do all of their powers.
+	var d uint64
+	for i, w := range x.Bits() {
+		if i == 0 {
+			d += uint64(w) % 10
+		} else {
+			d += 6 * (uint64(w) % 10)
+		}
+	}
+	return d % 10
+}
+
 // fix check for overflow, underflow, and clamping.
 func (c Context) fix(z *Big) *Big {
 	adj := z.adjusted()
 
 	if adj > c.emax() {
-		prec := c.precision()
-
 		if z.isZero() {
 			z.exp = c.emax()
 			z.Context.Conditions |= Clamped
-			return z
+			return c.foldDown(z)
 		}
 
 		switch m := c.RoundingMode; m {
-		case ToNearestAway, ToNearestEven, ToNearestTowardZero:
+		case ToNearestAway, ToNearestEven, ToNearestTowardZero, AwayFromZero, Stochastic:
 			z.SetInf(z.Signbit())
-		case AwayFromZero:
-			// OK
-		case ToZero:
-			z.exp = c.emax() - prec + 1
+		case ToZero, ZeroFiveUp, ToOdd:
+			c.maxFinite(z)
 		case ToPositiveInf, ToNegativeInf:
 			if m == ToPositiveInf == z.Signbit() {
-				z.exp = c.emax() - prec + 1
+				c.maxFinite(z)
 			} else {
-				z.SetInf(false)
+				z.SetInf(z.Signbit())
 			}
 		}
 		z.Context.Conditions |= Overflow | Inexact | Rounded
@@ -68,19 +79,167 @@ func (c Context) fix(z *Big) *Big {
 
 		z.Context.Conditions |= Subnormal
 		if z.exp < tiny {
-			if c.shiftr(z, uint64(tiny-z.exp)) {
-				z.compact = 1
+			// If z is much smaller than the smallest subnormal,
+			// replace it with a smaller value that rounds the same
+			// way so that rescaling it doesn't build a huge power of
+			// ten. The extra digits keep Stochastic rounding
+			// unbiased to within its threshold.
+			const sticky = 21
+			if z.exp < tiny-z.Precision()-sticky {
+				z.setTriple(1, z.form&signbit, tiny-sticky)
 			}
-			z.exp = tiny
-			z.Context.Conditions |= Underflow
-			if z.isZero() {
+			inexact := z.Context.Conditions & Inexact
+			z.Context.Conditions &^= Inexact
+			c.quantize(z, tiny)
+			if z.Context.Conditions&Inexact != 0 && z.isZero() {
 				z.Context.Conditions |= Clamped
 			}
+			z.Context.Conditions |= inexact
+		}
+		// A subnormal result underflows if it is also inexact,
+		// whether it was rounded above or by the operation that
+		// produced it.
+		if z.Context.Conditions&Inexact != 0 {
+			z.Context.Conditions |= Underflow
 		}
 	}
+	return c.foldDown(z)
+}
+
+// foldDown decreases the exponent of z to etop, padding its
+// coefficient with zeros, if the Context's Clamp is set and the
+// exponent is larger than etop. It returns z.
+func (c Context) foldDown(z *Big) *Big {
+	if !c.Clamp || !z.IsFinite() || c.precision() == UnlimitedPrecision {
+		return z
+	}
+	top := c.etop()
+	if z.exp <= top {
+		return z
+	}
+	c.shiftl(z, uint64(z.exp-top))
+	z.exp = top
+	z.Context.Conditions |= Clamped
 	return z
 }
 
+// maxFinite sets z to the largest finite number with the same
+// sign as z that the Context can represent and returns z.
+func (c Context) maxFinite(z *Big) *Big {
+	if c.precision() == UnlimitedPrecision {
+		// There is no largest finite number.
+		return z.SetInf(z.Signbit())
+	}
+	sign := z.form & signbit
+	maxfor(&z.unscaled, c.precision(), +1)
+	z.exp = c.etop()
+	z.form = finite | sign
+	return z.norm()
+}
+
+// overflow sets z to a value with the sign neg that is too large
+// for the Context, rounds it as if by fix, and returns z.
+func (c Context) overflow(z *Big, neg bool) *Big {
+	z.SetMantScale(1, -(c.emax() + 1))
+	z.SetSignbit(neg)
+	return c.finish(z)
+}
+
+// underflow sets z to a value with the sign neg that is too small
+// for the Context, rounds it as if by fix, and returns z.
+func (c Context) underflow(z *Big, neg bool) *Big {
+	z.SetMantScale(1, -(c.etiny() - 1))
+	z.SetSignbit(neg)
+	return c.finish(z)
+}
+
+// perturb sets z to x + t rounded to the Context's precision, where
+// t is an unknown number with a magnitude less than 10**e that is
+// positive if up is true and negative otherwise. It reports whether
+// it was able to do so, which is only the case if no such t can
+// move x across a rounding boundary.
+//
+// perturb is used for functions like sinh(x) = x + x**3/6 + ... when
+// x is small enough that only the sign of the remaining terms
+// affects the correctly rounded result.
+func (c Context) perturb(z, x *Big, e int, up bool) bool {
+	lim := min(x.exp, x.adjusted()-c.precision()-1)
+	if e >= lim {
+		return false
+	}
+	var t Big
+	t.SetMantScale(1, -(lim - 1))
+	t.SetSignbit(!up)
+	Context{Precision: UnlimitedPrecision}.Add(z, x, &t)
+	c.finish(z)
+	return true
+}
+
+// correctlyRound sets z to the result of f rounded to the
+// Context's precision and returns z.
+//
+// f computes its result in t using ctx, which has the default
+// exponent limits and extra digits of precision, some of which are
+// given by extra to account for cancellation. If the result is too
+// close to a rounding boundary to be correctly rounded, f is called
+// again with more precision. An infinite result is treated as an
+// overflow and a zero result as an underflow.
+//
+// Exact results should be handled before calling correctlyRound,
+// since they might lie on a rounding boundary.
+func (c Context) correctlyRound(z *Big, extra int, f func(ctx Context, t *Big) *Big) *Big {
+	prec := c.precision()
+	ctx := c.dup()
+	ctx.MaxScale = 0
+	ctx.MinScale = 0
+
+	t := getDec(ctx)
+	defer putDec(t)
+	for guard := 10; ; guard *= 2 {
+		ctx.Precision = prec + extra + guard
+		f(ctx, t)
+		if !t.IsFinite() || t.Sign() == 0 || guard >= 160 || roundable(t, prec) {
+			break
+		}
+	}
+	if t.IsInf(0) {
+		return c.overflow(z, t.Signbit())
+	}
+	if t.IsFinite() && t.Sign() == 0 {
+		return c.underflow(z, t.Signbit())
+	}
+	return c.Set(z, t)
+}
+
+// roundable reports whether x, an approximation whose error is at
+// most a few units in its last place, can be correctly rounded to
+// prec digits.
+func roundable(x *Big, prec int) bool {
+	// Ignore the last two digits and check whether the remaining
+	// digits after the first prec digits could be adjacent to a
+	// rounding boundary: 00...0, 99...9, 49...9, or 50...0.
+	k := x.Precision() - prec - 2
+	if k < 2 {
+		return false
+	}
+	var d big.Int
+	if x.isCompact() {
+		d.SetUint64(x.compact)
+	} else {
+		d.Set(&x.unscaled)
+	}
+	d.Quo(&d, arith.BigPow10(2))
+	d.Rem(&d, arith.BigPow10(uint64(k)))
+
+	var b big.Int
+	b.Quo(arith.BigPow10(uint64(k)), big.NewInt(2))
+	if d.Sign() == 0 || d.Cmp(&b) == 0 {
+		return false
+	}
+	d.Add(&d, big.NewInt(1))
+	return d.Cmp(&b) != 0 && d.Cmp(arith.BigPow10(uint64(k))) != 0
+}
+
 // alias returns z if z != x, otherwise a newly-allocated big.Int.
 func alias(z, x *big.Int) *big.Int {
 	if z != x {
@@ -117,6 +276,45 @@ func (z *Big) invalidContext(c Context) bool {
 	return true
 }
 
+// maxDigits returns the maximum number of digits in a coefficient
+// under c's MaxDigits and MaxMemory, or zero if there is no limit.
+func (c Context) maxDigits() int64 {
+	o := c.Options
+	if o == nil {
+		return 0
+	}
+	n := int64(o.MaxDigits)
+	if o.MaxMemory > 0 {
+		// Each byte holds log10(256) ≈ 2.408 digits.
+		m := int64(float64(o.MaxMemory)*2.408) + 1
+		if n <= 0 || m < n {
+			n = m
+		}
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// tooLarge reports whether a coefficient with n digits exceeds
+// c's MaxDigits or MaxMemory.
+func (c Context) tooLarge(n int64) bool {
+	m := c.maxDigits()
+	return m > 0 && n > m
+}
+
+// checkStorage sets z to NaN and raises InsufficientStorage if a
+// coefficient with n digits exceeds c's MaxDigits or MaxMemory. It
+// reports whether z was set.
+func (z *Big) checkStorage(c Context, n int64) bool {
+	if !c.tooLarge(n) {
+		return false
+	}
+	z.setNaN(InsufficientStorage, qnan, storage)
+	return true
+}
+
 // copybits can be useful when we want to allocate a big.Int without calling
 // new or big.Int.Set. For example:
 //
@@ -231,6 +429,55 @@ func getmsd(x *Big, n int) uint64 {
 	return w.Uint64()
 }
 
+// coeff returns the n least significant digits of x's
+// coefficient. x must be finite.
+func (x *Big) coeff(n int) *big.Int {
+	z := new(big.Int)
+	if x.isCompact() {
+		z.SetUint64(x.compact)
+	} else {
+		z.Set(&x.unscaled)
+	}
+	if x.Precision() > n {
+		z.Mod(z, arith.BigPow10(uint64(n)))
+	}
+	return z
+}
+
+// setCoeff sets z to the finite decimal with the coefficient x,
+// sign, and exponent and returns z.
+func (z *Big) setCoeff(x *big.Int, sign form, exp int) *Big {
+	z.unscaled.Set(x)
+	z.exp = exp
+	z.form = finite | sign
+	return z.norm()
+}
+
+// digits returns the number of digits in x's coefficient used by
+// digit-wise operations like Rotate and Shift.
+//
+// It's the Context's precision unless the precision is
+// UnlimitedPrecision, in which case it's the precision of x.
+func (c Context) digits(x *Big) int {
+	if p := c.precision(); p != UnlimitedPrecision {
+		return p
+	}
+	return x.Precision()
+}
+
+// intOperand returns x as an int and reports whether x is an
+// integer with a scale of zero in the range [-n, n].
+func intOperand(x *Big, n int) (int, bool) {
+	if !x.IsFinite() || x.exp != 0 {
+		return 0, false
+	}
+	v, ok := x.Int64()
+	if !ok || v < -int64(n) || v > int64(n) {
+		return 0, false
+	}
+	return int(v), true
+}
+
 var decPool = sync.Pool{
 	New: func() interface{} {
 		return new(Big)